@@ -207,7 +207,7 @@ func getAttributeFormula(key string) string {
 }
 
 func isInCoreCharacteristics(value string, inv *models.Investigator) bool {
-	return slices.Contains(inv.Archetype.CoreCharacteristic, value)
+	return inv.Archetype != nil && slices.Contains(inv.Archetype.CoreCharacteristic, value)
 }
 
 var _ = templruntime.GeneratedTemplate
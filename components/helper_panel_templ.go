@@ -117,7 +117,7 @@ func RecoveryContent(inv *models.Investigator) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		if inv.Archetype.Name != "" {
+		if inv.IsPulp() {
 			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "<div class=\"alert alert-info alert-sm mb-3\"><i class=\"bi bi-stars me-1\"></i> <strong>Pulp Mode:</strong> Enhanced recovery rates apply</div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
@@ -127,7 +127,7 @@ func RecoveryContent(inv *models.Investigator) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		if inv.Archetype.Name != "" {
+		if inv.IsPulp() {
 			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "<option value=\"hours\">Hours</option> ")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
@@ -137,7 +137,7 @@ func RecoveryContent(inv *models.Investigator) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		if inv.Archetype.Name != "" {
+		if inv.IsPulp() {
 			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "<div class=\"recovery-section\"><label class=\"form-label fw-bold\"><i class=\"bi bi-bandaid text-success me-1\"></i>First Aid (Pulp)</label><p class=\"small text-muted mb-2\">Successful First Aid restores 1d6+1 HP (once per injury). Successful Medicine restores 1d6+2 HP additional.</p><button class=\"btn btn-sm btn-outline-success w-100\" onclick=\"HelperPanel.rollFirstAid()\"><i class=\"bi bi-bandaid me-1\"></i>Roll First Aid (1d6+1)</button><div class=\"recovery-result mt-2\" id=\"first-aid-result\"></div></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
@@ -176,7 +176,7 @@ func QuickRulesContent(inv *models.Investigator) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		if inv.Archetype.Name != "" {
+		if inv.IsPulp() {
 			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "<h6 class=\"helper-section-title\">Pulp: Luck Recovery</h6><div class=\"rule-card mb-3\"><p class=\"small mb-1\"><strong>Between Sessions:</strong> Roll 2d6+10 and regain that much Luck</p><button class=\"btn btn-sm btn-outline-primary w-100 mt-2\" onclick=\"HelperPanel.rollLuckRecovery()\"><i class=\"bi bi-clover me-1\"></i>Roll Luck Recovery (2d6+10)</button><div class=\"recovery-result mt-2\" id=\"luck-recovery-result\"></div></div><h6 class=\"helper-section-title\">Pulp: Spend Luck for Sanity</h6><div class=\"rule-card mb-3\"><p class=\"small mb-0\">Spend 10 Luck to recover 1d6 Sanity points</p></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
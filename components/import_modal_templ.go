@@ -29,7 +29,7 @@ func ImportModal() templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div id=\"importModal\" class=\"modal fade\" tabindex=\"-1\" aria-labelledby=\"importModalLabel\" aria-hidden=\"true\"><div class=\"modal-dialog modal-lg\"><div class=\"modal-content\"><div class=\"modal-header\"><h1 class=\"modal-title fs-5\" id=\"importModalLabel\">Import Investigators</h1><button type=\"button\" class=\"btn-close\" data-bs-dismiss=\"modal\" aria-label=\"Close\"></button></div><div class=\"modal-body\"><textarea class=\"form-control modal-textarea\" id=\"importCode\" rows=\"10\" placeholder=\"Paste export code here\"></textarea></div><div class=\"modal-footer\"><button onclick=\"characterUtils.importInvestigators();\" type=\"button\" class=\"btn btn-primary\" hx-swap=\"none\" data-bs-dismiss=\"modal\">Import</button> <button type=\"button\" class=\"btn btn-secondary\" data-bs-dismiss=\"modal\">Cancel</button></div></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div id=\"importModal\" class=\"modal fade\" tabindex=\"-1\" aria-labelledby=\"importModalLabel\" aria-hidden=\"true\"><div class=\"modal-dialog modal-lg\"><div class=\"modal-content\"><div class=\"modal-header\"><h1 class=\"modal-title fs-5\" id=\"importModalLabel\">Import Investigators</h1><button type=\"button\" class=\"btn-close\" data-bs-dismiss=\"modal\" aria-label=\"Close\"></button></div><div class=\"modal-body\"><textarea class=\"form-control modal-textarea\" id=\"importCode\" rows=\"10\" placeholder=\"Paste export code here\"></textarea> <label for=\"importSheet\" class=\"form-label mt-3\">Or upload a filled character sheet</label> <input class=\"form-control\" type=\"file\" id=\"importSheet\" accept=\"application/pdf,.pdf\"></div><div class=\"modal-footer\"><button onclick=\"characterUtils.importInvestigators();\" type=\"button\" class=\"btn btn-primary\" hx-swap=\"none\" data-bs-dismiss=\"modal\">Import</button> <button type=\"button\" class=\"btn btn-secondary\" data-bs-dismiss=\"modal\">Cancel</button></div></div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
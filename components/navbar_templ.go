@@ -8,6 +8,8 @@ package components
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
+import "book-of-shadows/storage"
+
 func Navbar() templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -29,7 +31,35 @@ func Navbar() templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<nav id=\"navbar\" class=\"navbar navbar-expand-lg navbar-light bg-white shadow-sm\"><div class=\"container\"><a class=\"navbar-brand fw-bold\" href=\"/\">CorbittFiles</a> <button class=\"navbar-toggler\" type=\"button\" data-bs-toggle=\"collapse\" data-bs-target=\"#navbarNav\" aria-controls=\"navbarNav\" aria-expanded=\"false\" aria-label=\"Toggle navigation\"><span class=\"navbar-toggler-icon\"></span></button><div class=\"collapse navbar-collapse\" id=\"navbarNav\"><ul class=\"navbar-nav ms-auto\"><li class=\"nav-item\"><a class=\"nav-link\" href=\"/?view=archive\" onclick=\"return App.navigate('/api/investigator', 'archive');\">Archive</a></li><li class=\"nav-item\"><a class=\"nav-link\" href=\"/?view=create\" onclick=\"return App.navigate('/wizard/base/new', 'create');\">Create Investigator</a></li><li class=\"nav-item\"><a class=\"nav-link\" href=\"/?view=random\" onclick=\"return App.navigate('/api/generate/?mode=pulp', 'random');\">Random Investigator</a></li><li class=\"nav-item\"><a class=\"nav-link\" href=\"#\" onclick=\"RulesDrawer.open(); return false;\">Rules</a></li><li class=\"nav-item\"><a class=\"nav-link\" href=\"/keeper\"><i class=\"bi bi-shield-shaded me-1\"></i>Keeper</a></li></ul></div></div></nav>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<nav id=\"navbar\" class=\"navbar navbar-expand-lg navbar-light bg-white shadow-sm\"><div class=\"container\"><a class=\"navbar-brand fw-bold\" href=\"/\">CorbittFiles</a> <button class=\"navbar-toggler\" type=\"button\" data-bs-toggle=\"collapse\" data-bs-target=\"#navbarNav\" aria-controls=\"navbarNav\" aria-expanded=\"false\" aria-label=\"Toggle navigation\"><span class=\"navbar-toggler-icon\"></span></button><div class=\"collapse navbar-collapse\" id=\"navbarNav\"><ul class=\"navbar-nav ms-auto\"><li class=\"nav-item\"><a class=\"nav-link\" href=\"/?view=archive\" onclick=\"return App.navigate('/api/investigator', 'archive');\">Archive</a></li><li class=\"nav-item\"><a class=\"nav-link\" href=\"/?view=create\" onclick=\"return App.navigate('/wizard/base/new', 'create');\">Create Investigator</a></li><li class=\"nav-item dropdown\"><a class=\"nav-link dropdown-toggle\" href=\"#\" id=\"randomDropdown\" role=\"button\" data-bs-toggle=\"dropdown\" aria-expanded=\"false\">Random Investigator</a><ul class=\"dropdown-menu dropdown-menu-end\" aria-labelledby=\"randomDropdown\"><li><h6 class=\"dropdown-header\">Pulp Cthulhu</h6></li><li><a class=\"dropdown-item\" href=\"/?view=random\" onclick=\"return App.navigate('/api/generate/?mode=pulp&era=modern', 'random');\">Modern</a></li><li><a class=\"dropdown-item\" href=\"/?view=random&era=1920s\" onclick=\"return App.navigate('/api/generate/?mode=pulp&era=1920s', 'random');\">1920s</a></li><li><a class=\"dropdown-item\" href=\"/?view=random&era=gaslight\" onclick=\"return App.navigate('/api/generate/?mode=pulp&era=gaslight', 'random');\">Gaslight</a></li><li><hr class=\"dropdown-divider\"></li><li><h6 class=\"dropdown-header\">Classic Call of Cthulhu</h6></li><li><a class=\"dropdown-item\" href=\"/?view=random-classic\" onclick=\"return App.navigate('/api/generate/?mode=classic&era=modern', 'random-classic');\">Modern</a></li><li><a class=\"dropdown-item\" href=\"/?view=random-classic&era=1920s\" onclick=\"return App.navigate('/api/generate/?mode=classic&era=1920s', 'random-classic');\">1920s</a></li><li><a class=\"dropdown-item\" href=\"/?view=random-classic&era=gaslight\" onclick=\"return App.navigate('/api/generate/?mode=classic&era=gaslight', 'random-classic');\">Gaslight</a></li></ul></li><li class=\"nav-item\"><a class=\"nav-link\" href=\"#\" onclick=\"RulesDrawer.open(); return false;\">Rules</a></li><li class=\"nav-item\"><a class=\"nav-link\" href=\"/keeper\"><i class=\"bi bi-shield-shaded me-1\"></i>Keeper</a></li>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if user := storage.UserFromContext(ctx); user != nil {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<li class=\"nav-item\"><span class=\"nav-link text-muted\"><i class=\"bi bi-person-circle me-1\"></i>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var2 string
+			templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(user.Username)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/navbar.templ`, Line: 42, Col: 117}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "</span></li><li class=\"nav-item\"><a class=\"nav-link\" href=\"#\" onclick=\"return Auth.logout();\">Log Out</a></li>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		} else {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "<li class=\"nav-item\"><a class=\"nav-link\" href=\"/login\">Log In</a></li>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "</ul></div></div></nav>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -133,7 +133,7 @@ func renderOccupationOptions(inv *models.Investigator) templ.Component {
 		ctx = templ.ClearChildren(ctx)
 		if inv != nil && inv.Archetype != nil && inv.Archetype.Name != "" {
 			for _, suggestedOcc := range inv.Archetype.SuggestedOccupations {
-				if occEntity, exists := models.Occupations[suggestedOcc]; exists {
+				if occEntity, exists := models.Occupations[suggestedOcc]; exists && occEntity.AvailableIn(selectedEra(inv)) {
 					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "<option value=\"")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
@@ -193,8 +193,8 @@ func renderOccupationOptions(inv *models.Investigator) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			for occ := range models.OccupationsList {
-				occEntity, _ := models.Occupations[models.OccupationsList[occ]]
+			for _, occName := range models.OccupationsForEra(selectedEra(inv)) {
+				occEntity := models.Occupations[occName]
 				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, " ")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
@@ -246,8 +246,8 @@ func renderOccupationOptions(inv *models.Investigator) templ.Component {
 				}
 			}
 		} else {
-			for occ := range models.OccupationsList {
-				occEntity, _ := models.Occupations[models.OccupationsList[occ]]
+			for _, occName := range models.OccupationsForEra(selectedEra(inv)) {
+				occEntity := models.Occupations[occName]
 				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "<option value=\"")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
@@ -307,7 +307,8 @@ func isOccupationSuggested(occupationName string, suggestedOccupations []string)
 }
 
 func getOccupationContainerStyle(inv *models.Investigator) string {
-	if inv != nil && inv.Archetype != nil && inv.Archetype.Name != "" {
+	// Classic investigators have no archetype to pick first
+	if inv != nil && (!inv.IsPulp() || inv.Archetype != nil && inv.Archetype.Name != "") {
 		return "display: block;"
 	}
 	return "display: none;"
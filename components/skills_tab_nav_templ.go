@@ -8,7 +8,13 @@ package components
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
-func SkillsTabNav() templ.Component {
+import (
+	"book-of-shadows/models"
+	"strconv"
+)
+
+// SkillsTabNav starts on the archetype skills, or on the occupation skills for Classic investigators
+func SkillsTabNav(investigator *models.Investigator) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -29,7 +35,62 @@ func SkillsTabNav() templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"mb-4\"><ul class=\"nav nav-pills nav-fill\" id=\"skillsTabs\" role=\"tablist\"><li class=\"nav-item\" role=\"presentation\"><button class=\"nav-link active fw-medium py-3\" id=\"archetype-tab\" data-bs-toggle=\"tab\" data-bs-target=\"#archetype-skills\" type=\"button\" role=\"tab\" aria-controls=\"archetype-skills\" aria-selected=\"true\"><i class=\"bi bi-person-badge me-2\"></i>Archetype Skills</button></li><li class=\"nav-item\" role=\"presentation\"><button class=\"nav-link fw-medium py-3\" id=\"occupation-tab\" data-bs-toggle=\"tab\" data-bs-target=\"#occupation-skills\" type=\"button\" role=\"tab\" aria-controls=\"occupation-skills\" aria-selected=\"false\" disabled><i class=\"bi bi-briefcase me-2\"></i>Occupation Skills</button></li><li class=\"nav-item\" role=\"presentation\"><button class=\"nav-link fw-medium py-3\" id=\"general-tab\" data-bs-toggle=\"tab\" data-bs-target=\"#general-skills\" type=\"button\" role=\"tab\" aria-controls=\"general-skills\" aria-selected=\"false\" disabled><i class=\"bi bi-list-check me-2\"></i>General Skills</button></li></ul></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"mb-4\"><ul class=\"nav nav-pills nav-fill\" id=\"skillsTabs\" role=\"tablist\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if investigator.Archetype != nil {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<li class=\"nav-item\" role=\"presentation\"><button class=\"nav-link active fw-medium py-3\" id=\"archetype-tab\" data-bs-toggle=\"tab\" data-bs-target=\"#archetype-skills\" type=\"button\" role=\"tab\" aria-controls=\"archetype-skills\" aria-selected=\"true\"><i class=\"bi bi-person-badge me-2\"></i>Archetype Skills</button></li>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<li class=\"nav-item\" role=\"presentation\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var2 = []any{"nav-link fw-medium py-3", templ.KV("active", investigator.Archetype == nil)}
+		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var2...)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "<button class=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var3 string
+		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var2).String())
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/skills_tab_nav.templ`, Line: 1, Col: 0}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "\" id=\"occupation-tab\" data-bs-toggle=\"tab\" data-bs-target=\"#occupation-skills\" type=\"button\" role=\"tab\" aria-controls=\"occupation-skills\" aria-selected=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var4 string
+		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.FormatBool(investigator.Archetype == nil))
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/skills_tab_nav.templ`, Line: 37, Col: 84}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if investigator.Archetype != nil {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, " disabled")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "><i class=\"bi bi-briefcase me-2\"></i>Occupation Skills</button></li><li class=\"nav-item\" role=\"presentation\"><button class=\"nav-link fw-medium py-3\" id=\"general-tab\" data-bs-toggle=\"tab\" data-bs-target=\"#general-skills\" type=\"button\" role=\"tab\" aria-controls=\"general-skills\" aria-selected=\"false\" disabled><i class=\"bi bi-list-check me-2\"></i>General Skills</button></li></ul></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
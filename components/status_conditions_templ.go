@@ -81,7 +81,69 @@ func StatusConditions(inv *models.Investigator) templ.Component {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, " title=\"Dying_Chk\" data-stat=\"Dying\" onclick=\"characterUtils.recalculateSheetValues(this, 'base'); CharacterSheet.updateStatusClasses(); this.blur()\"> <label class=\"form-check-label\" for=\"dying\" style=\"cursor: pointer;\">Dying</label></div></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, " title=\"Dying_Chk\" data-stat=\"Dying\" onclick=\"characterUtils.recalculateSheetValues(this, 'base'); CharacterSheet.updateStatusClasses(); this.blur()\"> <label class=\"form-check-label\" for=\"dying\" style=\"cursor: pointer;\">Dying</label></div></div><div class=\"input-group input-group-sm mt-3\" style=\"max-width: 22rem;\"><span class=\"input-group-text\">SAN loss</span> <input type=\"text\" class=\"form-control\" id=\"san-loss\" placeholder=\"1/1D6\" title=\"Sanity lost on a success/failure, e.g. 0/1D4 or 1/1D10\"> <button type=\"button\" class=\"btn btn-outline-secondary\" data-investigator-id=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var2 string
+		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(inv.ID)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/status_conditions.templ`, Line: 101, Col: 49}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "\" onclick=\"CharacterSheet.applySanityLoss(this.dataset.investigatorId)\"><i class=\"bi bi-dice-5 me-1\"></i>Roll Sanity</button></div><div class=\"d-flex flex-wrap align-items-center gap-2 mt-2\"><div class=\"input-group input-group-sm\" style=\"max-width: 22rem;\"><span class=\"input-group-text\">Damage</span> <input type=\"text\" class=\"form-control\" id=\"damage-taken\" placeholder=\"1D8+1D4\" title=\"Damage of a single hit, a number or dice such as 1D6+2\"> <button type=\"button\" class=\"btn btn-outline-danger\" data-investigator-id=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var3 string
+		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(inv.ID)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/status_conditions.templ`, Line: 120, Col: 53}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "\" onclick=\"CharacterSheet.applyDamage(this.dataset.investigatorId)\"><i class=\"bi bi-heartbreak me-1\"></i>Take Hit</button></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if inv.IsPulp() {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "<div class=\"form-check\"><input class=\"form-check-input\" type=\"checkbox\" id=\"damage-spend-luck\"> <label class=\"form-check-label small\" for=\"damage-spend-luck\">Spend 5 Luck to ignore a major wound</label></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "<div class=\"btn-group btn-group-sm\"><button type=\"button\" class=\"btn btn-outline-success\" data-investigator-id=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var4 string
+		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(inv.ID)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/status_conditions.templ`, Line: 136, Col: 53}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "\" onclick=\"CharacterSheet.heal(this.dataset.investigatorId, 'First Aid')\"><i class=\"bi bi-bandaid me-1\"></i>First Aid</button> <button type=\"button\" class=\"btn btn-outline-success\" data-investigator-id=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var5 string
+		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(inv.ID)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `components/status_conditions.templ`, Line: 144, Col: 53}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "\" onclick=\"CharacterSheet.heal(this.dataset.investigatorId, 'Medicine')\"><i class=\"bi bi-capsule me-1\"></i>Medicine</button></div></div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -35,7 +35,7 @@ func TalentsSection(inv *models.Investigator) templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		if inv.Archetype.Name != "" {
+		if inv.IsPulp() && inv.Archetype != nil && inv.Archetype.Name != "" {
 			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"card shadow-sm mb-4\" style=\"border-radius: 1rem; border: none;\"><div class=\"card-header d-flex align-items-center justify-content-between p-3 card-header-custom\"><div class=\"d-flex align-items-center\"><i class=\"bi bi-stars me-2 card-header-icon\"></i><h4 class=\"section-title\">Pulp Talents</h4><span class=\"badge ms-2 talent-count-badge\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
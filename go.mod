@@ -8,4 +8,5 @@ require (
 	github.com/a-h/templ v0.3.865
 	github.com/google/uuid v1.6.0
 	github.com/mattn/go-sqlite3 v1.14.24
+	golang.org/x/crypto v0.31.0
 )
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -11,10 +13,14 @@ type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
 	Cookie   CookieConfig
+	Storage  StorageConfig
+	Auth     AuthConfig
 }
 
 // ServerConfig contains server-specific configuration
 type ServerConfig struct {
+	// Environment is "production" when deployed, which makes missing secrets fatal
+	Environment  string
 	Port         string
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
@@ -30,17 +36,52 @@ type DatabaseConfig struct {
 
 // CookieConfig contains cookie-specific configuration
 type CookieConfig struct {
-	Prefix   string
-	MaxAge   int
-	HttpOnly bool
-	Secure   bool
-	SameSite int
+	Prefix       string
+	OwnerName    string
+	MaxAge       int
+	MaxTotalSize int
+	HttpOnly     bool
+	Secure       bool
+	SameSite     int
+	// SigningKeys authenticate investigator cookies; the first key signs, all keys verify
+	SigningKeys []string
+	// EncryptionKeys optionally encrypt investigator cookies; the first key encrypts, all keys decrypt
+	EncryptionKeys []string
+	// AllowUnsigned accepts cookies written before signing was enabled, which are sealed on
+	// their next write. On while players move over, to be turned off once their cookies are sealed
+	AllowUnsigned bool
+}
+
+// Environments the server runs in
+const (
+	EnvironmentDevelopment = "development"
+	EnvironmentProduction  = "production"
+)
+
+// Investigator storage backends
+const (
+	StorageBackendCookie = "cookie"
+	StorageBackendSQLite = "sqlite"
+)
+
+// StorageConfig contains investigator storage configuration
+type StorageConfig struct {
+	Backend string
+}
+
+// AuthConfig contains user account and login session configuration
+type AuthConfig struct {
+	SessionCookie     string
+	SessionTTL        time.Duration
+	Secure            bool
+	MinPasswordLength int
 }
 
 // New creates a new Config instance with values from environment variables or defaults
 func New() *Config {
 	return &Config{
 		Server: ServerConfig{
+			Environment:  getEnv("APP_ENV", EnvironmentDevelopment),
 			Port:         getEnv("SERVER_PORT", "8080"),
 			ReadTimeout:  getDurationEnv("SERVER_READ_TIMEOUT", 15*time.Second),
 			WriteTimeout: getDurationEnv("SERVER_WRITE_TIMEOUT", 15*time.Second),
@@ -52,13 +93,40 @@ func New() *Config {
 			RetentionPeriod: getDurationEnv("DB_RETENTION_PERIOD", 24*time.Hour),
 		},
 		Cookie: CookieConfig{
-			Prefix:   getEnv("COOKIE_PREFIX", "investigator"),
-			MaxAge:   getIntEnv("COOKIE_MAX_AGE", 3600*24*30), // 30 days
-			HttpOnly: getBoolEnv("COOKIE_HTTP_ONLY", true),
-			Secure:   getBoolEnv("COOKIE_SECURE", true),
-			SameSite: getIntEnv("COOKIE_SAME_SITE", 3), // http.SameSiteStrictMode = 3
+			Prefix:         getEnv("COOKIE_PREFIX", "investigator"),
+			OwnerName:      getEnv("COOKIE_OWNER_NAME", "owner"),
+			MaxAge:         getIntEnv("COOKIE_MAX_AGE", 3600*24*30),     // 30 days
+			MaxTotalSize:   getIntEnv("COOKIE_MAX_TOTAL_SIZE", 32*1024), // keeps the Cookie header under common proxy limits
+			HttpOnly:       getBoolEnv("COOKIE_HTTP_ONLY", true),
+			Secure:         getBoolEnv("COOKIE_SECURE", true),
+			SameSite:       getIntEnv("COOKIE_SAME_SITE", 3), // http.SameSiteStrictMode = 3
+			SigningKeys:    getListEnv("COOKIE_SIGNING_KEYS"),
+			EncryptionKeys: getListEnv("COOKIE_ENCRYPTION_KEYS"),
+			AllowUnsigned:  getBoolEnv("COOKIE_ALLOW_UNSIGNED", true),
+		},
+		Storage: StorageConfig{
+			Backend: getEnv("STORAGE_BACKEND", StorageBackendCookie),
 		},
+		Auth: AuthConfig{
+			SessionCookie:     getEnv("AUTH_SESSION_COOKIE", "session"),
+			SessionTTL:        getDurationEnv("AUTH_SESSION_TTL", 30*24*time.Hour),
+			Secure:            getBoolEnv("AUTH_COOKIE_SECURE", true),
+			MinPasswordLength: getIntEnv("AUTH_MIN_PASSWORD_LENGTH", 8),
+		},
+	}
+}
+
+// Validate reports configuration the server cannot safely run with
+func (c *Config) Validate() error {
+	if c.Server.Environment != EnvironmentProduction {
+		return nil
+	}
+	cookieBackend := c.Storage.Backend == StorageBackendCookie || c.Storage.Backend == ""
+	if cookieBackend && len(c.Cookie.SigningKeys) == 0 {
+		// An ephemeral key would drop every player's investigators on each restart
+		return fmt.Errorf("COOKIE_SIGNING_KEYS must be set in production")
 	}
+	return nil
 }
 
 // getEnv gets an environment variable or returns a default value
@@ -89,6 +157,17 @@ func getBoolEnv(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// getListEnv gets a comma separated environment variable as a list of non-empty values
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 // getDurationEnv gets an environment variable as time.Duration or returns a default value
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
@@ -0,0 +1,176 @@
+// Package dice parses and rolls Call of Cthulhu dice expressions such as "3D6*5",
+// "(2D6+6)*5" or "1D10+1D4+DB". Percentile dice take bonus and penalty dice as a
+// suffix, "D100B1" rolls with one bonus die and "D100P2" with two penalty dice.
+package dice
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// Limits that keep a single expression cheap to roll
+const (
+	maxExpressionLength = 100
+	maxDice             = 100
+	maxSides            = 1000
+	maxExtraDice        = 2
+)
+
+// Vars maps the names used in an expression, such as "DB", to the expressions they stand for
+type Vars map[string]string
+
+// Roll is the outcome of one dice term of an expression
+type Roll struct {
+	Term  string `json:"term"`           // The term as written, e.g. "3D6" or "D100B1"
+	Dice  []int  `json:"dice"`           // Every die rolled, the units die for percentile rolls
+	Tens  []int  `json:"tens,omitempty"` // Tens dice of a percentile roll, one more per bonus or penalty die
+	Total int    `json:"total"`          // Sum of the dice, or the kept percentile result
+}
+
+// Result is the outcome of rolling a whole expression
+type Result struct {
+	Expression string `json:"expression"`
+	Total      int    `json:"total"`
+	Rolls      []Roll `json:"rolls"`
+}
+
+// Expression is a parsed dice expression that can be rolled any number of times
+type Expression struct {
+	source string
+	root   node
+}
+
+// Parse parses a dice expression
+func Parse(source string) (*Expression, error) {
+	if len(source) > maxExpressionLength {
+		return nil, fmt.Errorf("expression is longer than %d characters", maxExpressionLength)
+	}
+	p := &parser{source: source}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	root, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return &Expression{source: strings.TrimSpace(source), root: root}, nil
+}
+
+// MustParse parses an expression known to be valid and panics otherwise
+func MustParse(source string) *Expression {
+	e, err := Parse(source)
+	if err != nil {
+		panic(fmt.Sprintf("dice: %q: %v", source, err))
+	}
+	return e
+}
+
+// String returns the expression as it was written
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Variables returns the names of the variables the expression uses
+func (e *Expression) Variables() []string {
+	return variables(e.root)
+}
+
+// Roll rolls the expression. Every variable it names must be in vars, and the
+// expressions variables stand for may not name variables themselves.
+func (e *Expression) Roll(r *rand.Rand, vars Vars) (Result, error) {
+	resolved := make(map[string]node)
+	for _, name := range variables(e.root) {
+		value, ok := vars[name]
+		if !ok {
+			return Result{}, fmt.Errorf("unknown variable %s", name)
+		}
+		v, err := Parse(value)
+		if err != nil {
+			return Result{}, fmt.Errorf("variable %s: %v", name, err)
+		}
+		if len(variables(v.root)) > 0 {
+			return Result{}, fmt.Errorf("variable %s refers to another variable", name)
+		}
+		resolved[name] = v.root
+	}
+
+	result := Result{Expression: e.source, Rolls: []Roll{}}
+	result.Total = e.root.eval(&roller{r: r, vars: resolved, result: &result})
+	return result, nil
+}
+
+// Total rolls an expression without variables and returns its total
+func (e *Expression) Total(r *rand.Rand) int {
+	result, err := e.Roll(r, nil)
+	if err != nil {
+		panic(fmt.Sprintf("dice: %q: %v", e.source, err))
+	}
+	return result.Total
+}
+
+// Max returns the total of an expression without variables with every die rolling its highest
+func (e *Expression) Max() int {
+	if names := variables(e.root); len(names) > 0 {
+		panic(fmt.Sprintf("dice: %q: unknown variable %s", e.source, names[0]))
+	}
+	return e.root.eval(&roller{maximize: true})
+}
+
+// Percentile rolls 1D100 with bonus and penalty dice, which cancel each other out
+func Percentile(r *rand.Rand, bonus, penalty int) Roll {
+	return (&roller{r: r}).percentile(bonus - penalty)
+}
+
+// roller carries the random source and collects the rolls of one evaluation
+type roller struct {
+	r        *rand.Rand
+	vars     map[string]node
+	result   *Result
+	maximize bool // Every die rolls its highest instead of at random
+}
+
+func (rl *roller) record(roll Roll) {
+	if rl.result != nil {
+		rl.result.Rolls = append(rl.result.Rolls, roll)
+	}
+}
+
+// die rolls a single die with the given number of sides
+func (rl *roller) die(sides int) int {
+	if rl.maximize {
+		return sides
+	}
+	return rl.r.Intn(sides) + 1
+}
+
+// percentile rolls 1D100 keeping the best tens die for a positive extra and the worst
+// for a negative one. A tens of 00 with a units of 0 reads as 100.
+func (rl *roller) percentile(extra int) Roll {
+	extra = max(-maxExtraDice, min(maxExtraDice, extra))
+	if extra == 0 || rl.maximize {
+		total := rl.die(100)
+		return Roll{Term: "D100", Dice: []int{total}, Total: total}
+	}
+	units := rl.r.Intn(10)
+	count := 1 + max(extra, -extra)
+	tens := make([]int, count)
+	total := 0
+	for i := range tens {
+		tens[i] = rl.r.Intn(10) * 10
+		value := tens[i] + units
+		if value == 0 {
+			value = 100
+		}
+		if i == 0 || extra > 0 && value < total || extra < 0 && value > total {
+			total = value
+		}
+	}
+	return Roll{Term: "D100", Dice: []int{units}, Tens: tens, Total: total}
+}
@@ -0,0 +1,160 @@
+package dice
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	valid := []string{"3D6*5", "(2D6+6)*5", "1d10+1d4+DB", "D%", "D100B1", "1D100P2", "-1", " 2D6 / 2 "}
+	for _, expr := range valid {
+		if _, err := Parse(expr); err != nil {
+			t.Errorf("expected %q to parse, got %v", expr, err)
+		}
+	}
+
+	invalid := []string{"", "3D", "3D1", "0D6", "101D6", "2D6+", "(1D6", "1D6)", "3D6B1", "D100B3", "4/0", "1D6 $ 2"}
+	for _, expr := range invalid {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("expected %q to fail to parse", expr)
+		}
+	}
+}
+
+func TestRoll(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	tests := []struct {
+		expr     string
+		min, max int
+		rolls    int
+	}{
+		{"3D6*5", 15, 90, 1},
+		{"(2D6+6)*5", 40, 90, 1},
+		{"1D10+1D4", 2, 14, 2},
+		{"D%", 1, 100, 1},
+		{"-1D4", -4, -1, 1},
+		{"7", 7, 7, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e := MustParse(tt.expr)
+			for i := 0; i < 200; i++ {
+				result, err := e.Roll(r, nil)
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if result.Total < tt.min || result.Total > tt.max {
+					t.Fatalf("expected a total between %d and %d, got %d", tt.min, tt.max, result.Total)
+				}
+				if len(result.Rolls) != tt.rolls {
+					t.Fatalf("expected %d rolls in the breakdown, got %d", tt.rolls, len(result.Rolls))
+				}
+			}
+		})
+	}
+
+	t.Run("breaks down every die", func(t *testing.T) {
+		result, _ := MustParse("3D6").Roll(r, nil)
+		roll := result.Rolls[0]
+		sum := 0
+		for _, die := range roll.Dice {
+			sum += die
+		}
+		if roll.Term != "3D6" || len(roll.Dice) != 3 || sum != roll.Total || sum != result.Total {
+			t.Errorf("expected three dice adding up to the total, got %+v", roll)
+		}
+	})
+}
+
+func TestMax(t *testing.T) {
+	tests := map[string]int{"1D6": 6, "1D4+1": 5, "(2D6+6)*5": 90, "D100B1": 100, "2": 2}
+	for expr, want := range tests {
+		if got := MustParse(expr).Max(); got != want {
+			t.Errorf("expected the maximum of %q to be %d, got %d", expr, want, got)
+		}
+	}
+}
+
+func TestRollVariables(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	e := MustParse("1D10+DB")
+
+	t.Run("rolls the variable's expression", func(t *testing.T) {
+		result, err := e.Roll(r, Vars{"DB": "+1D4"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(result.Rolls) != 2 || result.Rolls[1].Term != "1D4" {
+			t.Errorf("expected the damage bonus die in the breakdown, got %+v", result.Rolls)
+		}
+	})
+
+	t.Run("adds a flat variable", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			result, _ := e.Roll(r, Vars{"DB": "-2"})
+			if result.Total < -1 || result.Total > 8 {
+				t.Fatalf("expected a total between -1 and 8, got %d", result.Total)
+			}
+		}
+	})
+
+	for name, vars := range map[string]Vars{
+		"unknown variable":   nil,
+		"invalid variable":   {"DB": "1D"},
+		"recursive variable": {"DB": "DB"},
+	} {
+		t.Run("rejects "+name, func(t *testing.T) {
+			if _, err := e.Roll(r, vars); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 500; i++ {
+		bonus := Percentile(r, 2, 0)
+		if len(bonus.Tens) != 3 || len(bonus.Dice) != 1 {
+			t.Fatalf("expected three tens dice and a units die, got %+v", bonus)
+		}
+		for _, tens := range bonus.Tens {
+			value := tens + bonus.Dice[0]
+			if value == 0 {
+				value = 100
+			}
+			if value < bonus.Total {
+				t.Fatalf("expected the bonus roll to keep the lowest result, got %+v", bonus)
+			}
+		}
+
+		penalty := Percentile(r, 0, 1)
+		for _, tens := range penalty.Tens {
+			value := tens + penalty.Dice[0]
+			if value == 0 {
+				value = 100
+			}
+			if value > penalty.Total {
+				t.Fatalf("expected the penalty roll to keep the highest result, got %+v", penalty)
+			}
+		}
+	}
+
+	t.Run("bonus and penalty dice cancel out", func(t *testing.T) {
+		roll := Percentile(r, 1, 1)
+		if len(roll.Tens) != 0 || roll.Total < 1 || roll.Total > 100 {
+			t.Errorf("expected a plain percentile roll, got %+v", roll)
+		}
+	})
+}
+
+func TestRollIsReproducible(t *testing.T) {
+	e := MustParse("1D10+1D4+3D6*5")
+	first, _ := e.Roll(rand.New(rand.NewSource(1234)), nil)
+	second, _ := e.Roll(rand.New(rand.NewSource(1234)), nil)
+	if first.Total != second.Total {
+		t.Errorf("expected the same seed to roll the same total, got %d and %d", first.Total, second.Total)
+	}
+}
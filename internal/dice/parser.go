@@ -0,0 +1,314 @@
+package dice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// node is a part of a parsed expression
+type node interface {
+	eval(rl *roller) int
+}
+
+type numberNode int
+
+func (n numberNode) eval(*roller) int {
+	return int(n)
+}
+
+// diceNode rolls count dice of the given sides. Percentile dice may carry bonus
+// (positive extra) or penalty (negative extra) dice.
+type diceNode struct {
+	term  string
+	count int
+	sides int
+	extra int
+}
+
+func (n diceNode) eval(rl *roller) int {
+	if n.extra != 0 {
+		roll := rl.percentile(n.extra)
+		roll.Term = n.term
+		rl.record(roll)
+		return roll.Total
+	}
+	roll := Roll{Term: n.term, Dice: make([]int, n.count)}
+	for i := range roll.Dice {
+		roll.Dice[i] = rl.die(n.sides)
+		roll.Total += roll.Dice[i]
+	}
+	rl.record(roll)
+	return roll.Total
+}
+
+type variableNode string
+
+func (n variableNode) eval(rl *roller) int {
+	return rl.vars[string(n)].eval(rl)
+}
+
+type negateNode struct {
+	operand node
+}
+
+func (n negateNode) eval(rl *roller) int {
+	return -n.operand.eval(rl)
+}
+
+type binaryNode struct {
+	op          byte
+	left, right node
+}
+
+func (n binaryNode) eval(rl *roller) int {
+	left, right := n.left.eval(rl), n.right.eval(rl)
+	switch n.op {
+	case '+':
+		return left + right
+	case '-':
+		return left - right
+	case '*':
+		return left * right
+	default:
+		if right == 0 {
+			return 0
+		}
+		return left / right
+	}
+}
+
+// variables returns the names of the variables an expression uses
+func variables(n node) []string {
+	switch n := n.(type) {
+	case variableNode:
+		return []string{string(n)}
+	case negateNode:
+		return variables(n.operand)
+	case binaryNode:
+		return append(variables(n.left), variables(n.right)...)
+	default:
+		return nil
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenDice
+	tokenVariable
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// parser is a recursive descent parser over the grammar
+//
+//	expression = term { ("+" | "-") term }
+//	term       = unary { ("*" | "/") unary }
+//	unary      = ("+" | "-") unary | primary
+//	primary    = number | dice | variable | "(" expression ")"
+type parser struct {
+	source string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) tokenize() error {
+	s := strings.ToUpper(p.source)
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case strings.ContainsRune("+-*/()", c):
+			p.tokens = append(p.tokens, token{tokenOperator, string(c)})
+			i++
+		case unicode.IsDigit(c) || c == 'D' && i+1 < len(s) && (isDigit(s[i+1]) || s[i+1] == '%'):
+			// A number, or a dice term such as 3D6, D%, or D100B1
+			start := i
+			for i < len(s) && isDigit(s[i]) {
+				i++
+			}
+			if i < len(s) && s[i] == 'D' && i+1 < len(s) && (isDigit(s[i+1]) || s[i+1] == '%') {
+				i++
+				if s[i] == '%' {
+					i++
+				}
+				for i < len(s) && isDigit(s[i]) {
+					i++
+				}
+				if i < len(s) && (s[i] == 'B' || s[i] == 'P') {
+					i++
+					for i < len(s) && isDigit(s[i]) {
+						i++
+					}
+				}
+				p.tokens = append(p.tokens, token{tokenDice, s[start:i]})
+			} else {
+				p.tokens = append(p.tokens, token{tokenNumber, s[start:i]})
+			}
+		case unicode.IsLetter(c):
+			start := i
+			for i < len(s) && unicode.IsLetter(rune(s[i])) {
+				i++
+			}
+			p.tokens = append(p.tokens, token{tokenVariable, s[start:i]})
+		default:
+			return fmt.Errorf("unexpected character %q", c)
+		}
+	}
+	return nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (p *parser) peekOperator(ops string) (byte, bool) {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenOperator && strings.Contains(ops, p.tokens[p.pos].text) {
+		return p.tokens[p.pos].text[0], true
+	}
+	return 0, false
+}
+
+func (p *parser) expression() (node, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.peekOperator("+-")
+		if !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) term() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.peekOperator("*/")
+		if !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		if n, ok := right.(numberNode); op == '/' && ok && n == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) unary() (node, error) {
+	op, ok := p.peekOperator("+-")
+	if !ok {
+		return p.primary()
+	}
+	p.pos++
+	operand, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	if op == '+' {
+		return operand, nil
+	}
+	return negateNode{operand: operand}, nil
+}
+
+func (p *parser) primary() (node, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("expression ends unexpectedly")
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	switch tok.kind {
+	case tokenNumber:
+		n, err := strconv.Atoi(tok.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", tok.text)
+		}
+		return numberNode(n), nil
+	case tokenDice:
+		return parseDice(tok.text)
+	case tokenVariable:
+		return variableNode(tok.text), nil
+	}
+	if tok.text != "(" {
+		return nil, fmt.Errorf("unexpected %q", tok.text)
+	}
+	inner, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.peekOperator(")"); !ok {
+		return nil, fmt.Errorf("missing closing parenthesis")
+	}
+	p.pos++
+	return inner, nil
+}
+
+// parseDice parses a dice term such as "3D6", "D%" or "D100P2"
+func parseDice(text string) (node, error) {
+	countText, rest, _ := strings.Cut(text, "D")
+	n := diceNode{term: text, count: 1}
+	if countText != "" {
+		count, err := strconv.Atoi(countText)
+		if err != nil || count < 1 || count > maxDice {
+			return nil, fmt.Errorf("%s: dice count must be between 1 and %d", text, maxDice)
+		}
+		n.count = count
+	}
+
+	sidesText, extraText := rest, ""
+	sign := 0
+	if i := strings.IndexAny(rest, "BP"); i >= 0 {
+		sidesText, extraText = rest[:i], rest[i+1:]
+		sign = 1
+		if rest[i] == 'P' {
+			sign = -1
+		}
+	}
+	if sidesText == "%" {
+		n.sides = 100
+	} else {
+		sides, err := strconv.Atoi(sidesText)
+		if err != nil || sides < 2 || sides > maxSides {
+			return nil, fmt.Errorf("%s: dice must have between 2 and %d sides", text, maxSides)
+		}
+		n.sides = sides
+	}
+
+	if sign != 0 {
+		if n.sides != 100 || n.count != 1 {
+			return nil, fmt.Errorf("%s: only a single D100 takes bonus or penalty dice", text)
+		}
+		extra := 1
+		if extraText != "" {
+			var err error
+			if extra, err = strconv.Atoi(extraText); err != nil || extra < 1 || extra > maxExtraDice {
+				return nil, fmt.Errorf("%s: at most %d bonus or penalty dice", text, maxExtraDice)
+			}
+		}
+		n.extra = sign * extra
+	}
+	return n, nil
+}
@@ -17,6 +17,10 @@ var (
 	ErrCookieNotFound  = errors.New("cookie not found")
 	ErrCookieTooLarge  = errors.New("cookie size exceeds limit")
 	ErrInvalidCookie   = errors.New("invalid cookie format")
+	ErrNoCookieSession = errors.New("no cookie session in context")
+
+	// Account errors
+	ErrInvalidCredentials = errors.New("invalid username or password")
 
 	// Investigator errors
 	ErrInvalidAttribute = errors.New("invalid attribute")
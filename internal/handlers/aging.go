@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/internal/validation"
+	"book-of-shadows/models"
+	"book-of-shadows/storage"
+)
+
+// AgingRequest is the body of an aging request
+type AgingRequest struct {
+	Deductions map[string]int `json:"deductions"` // Points to take from each characteristic, spread evenly when empty
+}
+
+// AgingResponse is the aging applied with a line describing it
+type AgingResponse struct {
+	models.AgingReport
+	Summary string `json:"summary"`
+}
+
+// ApplyAging applies the modifiers of an investigator's age to their characteristics once
+// they are rolled, and recalculates everything that depends on them
+func (h *Handler) ApplyAging(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) == 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing investigator ID", nil))
+		return
+	}
+	id := params[0]
+
+	var req AgingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid JSON", err))
+		return
+	}
+	defer r.Body.Close()
+
+	ctx := r.Context()
+	ownerID := storage.OwnerFromContext(ctx)
+	investigator, err := h.store.GetInvestigator(ctx, ownerID, id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	if investigator.Aging != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusConflict, "Aging was already applied", nil))
+		return
+	}
+
+	snapshot, err := investigator.ToJSON()
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	violations := validation.Check(investigator)
+
+	report, err := investigator.ApplyAging(newRand(), req.Deductions)
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Cannot apply aging: "+err.Error(), err))
+		return
+	}
+	h.recalculateDependentAttributes(investigator)
+
+	if err := h.saveInvestigator(ctx, ownerID, id, investigator, violations); err != nil {
+		h.respondError(w, err)
+		return
+	}
+	h.recordRevision(ctx, id, "aging", "Age", nil, report, snapshot)
+
+	h.respondJSON(w, http.StatusOK, AgingResponse{AgingReport: report, Summary: report.Summary(investigator.Attributes)})
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"book-of-shadows/models"
+)
+
+// agingInvestigator stores a Classic investigator of the given age whose characteristics
+// are all 50 and who has not aged yet
+func agingInvestigator(store *MockStore, age int) *models.Investigator {
+	inv := models.RandomInvestigator(models.Classic, models.Modern)
+	inv.Age = age
+	inv.Aging = nil
+	for _, key := range []string{models.AttrStrength, models.AttrConstitution, models.AttrDexterity, models.AttrSize,
+		models.AttrAppearance, models.AttrEducation, models.AttrIntelligence, models.AttrPower, models.AttrLuck} {
+		attr := inv.Attributes[key]
+		attr.Value = 50
+		inv.Attributes[key] = attr
+	}
+	store.SaveInvestigator(context.Background(), "owner-1", inv)
+	return inv
+}
+
+func TestApplyAging(t *testing.T) {
+	// applyAging applies aging as owner-1
+	applyAging := func(h *Handler, id string, req AgingRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		h.ApplyAging(w, withOwner(requestWithParams("POST", "/api/investigator/"+id+"/aging", body, []string{id}), "owner-1", nil))
+		return w
+	}
+	// decode reads the aging from a successful response
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) AgingResponse {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var result AgingResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return result
+	}
+
+	t.Run("applies the deductions chosen for an older investigator", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := agingInvestigator(store, 55)
+
+		result := decode(t, applyAging(h, inv.ID, AgingRequest{Deductions: map[string]int{"STR": 4, "Constitution": 6}}))
+
+		saved := store.investigators[inv.ID]
+		if saved.Attributes[models.AttrStrength].Value != 46 || saved.Attributes[models.AttrConstitution].Value != 44 || saved.Attributes[models.AttrDexterity].Value != 50 {
+			t.Errorf("expected STR 46, CON 44 and DEX 50, got %v", saved.Attributes)
+		}
+		if saved.Attributes[models.AttrAppearance].Value != 40 || result.APPLoss != 10 {
+			t.Errorf("expected APP to drop by 10, got %d", saved.Attributes[models.AttrAppearance].Value)
+		}
+		gained := 0
+		for _, check := range result.EDUImprovements {
+			// Each check rolls against EDU as raised by the checks before it
+			if check.Improved != (check.Roll > 50+gained) {
+				t.Errorf("expected an EDU check to improve only on a roll over EDU, got %+v", check)
+			}
+			gained += check.Gain
+		}
+		if len(result.EDUImprovements) != 3 || saved.Attributes[models.AttrEducation].Value != 50+gained {
+			t.Errorf("expected 3 EDU checks adding %d, got %+v and EDU %d", gained, result.EDUImprovements, saved.Attributes[models.AttrEducation].Value)
+		}
+		// STR below SIZ and DEX equal to it give MOV 8 before the penalty
+		if saved.Move != 8-2 || result.MovePenalty != 2 {
+			t.Errorf("expected MOV 6 after the age penalty, got %d", saved.Move)
+		}
+		if saved.Aging == nil || result.Summary == "" {
+			t.Error("expected the aging to be kept on the investigator with a summary")
+		}
+		if len(store.revisions) != 1 || store.revisions[0].Section != "aging" {
+			t.Error("expected the aging to be recorded in the history")
+		}
+	})
+
+	t.Run("spreads the deduction and rolls Luck twice for the young", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := agingInvestigator(store, 17)
+
+		result := decode(t, applyAging(h, inv.ID, AgingRequest{}))
+
+		saved := store.investigators[inv.ID]
+		if result.Deductions[models.AttrStrength] != 3 || result.Deductions[models.AttrSize] != 2 {
+			t.Errorf("expected 5 points spread over STR and SIZ, got %v", result.Deductions)
+		}
+		if saved.Attributes[models.AttrEducation].Value != 45 || len(result.EDUImprovements) != 0 {
+			t.Errorf("expected EDU 45 without improvement checks, got %d", saved.Attributes[models.AttrEducation].Value)
+		}
+		if len(result.LuckRolls) != 2 || saved.Attributes[models.AttrLuck].Value != max(result.LuckRolls[0], result.LuckRolls[1]) {
+			t.Errorf("expected the best of two Luck rolls, got %v and Luck %d", result.LuckRolls, saved.Attributes[models.AttrLuck].Value)
+		}
+	})
+
+	t.Run("rejects deductions that break the rules", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := agingInvestigator(store, 45)
+
+		for _, deductions := range []map[string]int{
+			{"STR": 3},
+			{"STR": 3, "APP": 2},
+			{"DEX": 7, "CON": -2},
+		} {
+			if w := applyAging(h, inv.ID, AgingRequest{Deductions: deductions}); w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d for %v, got %d", http.StatusBadRequest, deductions, w.Code)
+			}
+		}
+		if saved := store.investigators[inv.ID]; saved.Aging != nil || saved.Attributes[models.AttrStrength].Value != 50 {
+			t.Error("expected a rejected aging to leave the investigator untouched")
+		}
+	})
+
+	t.Run("applies only once", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := agingInvestigator(store, 30)
+
+		decode(t, applyAging(h, inv.ID, AgingRequest{}))
+		if w := applyAging(h, inv.ID, AgingRequest{}); w.Code != http.StatusConflict {
+			t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+		}
+	})
+
+	t.Run("ages generated investigators of every bracket", func(t *testing.T) {
+		seen := make(map[int]bool)
+		for seed := int64(1); seed <= 300; seed++ {
+			inv := models.SeededInvestigator(models.Pulp, models.Twenties, seed)
+			bracket := models.AgeBracketFor(inv.Age)
+			if inv.Age < 15 || inv.Age > 89 {
+				t.Fatalf("seed %d: expected an age from 15 to 89, got %d", seed, inv.Age)
+			}
+			if inv.Aging == nil || inv.Aging.Age != inv.Age || len(inv.Aging.EDUImprovements) != bracket.EDUChecks {
+				t.Fatalf("seed %d: expected the generated investigator to be aged, got %+v", seed, inv.Aging)
+			}
+
+			deducted := 0
+			for _, points := range inv.Aging.Deductions {
+				deducted += points
+			}
+			if deducted != bracket.Deduction || inv.Aging.EDULoss != bracket.EDULoss || inv.Aging.MovePenalty != bracket.MovePenalty {
+				t.Errorf("seed %d: expected the modifiers of ages %d-%d, got %+v", seed, bracket.MinAge, bracket.MaxAge, inv.Aging)
+			}
+			if bracket.LuckRolls > 1 && len(inv.Aging.LuckRolls) != bracket.LuckRolls {
+				t.Errorf("seed %d: expected Luck rolled %d times, got %v", seed, bracket.LuckRolls, inv.Aging.LuckRolls)
+			}
+			seen[bracket.MinAge] = true
+		}
+
+		for _, bracket := range models.AgeBrackets {
+			if !seen[bracket.MinAge] {
+				t.Errorf("expected investigators aged %d-%d to be generated", bracket.MinAge, bracket.MaxAge)
+			}
+		}
+	})
+}
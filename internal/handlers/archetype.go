@@ -41,21 +41,42 @@ func (h *Handler) GetArchetypeOccupations(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// Build response
+	era, err := eraFromQuery(r)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, occupationsResponse(archetype.SuggestedOccupations, era))
+}
+
+// ListOccupations returns the occupations available in the era given by the era query parameter
+func (h *Handler) ListOccupations(w http.ResponseWriter, r *http.Request) {
+	era, err := eraFromQuery(r)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, occupationsResponse(nil, era))
+}
+
+// occupationsResponse splits the occupations of an era into the suggested ones and the rest
+func occupationsResponse(suggestedOccupations []string, era models.Era) ArchetypeOccupationsResponse {
 	response := ArchetypeOccupationsResponse{
-		Suggested: make([]OccupationInfo, 0, len(archetype.SuggestedOccupations)),
+		Suggested: make([]OccupationInfo, 0, len(suggestedOccupations)),
 		Others:    make([]OccupationInfo, 0),
 	}
 
 	// Create a set of suggested occupations for O(1) lookup
-	suggestedSet := make(map[string]struct{}, len(archetype.SuggestedOccupations))
-	for _, name := range archetype.SuggestedOccupations {
+	suggestedSet := make(map[string]struct{}, len(suggestedOccupations))
+	for _, name := range suggestedOccupations {
 		suggestedSet[name] = struct{}{}
 	}
 
 	// Add suggested occupations
-	for _, suggestedOccName := range archetype.SuggestedOccupations {
-		if occupation, exists := models.Occupations[suggestedOccName]; exists {
+	for _, suggestedOccName := range suggestedOccupations {
+		if occupation, exists := models.Occupations[suggestedOccName]; exists && occupation.AvailableIn(era) {
 			response.Suggested = append(response.Suggested, OccupationInfo{
 				Name:        occupation.Name,
 				Description: occupation.GetDescription(),
@@ -64,16 +85,15 @@ func (h *Handler) GetArchetypeOccupations(w http.ResponseWriter, r *http.Request
 	}
 
 	// Add all other occupations
-	for _, occupationName := range models.OccupationsList {
+	for _, occupationName := range models.OccupationsForEra(era) {
 		if _, isSuggested := suggestedSet[occupationName]; !isSuggested {
-			if occupation, exists := models.Occupations[occupationName]; exists {
-				response.Others = append(response.Others, OccupationInfo{
-					Name:        occupation.Name,
-					Description: occupation.GetDescription(),
-				})
-			}
+			occupation := models.Occupations[occupationName]
+			response.Others = append(response.Others, OccupationInfo{
+				Name:        occupation.Name,
+				Description: occupation.GetDescription(),
+			})
 		}
 	}
 
-	h.respondJSON(w, http.StatusOK, response)
-}
\ No newline at end of file
+	return response
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/storage"
+	"book-of-shadows/views"
+)
+
+// maxUsernameLength bounds usernames so they fit in the navbar
+const maxUsernameLength = 32
+
+// CredentialsRequest is the body of register and login requests
+type CredentialsRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// LoginPage renders the login form
+func (h *Handler) LoginPage(w http.ResponseWriter, r *http.Request) {
+	component := views.Login()
+	if err := component.Render(r.Context(), w); err != nil {
+		h.logger.Printf("Failed to render login page: %v", err)
+		h.respondError(w, err)
+	}
+}
+
+// RegisterPage renders the registration form
+func (h *Handler) RegisterPage(w http.ResponseWriter, r *http.Request) {
+	component := views.Register()
+	if err := component.Render(r.Context(), w); err != nil {
+		h.logger.Printf("Failed to render register page: %v", err)
+		h.respondError(w, err)
+	}
+}
+
+// Register creates an account, moves the browser's investigators into it and logs in
+func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
+	creds, ok := h.decodeCredentials(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.validateCredentials(creds); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	user, err := h.store.CreateUser(r.Context(), creds.Username, creds.Password)
+	if err != nil {
+		if err == errors.ErrAlreadyExists {
+			err = errors.NewHTTPError(http.StatusConflict, "Username is already taken", err)
+		}
+		h.respondError(w, err)
+		return
+	}
+
+	if err := h.startSession(w, r, user.ID); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, user)
+}
+
+// Login verifies the credentials and starts a session
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
+	creds, ok := h.decodeCredentials(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.store.AuthenticateUser(r.Context(), creds.Username, creds.Password)
+	if err != nil {
+		if err == errors.ErrInvalidCredentials {
+			err = errors.NewHTTPError(http.StatusUnauthorized, "Invalid username or password", err)
+		}
+		h.respondError(w, err)
+		return
+	}
+
+	if err := h.startSession(w, r, user.ID); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, user)
+}
+
+// Logout ends the current session
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(h.auth.SessionCookie); err == nil && cookie.Value != "" {
+		if err := h.store.DeleteSession(r.Context(), cookie.Value); err != nil {
+			h.respondError(w, err)
+			return
+		}
+	}
+
+	http.SetCookie(w, h.sessionCookie("", -1))
+	h.respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Logged out",
+	})
+}
+
+// CurrentUser returns the logged in user
+func (h *Handler) CurrentUser(w http.ResponseWriter, r *http.Request) {
+	user := storage.UserFromContext(r.Context())
+	if user == nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusUnauthorized, "Not logged in", nil))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, user)
+}
+
+// decodeCredentials reads the credentials from the request body, responding on failure
+func (h *Handler) decodeCredentials(w http.ResponseWriter, r *http.Request) (*CredentialsRequest, bool) {
+	var creds CredentialsRequest
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid JSON", err))
+		return nil, false
+	}
+	defer r.Body.Close()
+
+	creds.Username = strings.TrimSpace(creds.Username)
+	if creds.Username == "" || creds.Password == "" {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Username and password are required", nil))
+		return nil, false
+	}
+
+	return &creds, true
+}
+
+// validateCredentials checks the rules for new accounts
+func (h *Handler) validateCredentials(creds *CredentialsRequest) error {
+	if utf8.RuneCountInString(creds.Username) > maxUsernameLength {
+		return errors.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Username must be at most %d characters", maxUsernameLength), nil)
+	}
+	if utf8.RuneCountInString(creds.Password) < h.auth.MinPasswordLength {
+		return errors.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Password must be at least %d characters", h.auth.MinPasswordLength), nil)
+	}
+	return nil
+}
+
+// startSession logs the user in, first moving the investigators this browser created
+// anonymously into the account so they are not lost
+func (h *Handler) startSession(w http.ResponseWriter, r *http.Request, userID string) error {
+	ctx := r.Context()
+	if storage.UserFromContext(ctx) == nil {
+		if err := h.store.ClaimInvestigators(ctx, storage.OwnerFromContext(ctx), userID); err != nil {
+			// Logging in still works, the investigators stay with the browser
+			h.logger.Printf("Failed to claim investigators: %v", err)
+		}
+	}
+
+	token, err := h.store.CreateSession(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, h.sessionCookie(token, int(h.auth.SessionTTL/time.Second)))
+	return nil
+}
+
+// sessionCookie creates the login session cookie; a negative maxAge removes it
+func (h *Handler) sessionCookie(token string, maxAge int) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     h.auth.SessionCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   h.auth.Secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if maxAge < 0 {
+		cookie.Expires = time.Now().Add(-24 * time.Hour)
+	}
+	return cookie
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"book-of-shadows/models"
+)
+
+// credentialsRequest builds a JSON register or login request for an anonymous owner
+func credentialsRequest(path, username, password string) *http.Request {
+	body, _ := json.Marshal(CredentialsRequest{Username: username, Password: password})
+	return withOwner(requestWithParams("POST", path, body, nil), "anonymous-owner", nil)
+}
+
+// sessionCookieFrom returns the session cookie set on a response, if any
+func sessionCookieFrom(w *httptest.ResponseRecorder) *http.Cookie {
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "session" {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestRegister(t *testing.T) {
+	t.Run("creates account, claims investigators and logs in", func(t *testing.T) {
+		h, store := newTestHandler()
+
+		w := httptest.NewRecorder()
+		h.Register(w, credentialsRequest("/api/auth/register", "harvey", "correct horse"))
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+		}
+		cookie := sessionCookieFrom(w)
+		if cookie == nil || !cookie.HttpOnly {
+			t.Fatal("expected HttpOnly session cookie")
+		}
+		if _, ok := store.sessions[cookie.Value]; !ok {
+			t.Error("expected session to be stored")
+		}
+		if store.claimed["anonymous-owner"] != "user-harvey" {
+			t.Error("expected anonymous investigators to be claimed by the new user")
+		}
+	})
+
+	t.Run("rejects taken username", func(t *testing.T) {
+		h, _ := newTestHandler()
+		h.Register(httptest.NewRecorder(), credentialsRequest("/api/auth/register", "harvey", "correct horse"))
+
+		w := httptest.NewRecorder()
+		h.Register(w, credentialsRequest("/api/auth/register", "harvey", "another password"))
+
+		if w.Code != http.StatusConflict {
+			t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+		}
+	})
+
+	t.Run("rejects short password", func(t *testing.T) {
+		h, _ := newTestHandler()
+
+		w := httptest.NewRecorder()
+		h.Register(w, credentialsRequest("/api/auth/register", "harvey", "short"))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("rejects missing username", func(t *testing.T) {
+		h, _ := newTestHandler()
+
+		w := httptest.NewRecorder()
+		h.Register(w, credentialsRequest("/api/auth/register", "  ", "correct horse"))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
+
+func TestLoginAndLogout(t *testing.T) {
+	h, store := newTestHandler()
+	store.CreateUser(context.Background(), "harvey", "correct horse")
+
+	t.Run("rejects wrong password", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.Login(w, credentialsRequest("/api/auth/login", "harvey", "wrong password"))
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+		}
+		if sessionCookieFrom(w) != nil {
+			t.Error("expected no session cookie")
+		}
+	})
+
+	w := httptest.NewRecorder()
+	h.Login(w, credentialsRequest("/api/auth/login", "harvey", "correct horse"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	cookie := sessionCookieFrom(w)
+	if cookie == nil {
+		t.Fatal("expected session cookie")
+	}
+
+	t.Run("logout ends the session", func(t *testing.T) {
+		req := requestWithParams("POST", "/api/auth/logout", nil, nil)
+		req.AddCookie(cookie)
+		w := httptest.NewRecorder()
+
+		h.Logout(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		if _, ok := store.sessions[cookie.Value]; ok {
+			t.Error("expected session to be deleted")
+		}
+		if expired := sessionCookieFrom(w); expired == nil || expired.MaxAge >= 0 {
+			t.Error("expected session cookie to be removed")
+		}
+	})
+}
+
+func TestCurrentUser(t *testing.T) {
+	h, _ := newTestHandler()
+
+	t.Run("returns 401 when anonymous", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.CurrentUser(w, withOwner(requestWithParams("GET", "/api/auth/me", nil, nil), "anonymous-owner", nil))
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+		}
+	})
+
+	t.Run("returns logged in user", func(t *testing.T) {
+		user := &models.User{ID: "user-1", Username: "harvey"}
+		w := httptest.NewRecorder()
+		h.CurrentUser(w, withOwner(requestWithParams("GET", "/api/auth/me", nil, nil), user.ID, user))
+
+		var result models.User
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if result.Username != "harvey" {
+			t.Errorf("expected username harvey, got %s", result.Username)
+		}
+	})
+}
+
+func TestInvestigatorOwnership(t *testing.T) {
+	h, store := newTestHandler()
+	owner := &models.User{ID: "user-1", Username: "owner"}
+	other := &models.User{ID: "user-2", Username: "other"}
+
+	store.SaveInvestigator(context.Background(), owner.ID, models.RandomInvestigator(models.Pulp, models.Modern))
+
+	t.Run("owner can open investigator", func(t *testing.T) {
+		req := withOwner(requestWithParams("GET", "/api/investigator/test-inv-id", nil, []string{"test-inv-id"}), owner.ID, owner)
+		w := httptest.NewRecorder()
+
+		h.GetInvestigator(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+	})
+
+	t.Run("other user cannot open investigator", func(t *testing.T) {
+		req := withOwner(requestWithParams("GET", "/api/investigator/test-inv-id", nil, []string{"test-inv-id"}), other.ID, other)
+		w := httptest.NewRecorder()
+
+		h.GetInvestigator(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+
+	t.Run("other user cannot delete investigator", func(t *testing.T) {
+		req := withOwner(requestWithParams("DELETE", "/api/investigator/test-inv-id", nil, []string{"test-inv-id"}), other.ID, other)
+		w := httptest.NewRecorder()
+
+		h.DeleteInvestigator(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+		if _, ok := store.investigators["test-inv-id"]; !ok {
+			t.Error("expected investigator to remain")
+		}
+	})
+}
@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/models"
+	"book-of-shadows/storage"
+	"book-of-shadows/views"
+)
+
+// CreateCampaignRequest is the body of a create campaign request
+type CreateCampaignRequest struct {
+	Name     string `json:"name"`
+	Era      string `json:"era"`
+	GameMode string `json:"mode"`
+}
+
+// JoinCampaignRequest is the body of a join campaign request
+type JoinCampaignRequest struct {
+	Code           string `json:"code"`
+	InvestigatorID string `json:"investigatorId"`
+}
+
+// StrictRulesRequest is the body of a member strict rules request
+type StrictRulesRequest struct {
+	Strict bool `json:"strict"`
+}
+
+// CampaignsPage renders the keeper's campaigns
+func (h *Handler) CampaignsPage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	campaigns, err := h.store.ListCampaigns(ctx, storage.OwnerFromContext(ctx))
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	component := views.Campaigns(campaigns)
+	if err := component.Render(r.Context(), w); err != nil {
+		h.logger.Printf("Failed to render campaigns: %v", err)
+		h.respondError(w, err)
+	}
+}
+
+// PartyOverview renders the status of every member of one of the keeper's campaigns
+func (h *Handler) PartyOverview(w http.ResponseWriter, r *http.Request) {
+	campaign, ok := h.keeperCampaign(w, r)
+	if !ok {
+		return
+	}
+
+	component := views.PartyOverview(campaign)
+	if err := component.Render(r.Context(), w); err != nil {
+		h.logger.Printf("Failed to render party overview: %v", err)
+		h.respondError(w, err)
+	}
+}
+
+// GetCampaignParty returns the party status of one of the keeper's campaigns as JSON
+func (h *Handler) GetCampaignParty(w http.ResponseWriter, r *http.Request) {
+	campaign, ok := h.keeperCampaign(w, r)
+	if !ok {
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, campaign.Party())
+}
+
+// CreateCampaign creates a campaign run by the current owner
+func (h *Handler) CreateCampaign(w http.ResponseWriter, r *http.Request) {
+	var req CreateCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid JSON", err))
+		return
+	}
+	defer r.Body.Close()
+
+	campaign := &models.Campaign{Name: strings.TrimSpace(req.Name)}
+	if campaign.Name == "" {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Campaign name is required", nil))
+		return
+	}
+
+	var err error
+	if campaign.Era, err = models.ParseEra(req.Era); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid era", err))
+		return
+	}
+	if campaign.GameMode, err = models.ParseGameMode(req.GameMode); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid game mode", err))
+		return
+	}
+
+	ctx := r.Context()
+	if user := storage.UserFromContext(ctx); user != nil {
+		campaign.KeeperName = user.Username
+	}
+
+	if _, err := h.store.CreateCampaign(ctx, storage.OwnerFromContext(ctx), campaign); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, campaign)
+}
+
+// DeleteCampaign deletes one of the keeper's campaigns
+func (h *Handler) DeleteCampaign(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) == 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing campaign ID", nil))
+		return
+	}
+
+	ctx := r.Context()
+	if err := h.store.DeleteCampaign(ctx, storage.OwnerFromContext(ctx), params[0]); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "campaignDeleted")
+	w.WriteHeader(http.StatusOK)
+}
+
+// RemoveCampaignMember removes an investigator from one of the keeper's campaigns
+func (h *Handler) RemoveCampaignMember(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) < 2 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing campaign or investigator ID", nil))
+		return
+	}
+
+	ctx := r.Context()
+	if err := h.store.RemoveCampaignMember(ctx, storage.OwnerFromContext(ctx), params[0], params[1]); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "memberRemoved")
+	w.WriteHeader(http.StatusOK)
+}
+
+// SetMemberStrictRules turns strict rules on or off for a campaign member, keeping a player
+// from dropping them once creation is over
+func (h *Handler) SetMemberStrictRules(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) < 2 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing campaign or investigator ID", nil))
+		return
+	}
+
+	var req StrictRulesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid JSON", err))
+		return
+	}
+	defer r.Body.Close()
+
+	ctx := r.Context()
+	member, err := h.store.GetCampaignMember(ctx, storage.OwnerFromContext(ctx), params[0], params[1])
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	investigator, err := h.store.GetMemberInvestigator(ctx, member)
+	if err != nil {
+		if err == errors.ErrNotFound {
+			err = errors.NewHTTPError(http.StatusConflict, "The player keeps this investigator in their browser, change strict rules from their sheet", err)
+		}
+		h.respondError(w, err)
+		return
+	}
+
+	if investigator.StrictRules != req.Strict {
+		snapshot, err := investigator.ToJSON()
+		if err != nil {
+			h.respondError(w, err)
+			return
+		}
+
+		investigator.StrictRules = req.Strict
+		if err := h.store.UpdateMemberInvestigator(ctx, member, investigator); err != nil {
+			h.respondError(w, err)
+			return
+		}
+		h.recordRevisionFor(ctx, member.OwnerID, member.InvestigatorID, "stats", "StrictRules", !req.Strict, req.Strict, snapshot)
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]bool{"strict": investigator.StrictRules})
+}
+
+// JoinCampaign adds one of the current owner's investigators to the campaign with the join code
+func (h *Handler) JoinCampaign(w http.ResponseWriter, r *http.Request) {
+	var req JoinCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid JSON", err))
+		return
+	}
+	defer r.Body.Close()
+
+	if strings.TrimSpace(req.Code) == "" || req.InvestigatorID == "" {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Join code and investigator are required", nil))
+		return
+	}
+
+	// Only the owner of an investigator can bring it into a campaign
+	ctx := r.Context()
+	ownerID := storage.OwnerFromContext(ctx)
+	investigator, err := h.store.GetInvestigator(ctx, ownerID, req.InvestigatorID)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	investigator.ID = req.InvestigatorID
+
+	campaign, err := h.store.JoinCampaign(ctx, req.Code, ownerID, investigator)
+	if err != nil {
+		if err == errors.ErrNotFound {
+			err = errors.NewHTTPError(http.StatusNotFound, "Unknown join code", err)
+		}
+		h.respondError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]string{
+		"campaign": campaign.Name,
+	})
+}
+
+// keeperCampaign loads the campaign named in the route for the current keeper, responding on failure
+func (h *Handler) keeperCampaign(w http.ResponseWriter, r *http.Request) (*models.Campaign, bool) {
+	params := r.Context().Value("params").([]string)
+	if len(params) == 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing campaign ID", nil))
+		return nil, false
+	}
+
+	ctx := r.Context()
+	campaign, err := h.store.GetCampaign(ctx, storage.OwnerFromContext(ctx), params[0])
+	if err != nil {
+		h.respondError(w, err)
+		return nil, false
+	}
+
+	return campaign, true
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"book-of-shadows/models"
+)
+
+// createCampaign creates a campaign for keeper-1 and returns it
+func createCampaign(t *testing.T, h *Handler, name string) *models.Campaign {
+	t.Helper()
+
+	body, _ := json.Marshal(CreateCampaignRequest{Name: name, Era: "1920s", GameMode: "classic"})
+	w := httptest.NewRecorder()
+	h.CreateCampaign(w, withOwner(requestWithParams("POST", "/api/campaign", body, nil), "keeper-1", nil))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var campaign models.Campaign
+	if err := json.Unmarshal(w.Body.Bytes(), &campaign); err != nil {
+		t.Fatalf("failed to decode campaign: %v", err)
+	}
+	return &campaign
+}
+
+func TestCreateCampaign(t *testing.T) {
+	h, _ := newTestHandler()
+
+	t.Run("creates campaign with join code", func(t *testing.T) {
+		campaign := createCampaign(t, h, "The Haunting")
+		if campaign.JoinCode == "" {
+			t.Error("expected a join code")
+		}
+		if campaign.Era != models.Twenties || campaign.GameMode != models.Classic {
+			t.Errorf("expected 1920s classic, got %v %v", campaign.Era, campaign.GameMode)
+		}
+	})
+
+	tests := []struct {
+		name string
+		req  CreateCampaignRequest
+	}{
+		{"missing name", CreateCampaignRequest{Name: "  ", Era: "1920s", GameMode: "pulp"}},
+		{"unknown era", CreateCampaignRequest{Name: "Test", Era: "stone age", GameMode: "pulp"}},
+		{"unknown game mode", CreateCampaignRequest{Name: "Test", Era: "1920s", GameMode: "grim"}},
+	}
+	for _, tt := range tests {
+		t.Run("rejects "+tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.req)
+			w := httptest.NewRecorder()
+			h.CreateCampaign(w, withOwner(requestWithParams("POST", "/api/campaign", body, nil), "keeper-1", nil))
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
+
+func TestJoinCampaign(t *testing.T) {
+	h, store := newTestHandler()
+	campaign := createCampaign(t, h, "The Haunting")
+
+	inv := models.RandomInvestigator(models.Pulp, models.Modern)
+	inv.Name = "Carl Stanford"
+	store.SaveInvestigator(context.Background(), "player-1", inv)
+
+	join := func(ownerID, code string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(JoinCampaignRequest{Code: code, InvestigatorID: inv.ID})
+		w := httptest.NewRecorder()
+		h.JoinCampaign(w, withOwner(requestWithParams("POST", "/api/campaign/join", body, nil), ownerID, nil))
+		return w
+	}
+
+	t.Run("rejects another player's investigator", func(t *testing.T) {
+		if w := join("player-2", campaign.JoinCode); w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+
+	t.Run("rejects unknown code", func(t *testing.T) {
+		if w := join("player-1", "UNKNOWN"); w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+
+	t.Run("joins with own investigator", func(t *testing.T) {
+		if w := join("player-1", campaign.JoinCode); w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		w := httptest.NewRecorder()
+		h.GetCampaignParty(w, withOwner(requestWithParams("GET", "/", nil, []string{campaign.ID}), "keeper-1", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+
+		var party []models.PartyStatus
+		json.Unmarshal(w.Body.Bytes(), &party)
+		if len(party) != 1 || party[0].Name != "Carl Stanford" {
+			t.Fatalf("expected Carl Stanford in the party, got %+v", party)
+		}
+		if party[0].HP != inv.Attributes[models.AttrHitPoints].Value {
+			t.Errorf("expected HP %d, got %d", inv.Attributes[models.AttrHitPoints].Value, party[0].HP)
+		}
+	})
+}
+
+func TestCampaignKeeperAccess(t *testing.T) {
+	h, store := newTestHandler()
+	campaign := createCampaign(t, h, "The Haunting")
+
+	inv := models.RandomInvestigator(models.Pulp, models.Modern)
+	store.SaveInvestigator(context.Background(), "player-1", inv)
+	store.JoinCampaign(context.Background(), campaign.JoinCode, "player-1", inv)
+
+	t.Run("hides party from other owners", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.PartyOverview(w, withOwner(requestWithParams("GET", "/", nil, []string{campaign.ID}), "player-1", nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+
+	t.Run("renders party overview", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.PartyOverview(w, withOwner(requestWithParams("GET", "/", nil, []string{campaign.ID}), "keeper-1", nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+	})
+
+	t.Run("removes member", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.RemoveCampaignMember(w, withOwner(requestWithParams("DELETE", "/", nil, []string{campaign.ID, inv.ID}), "keeper-1", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		if w.Header().Get("HX-Trigger") != "memberRemoved" {
+			t.Errorf("expected HX-Trigger memberRemoved, got %q", w.Header().Get("HX-Trigger"))
+		}
+	})
+
+	t.Run("deletes campaign", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.DeleteCampaign(w, withOwner(requestWithParams("DELETE", "/", nil, []string{campaign.ID}), "keeper-1", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		if _, ok := store.campaigns[campaign.ID]; ok {
+			t.Error("expected campaign to be deleted")
+		}
+	})
+}
+
+func TestSetMemberStrictRules(t *testing.T) {
+	// setStrict sets strict rules for a campaign member as the keeper
+	setStrict := func(h *Handler, keeperID, campaignID, id string, strict bool) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(StrictRulesRequest{Strict: strict})
+		w := httptest.NewRecorder()
+		path := "/api/campaign/" + campaignID + "/member/" + id + "/strict"
+		h.SetMemberStrictRules(w, withOwner(requestWithParams("POST", path, body, []string{campaignID, id}), keeperID, nil))
+		return w
+	}
+
+	// member creates a campaign for keeper-1 that an investigator of player-1 joined
+	member := func(t *testing.T) (*Handler, *MockStore, *models.Campaign, *models.Investigator) {
+		h, store := newTestHandler()
+		campaign := createCampaign(t, h, "The Haunting")
+		inv := models.SeededInvestigator(models.Classic, models.Modern, 7)
+		store.SaveInvestigator(context.Background(), "player-1", inv)
+		store.JoinCampaign(context.Background(), campaign.JoinCode, "player-1", inv)
+		return h, store, campaign, inv
+	}
+
+	t.Run("changes the player's sheet", func(t *testing.T) {
+		h, store, campaign, inv := member(t)
+
+		if w := setStrict(h, "keeper-1", campaign.ID, inv.ID, true); w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if !store.investigators[inv.ID].StrictRules {
+			t.Error("expected strict rules to be turned on")
+		}
+		if len(store.revisions) != 1 || store.revisions[0].AuthorID != "keeper-1" {
+			t.Errorf("expected one revision by the keeper, got %+v", store.revisions)
+		}
+
+		// Setting the same value again changes nothing
+		if w := setStrict(h, "keeper-1", campaign.ID, inv.ID, true); w.Code != http.StatusOK || len(store.revisions) != 1 {
+			t.Errorf("expected no new revision, got status %d and %d revisions", w.Code, len(store.revisions))
+		}
+	})
+
+	t.Run("rejects keepers of other campaigns", func(t *testing.T) {
+		h, store, campaign, inv := member(t)
+
+		if w := setStrict(h, "keeper-2", campaign.ID, inv.ID, true); w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+		if store.investigators[inv.ID].StrictRules {
+			t.Error("expected strict rules to stay off")
+		}
+	})
+
+	t.Run("needs the player's sheet", func(t *testing.T) {
+		h, store, campaign, inv := member(t)
+		// Sheets kept in the player's browser cookies are out of the keeper's reach
+		delete(store.investigators, inv.ID)
+
+		if w := setStrict(h, "keeper-1", campaign.ID, inv.ID, true); w.Code != http.StatusConflict {
+			t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+		}
+	})
+}
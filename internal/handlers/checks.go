@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/internal/validation"
+	"book-of-shadows/models"
+	"book-of-shadows/storage"
+)
+
+// CheckRequest is the body of a skill or characteristic check
+type CheckRequest struct {
+	Target          string `json:"target"`     // Skill name or characteristic, e.g. "Spot Hidden" or "POW"
+	Difficulty      string `json:"difficulty"` // Regular, Hard or Extreme, Regular when empty
+	Bonus           int    `json:"bonus"`
+	Penalty         int    `json:"penalty"`
+	MarkImprovement bool   `json:"markImprovement"` // Tick the skill for improvement on a success
+}
+
+// Check rolls a check against one of an investigator's skills or characteristics
+func (h *Handler) Check(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) == 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing investigator ID", nil))
+		return
+	}
+	id := params[0]
+
+	var req CheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid JSON", err))
+		return
+	}
+	defer r.Body.Close()
+
+	difficulty, err := models.ParseDifficulty(req.Difficulty)
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Unknown difficulty", err))
+		return
+	}
+	if req.Bonus < 0 || req.Penalty < 0 || req.Bonus > 2 || req.Penalty > 2 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Bonus and penalty dice must be between 0 and 2", nil))
+		return
+	}
+
+	ctx := r.Context()
+	ownerID := storage.OwnerFromContext(ctx)
+	investigator, err := h.store.GetInvestigator(ctx, ownerID, id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	// Keep the sheet as it was in case the check ticks a skill
+	snapshot, err := investigator.ToJSON()
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	alreadyTicked := investigator.Skills[req.Target].IsSelected
+	violations := validation.Check(investigator)
+
+	result, err := investigator.Check(newRand(), req.Target, difficulty, req.Bonus, req.Penalty, req.MarkImprovement)
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Unknown skill or characteristic", errors.ErrInvalidSkill))
+		return
+	}
+
+	if result.Improvement && !alreadyTicked {
+		if err := h.saveInvestigator(ctx, ownerID, id, investigator, violations); err != nil {
+			h.respondError(w, err)
+			return
+		}
+		h.recordRevision(ctx, id, "skill_check", result.Target, false, true, snapshot)
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// Develop runs the end of session development phase for an investigator, rolling to
+// improve every skill ticked for improvement, and returns the report
+func (h *Handler) Develop(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) == 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing investigator ID", nil))
+		return
+	}
+	id := params[0]
+
+	ctx := r.Context()
+	ownerID := storage.OwnerFromContext(ctx)
+	investigator, err := h.store.GetInvestigator(ctx, ownerID, id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	snapshot, err := investigator.ToJSON()
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if investigator.Creating {
+		h.respondError(w, errors.NewHTTPError(http.StatusConflict, "Investigators develop once creation is complete", nil))
+		return
+	}
+	violations := validation.Check(investigator)
+	report, err := investigator.Develop(newRand(), time.Now().UTC())
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Nothing to develop", err))
+		return
+	}
+
+	if err := h.saveInvestigator(ctx, ownerID, id, investigator, violations); err != nil {
+		h.respondError(w, err)
+		return
+	}
+	h.recordRevision(ctx, id, "development", "", nil, report, snapshot)
+
+	h.respondJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"book-of-shadows/models"
+)
+
+// check sends a check request for an investigator as owner-1
+func check(h *Handler, id string, req CheckRequest) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	h.Check(w, withOwner(requestWithParams("POST", "/api/investigator/"+id+"/check", body, []string{id}), "owner-1", nil))
+	return w
+}
+
+func TestResolveCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		roll       int
+		value      int
+		difficulty models.Difficulty
+		want       models.SuccessLevel
+	}{
+		{"a 1 is always a critical", 1, 5, models.ExtremeDifficulty, models.CriticalSuccess},
+		{"a 100 is always a fumble", 100, 99, models.RegularDifficulty, models.Fumble},
+		{"96 fumbles under 50", 96, 49, models.RegularDifficulty, models.Fumble},
+		{"96 only fails at 50 or more", 96, 50, models.RegularDifficulty, models.Failure},
+		{"96 fumbles a hard check under 50", 96, 90, models.HardDifficulty, models.Fumble},
+		{"extreme at a fifth", 12, 60, models.RegularDifficulty, models.ExtremeSuccess},
+		{"hard at a half", 30, 60, models.RegularDifficulty, models.HardSuccess},
+		{"regular at the value", 60, 60, models.RegularDifficulty, models.RegularSuccess},
+		{"failure above the value", 61, 60, models.RegularDifficulty, models.Failure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := models.ResolveCheck(tt.roll, tt.value, tt.difficulty); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	if models.RegularSuccess.Succeeds(models.HardDifficulty) {
+		t.Error("expected a regular success to fail a hard check")
+	}
+	if !models.ExtremeSuccess.Succeeds(models.HardDifficulty) {
+		t.Error("expected an extreme success to pass a hard check")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	// newInvestigator stores an investigator whose skill always succeeds unless fumbled
+	newInvestigator := func(store *MockStore, skill string) *models.Investigator {
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		s := inv.Skills[skill]
+		s.Value = 99
+		s.IsSelected = false
+		inv.Skills[skill] = s
+		store.SaveInvestigator(context.Background(), "owner-1", inv)
+		return inv
+	}
+
+	t.Run("ticks a successful skill for improvement", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := newInvestigator(store, "Spot Hidden")
+
+		for i := 0; i < 20; i++ {
+			w := check(h, inv.ID, CheckRequest{Target: "Spot Hidden", MarkImprovement: true})
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			var result struct {
+				Level       string `json:"level"`
+				Success     bool   `json:"success"`
+				Improvement bool   `json:"improvement"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &result)
+			if result.Success != result.Improvement {
+				t.Fatalf("expected a success to tick the skill, got %+v", result)
+			}
+			if result.Success {
+				if !store.investigators[inv.ID].Skills["Spot Hidden"].IsSelected {
+					t.Fatal("expected the tick to be saved")
+				}
+				if len(store.revisions) != 1 || store.revisions[0].Section != "skill_check" {
+					t.Fatalf("expected one skill_check revision, got %d", len(store.revisions))
+				}
+				return
+			}
+		}
+		t.Fatal("expected a 99 skill to succeed at least once")
+	})
+
+	t.Run("does not tick with a bonus die", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := newInvestigator(store, "Spot Hidden")
+
+		for i := 0; i < 20; i++ {
+			check(h, inv.ID, CheckRequest{Target: "Spot Hidden", Bonus: 1, MarkImprovement: true})
+		}
+		if store.investigators[inv.ID].Skills["Spot Hidden"].IsSelected {
+			t.Error("expected a roll helped by a bonus die not to tick the skill")
+		}
+	})
+
+	t.Run("does not tick Credit Rating", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := newInvestigator(store, "Credit Rating")
+
+		for i := 0; i < 20; i++ {
+			check(h, inv.ID, CheckRequest{Target: "Credit Rating", MarkImprovement: true})
+		}
+		if store.investigators[inv.ID].Skills["Credit Rating"].IsSelected {
+			t.Error("expected Credit Rating never to be ticked")
+		}
+	})
+
+	t.Run("checks a characteristic by abbreviation", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := newInvestigator(store, "Spot Hidden")
+
+		for _, target := range []string{"POW", "Luck", "SAN", "dexterity"} {
+			w := check(h, inv.ID, CheckRequest{Target: target, Difficulty: "hard", MarkImprovement: true})
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d for %s, got %d: %s", http.StatusOK, target, w.Code, w.Body.String())
+			}
+		}
+		if len(store.revisions) != 0 {
+			t.Error("expected characteristic checks not to change the sheet")
+		}
+	})
+
+	tests := []struct {
+		name string
+		id   string
+		req  CheckRequest
+		code int
+	}{
+		{"unknown skill", "", CheckRequest{Target: "Basket Weaving"}, http.StatusBadRequest},
+		{"unknown difficulty", "", CheckRequest{Target: "Spot Hidden", Difficulty: "impossible"}, http.StatusBadRequest},
+		{"too many bonus dice", "", CheckRequest{Target: "Spot Hidden", Bonus: 3}, http.StatusBadRequest},
+		{"unknown investigator", "missing", CheckRequest{Target: "Spot Hidden"}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run("rejects "+tt.name, func(t *testing.T) {
+			h, store := newTestHandler()
+			id := tt.id
+			if id == "" {
+				id = newInvestigator(store, "Spot Hidden").ID
+			}
+
+			w := check(h, id, tt.req)
+
+			if w.Code != tt.code {
+				t.Errorf("expected status %d, got %d", tt.code, w.Code)
+			}
+		})
+	}
+}
+
+func TestDevelop(t *testing.T) {
+	// develop runs the development phase as owner-1
+	develop := func(h *Handler, id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.Develop(w, withOwner(requestWithParams("POST", "/api/investigator/"+id+"/develop", nil, []string{id}), "owner-1", nil))
+		return w
+	}
+
+	t.Run("rolls every ticked skill and clears the ticks", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		for name, skill := range inv.Skills {
+			skill.IsSelected = name == "Spot Hidden" || name == "Listen"
+			inv.Skills[name] = skill
+		}
+		store.SaveInvestigator(context.Background(), "owner-1", inv)
+
+		w := develop(h, inv.ID)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var report models.DevelopmentReport
+		if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if len(report.Improvements) != 2 || report.Date.IsZero() {
+			t.Fatalf("expected a dated report for two skills, got %+v", report)
+		}
+
+		saved := store.investigators[inv.ID]
+		for _, imp := range report.Improvements {
+			skill := saved.Skills[imp.Skill]
+			if skill.IsSelected {
+				t.Errorf("expected %s to be unticked", imp.Skill)
+			}
+			if skill.Value != imp.To || imp.To-imp.From != imp.Gain {
+				t.Errorf("expected %s to go from %d to %d, saved %d", imp.Skill, imp.From, imp.To, skill.Value)
+			}
+			wantImproved := imp.Roll > imp.From || imp.Roll > 95
+			if imp.Improved != wantImproved || imp.Improved && (imp.Gain < 1 || imp.Gain > 10) {
+				t.Errorf("expected a roll of %d against %d to improve: %v, got %+v", imp.Roll, imp.From, wantImproved, imp)
+			}
+		}
+		if len(saved.Development) != 1 {
+			t.Errorf("expected the report to be stored, got %d reports", len(saved.Development))
+		}
+		if len(store.revisions) != 1 || store.revisions[0].Section != "development" {
+			t.Error("expected the development phase to be recorded in the history")
+		}
+	})
+
+	t.Run("rewards Sanity for reaching 90%", func(t *testing.T) {
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		for i := 0; i < 100; i++ {
+			skill := inv.Skills["Spot Hidden"]
+			skill.Value = 89
+			skill.IsSelected = true
+			inv.Skills["Spot Hidden"] = skill
+			san := inv.Attributes[models.AttrSanity]
+			san.Value = 10
+			inv.Attributes[models.AttrSanity] = san
+
+			report, err := inv.Develop(newRand(), time.Now())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if report.Improvements[0].Improved {
+				if report.SanityGained < 2 || report.SanityGained > 12 {
+					t.Fatalf("expected a 2D6 Sanity reward, got %d", report.SanityGained)
+				}
+				if got := inv.Attributes[models.AttrSanity].Value; got != 10+report.SanityGained {
+					t.Fatalf("expected Sanity %d, got %d", 10+report.SanityGained, got)
+				}
+				return
+			}
+		}
+		t.Fatal("expected an 89% skill to improve at least once")
+	})
+
+	t.Run("needs an improvable skill ticked", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		for name, skill := range inv.Skills {
+			// Credit Rating and Cthulhu Mythos are not improved in the development phase
+			skill.IsSelected = name == "Credit Rating" || name == "Cthulhu Mythos"
+			inv.Skills[name] = skill
+		}
+		store.SaveInvestigator(context.Background(), "owner-1", inv)
+
+		if w := develop(h, inv.ID); w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+		if len(store.investigators[inv.ID].Development) != 0 || len(store.revisions) != 0 {
+			t.Error("expected no development phase to be kept")
+		}
+	})
+
+	t.Run("waits for creation to be complete", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		inv.Creating = true
+		skill := inv.Skills["Spot Hidden"]
+		skill.IsSelected = true
+		inv.Skills["Spot Hidden"] = skill
+		store.SaveInvestigator(context.Background(), "owner-1", inv)
+
+		if w := develop(h, inv.ID); w.Code != http.StatusConflict {
+			t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+		}
+		if len(store.investigators[inv.ID].Development) != 0 {
+			t.Error("expected no development phase to be kept")
+		}
+	})
+
+	t.Run("rejects an unknown investigator", func(t *testing.T) {
+		h, _ := newTestHandler()
+
+		w := develop(h, "missing")
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
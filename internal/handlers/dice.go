@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"strings"
+
+	"book-of-shadows/internal/dice"
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/storage"
+)
+
+// RollRequest is the body of a dice roll request. An investigator lets the
+// expression name their values, such as DB for the damage bonus.
+type RollRequest struct {
+	Expression     string `json:"expression"`
+	InvestigatorID string `json:"investigatorId"`
+}
+
+// Roll rolls a dice expression and returns the total with every die rolled
+func (h *Handler) Roll(w http.ResponseWriter, r *http.Request) {
+	var req RollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid JSON", err))
+		return
+	}
+	defer r.Body.Close()
+
+	expression, err := dice.Parse(req.Expression)
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid dice expression: "+err.Error(), err))
+		return
+	}
+
+	var vars dice.Vars
+	if id := strings.TrimSpace(req.InvestigatorID); id != "" {
+		ctx := r.Context()
+		investigator, err := h.store.GetInvestigator(ctx, storage.OwnerFromContext(ctx), id)
+		if err != nil {
+			h.respondError(w, err)
+			return
+		}
+		vars = investigator.DiceVars()
+	}
+
+	result, err := expression.Roll(newRand(), vars)
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Cannot roll expression: "+err.Error(), err))
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// newRand returns a random source for a single request
+func newRand() *rand.Rand {
+	return rand.New(rand.NewSource(rand.Int63()))
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"book-of-shadows/internal/dice"
+	"book-of-shadows/models"
+)
+
+// roll sends a roll request as owner-1
+func roll(h *Handler, req RollRequest) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	h.Roll(w, withOwner(requestWithParams("POST", "/api/roll", body, nil), "owner-1", nil))
+	return w
+}
+
+func TestRoll(t *testing.T) {
+	t.Run("rolls an expression with its breakdown", func(t *testing.T) {
+		h, _ := newTestHandler()
+
+		w := roll(h, RollRequest{Expression: "3D6*5"})
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var result dice.Result
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if result.Total < 15 || result.Total > 90 || len(result.Rolls) != 1 || len(result.Rolls[0].Dice) != 3 {
+			t.Errorf("expected 3D6*5 with three dice, got %+v", result)
+		}
+	})
+
+	t.Run("uses the investigator's damage bonus", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		inv.DamageBonus = "+1D4"
+		store.SaveInvestigator(context.Background(), "owner-1", inv)
+
+		w := roll(h, RollRequest{Expression: "1D10+DB", InvestigatorID: inv.ID})
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var result dice.Result
+		json.Unmarshal(w.Body.Bytes(), &result)
+		if len(result.Rolls) != 2 || result.Rolls[1].Term != "1D4" {
+			t.Errorf("expected the damage bonus die in the breakdown, got %+v", result.Rolls)
+		}
+	})
+
+	t.Run("treats no damage bonus as zero", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		inv.DamageBonus = "None"
+		store.SaveInvestigator(context.Background(), "owner-1", inv)
+
+		w := roll(h, RollRequest{Expression: "1D3+DB", InvestigatorID: inv.ID})
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+	})
+
+	tests := []struct {
+		name string
+		req  RollRequest
+		code int
+	}{
+		{"invalid expression", RollRequest{Expression: "3D"}, http.StatusBadRequest},
+		{"damage bonus without investigator", RollRequest{Expression: "1D6+DB"}, http.StatusBadRequest},
+		{"unknown investigator", RollRequest{Expression: "1D6+DB", InvestigatorID: "missing"}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run("rejects "+tt.name, func(t *testing.T) {
+			h, _ := newTestHandler()
+
+			w := roll(h, tt.req)
+
+			if w.Code != tt.code {
+				t.Errorf("expected status %d, got %d", tt.code, w.Code)
+			}
+		})
+	}
+}
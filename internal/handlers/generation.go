@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/internal/validation"
+	"book-of-shadows/models"
+	"book-of-shadows/storage"
+)
+
+// GenerationRequest is the body of a request picking a generation method
+type GenerationRequest struct {
+	Method string `json:"method"`
+}
+
+// GenerationResponse is the generation method of an investigator with what it assigns from
+type GenerationResponse struct {
+	Method models.GenerationMethod `json:"method"`
+	Values []int                   `json:"values,omitempty"` // The set assigned between the characteristics
+	Points int                     `json:"points,omitempty"` // The pool divided between them with point-buy
+}
+
+// generationResponse describes the generation method of an investigator
+func generationResponse(investigator *models.Investigator) GenerationResponse {
+	resp := GenerationResponse{Method: investigator.Generation(), Values: investigator.GenerationValues()}
+	if resp.Method == models.GenerationPointBuy {
+		resp.Points = models.PointBuyPool
+	}
+	return resp
+}
+
+// SetGenerationMethod picks how an investigator's characteristics are generated, rolling
+// the set to assign from for roll and assign
+func (h *Handler) SetGenerationMethod(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) == 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing investigator ID", nil))
+		return
+	}
+	id := params[0]
+
+	var req GenerationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid JSON", err))
+		return
+	}
+	defer r.Body.Close()
+
+	method, err := models.ParseGenerationMethod(req.Method)
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, err.Error(), err))
+		return
+	}
+
+	ctx := r.Context()
+	ownerID := storage.OwnerFromContext(ctx)
+	investigator, err := h.store.GetInvestigator(ctx, ownerID, id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	if investigator.Aging != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusConflict, "Characteristics were already aged", nil))
+		return
+	}
+
+	snapshot, err := investigator.ToJSON()
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	oldMethod := investigator.Generation()
+	violations := validation.Check(investigator)
+	investigator.SetGenerationMethod(newRand(), method)
+
+	if err := h.saveInvestigator(ctx, ownerID, id, investigator, violations); err != nil {
+		h.respondError(w, err)
+		return
+	}
+	h.recordRevision(ctx, id, "generation", "Method", oldMethod, method, snapshot)
+
+	h.respondJSON(w, http.StatusOK, generationResponse(investigator))
+}
+
+// AssignCharacteristics sets every characteristic at once, keyed by abbreviation, checking
+// them against the investigator's generation method
+func (h *Handler) AssignCharacteristics(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) == 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing investigator ID", nil))
+		return
+	}
+	id := params[0]
+
+	var data map[string]int
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid JSON", err))
+		return
+	}
+	defer r.Body.Close()
+
+	ctx := r.Context()
+	ownerID := storage.OwnerFromContext(ctx)
+	investigator, err := h.store.GetInvestigator(ctx, ownerID, id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	if investigator.Aging != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusConflict, "Characteristics were already aged", nil))
+		return
+	}
+
+	snapshot, err := investigator.ToJSON()
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	violations := validation.Check(investigator)
+
+	if err := investigator.InvestigatorUpdateAttributes(data); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid characteristics: "+err.Error(), err))
+		return
+	}
+	h.resetSanityAndMagic(investigator)
+	h.recalculateDependentAttributes(investigator)
+
+	if err := h.saveInvestigator(ctx, ownerID, id, investigator, violations); err != nil {
+		h.respondError(w, err)
+		return
+	}
+	h.recordRevision(ctx, id, "attributes", "Characteristics", nil, data, snapshot)
+
+	h.respondJSON(w, http.StatusOK, investigator.Attributes)
+}
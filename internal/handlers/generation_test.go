@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"book-of-shadows/models"
+)
+
+// setGeneration picks a generation method as owner-1
+func setGeneration(h *Handler, id, method string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(GenerationRequest{Method: method})
+	w := httptest.NewRecorder()
+	h.SetGenerationMethod(w, withOwner(requestWithParams("POST", "/api/investigator/"+id+"/generation", body, []string{id}), "owner-1", nil))
+	return w
+}
+
+func TestSetGenerationMethod(t *testing.T) {
+	// decode reads the generation method from a successful response
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) GenerationResponse {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var result GenerationResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return result
+	}
+
+	t.Run("offers the quick-fire array and the point-buy pool", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := agingInvestigator(store, 30)
+
+		if result := decode(t, setGeneration(h, inv.ID, "quick-fire")); !slices.Equal(result.Values, models.QuickFireArray) {
+			t.Errorf("expected the quick-fire array, got %v", result.Values)
+		}
+		if result := decode(t, setGeneration(h, inv.ID, "point-buy")); result.Points != models.PointBuyPool || result.Values != nil {
+			t.Errorf("expected a pool of %d points, got %+v", models.PointBuyPool, result)
+		}
+		if len(store.revisions) != 2 || store.revisions[1].Section != "generation" {
+			t.Error("expected each change of method to be recorded in the history")
+		}
+	})
+
+	t.Run("rolls the values to assign on the server", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := agingInvestigator(store, 30)
+
+		result := decode(t, setGeneration(h, inv.ID, "roll-and-assign"))
+		if len(result.Values) != 8 || !slices.Equal(result.Values, store.investigators[inv.ID].GenerationPool) {
+			t.Fatalf("expected 8 values kept on the investigator, got %v", result.Values)
+		}
+		for _, value := range result.Values {
+			if value < 15 || value > 90 || value%5 != 0 {
+				t.Errorf("expected a characteristic roll, got %d", value)
+			}
+		}
+	})
+
+	t.Run("keeps the rolled values when the method is picked again", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := agingInvestigator(store, 30)
+
+		first := decode(t, setGeneration(h, inv.ID, "roll-and-assign"))
+		decode(t, setGeneration(h, inv.ID, "roll-and-assign"))
+		decode(t, setGeneration(h, inv.ID, "quick-fire"))
+		if again := decode(t, setGeneration(h, inv.ID, "roll-and-assign")); !slices.Equal(again.Values, first.Values) {
+			t.Errorf("expected the first roll %v to be kept, got %v", first.Values, again.Values)
+		}
+	})
+
+	t.Run("rejects unknown methods and aged investigators", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := agingInvestigator(store, 30)
+
+		if w := setGeneration(h, inv.ID, "standard-array"); w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+		inv.Aging = &models.AgingReport{Age: 30}
+		if w := setGeneration(h, inv.ID, "quick-fire"); w.Code != http.StatusConflict {
+			t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+		}
+	})
+}
+
+func TestAssignCharacteristics(t *testing.T) {
+	// characteristics builds a body from the eight generated values and Luck
+	characteristics := func(values ...int) map[string]int {
+		data := map[string]int{"LCK": 55}
+		for n, name := range []string{"STR", "CON", "SIZ", "DEX", "APP", "INT", "POW", "EDU"} {
+			data[name] = values[n]
+		}
+		return data
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		data   map[string]int
+		status int
+	}{
+		{"quick-fire array in any order", "quick-fire", characteristics(80, 50, 60, 70, 50, 60, 40, 50), http.StatusOK},
+		{"quick-fire value used twice", "quick-fire", characteristics(80, 80, 60, 70, 50, 60, 40, 50), http.StatusBadRequest},
+		{"point-buy spending the pool", "point-buy", characteristics(90, 15, 60, 60, 55, 70, 50, 60), http.StatusOK},
+		{"point-buy overspending", "point-buy", characteristics(90, 20, 60, 60, 55, 70, 50, 60), http.StatusBadRequest},
+		{"point-buy above 90", "point-buy", characteristics(95, 10, 60, 60, 55, 70, 50, 60), http.StatusBadRequest},
+		{"rolled values within the rules", "rolled", characteristics(35, 45, 65, 50, 60, 75, 40, 85), http.StatusOK},
+		{"missing Luck", "rolled", map[string]int{"STR": 50}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, store := newTestHandler()
+			inv := agingInvestigator(store, 30)
+			if w := setGeneration(h, inv.ID, tt.method); w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+
+			body, _ := json.Marshal(tt.data)
+			w := httptest.NewRecorder()
+			h.AssignCharacteristics(w, withOwner(requestWithParams("POST", "/api/investigator/"+inv.ID+"/characteristics", body, []string{inv.ID}), "owner-1", nil))
+
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			saved := store.investigators[inv.ID]
+			if tt.status != http.StatusOK {
+				if saved.Attributes[models.AttrStrength].Value != 50 {
+					t.Error("expected rejected characteristics to leave the investigator untouched")
+				}
+				return
+			}
+			if saved.Attributes[models.AttrStrength].Value != tt.data["STR"] || saved.Attributes[models.AttrLuck].Value != 55 {
+				t.Errorf("expected the characteristics to be saved, got %v", saved.Attributes)
+			}
+			if saved.Attributes[models.AttrSanity].Value != tt.data["POW"] || saved.FreePoints != tt.data["INT"]*2 {
+				t.Error("expected Sanity and the personal interest points to follow the new characteristics")
+			}
+		})
+	}
+
+	t.Run("assigns the values rolled for the investigator", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := agingInvestigator(store, 30)
+		setGeneration(h, inv.ID, "roll-and-assign")
+
+		pool := store.investigators[inv.ID].GenerationPool
+		body, _ := json.Marshal(characteristics(pool...))
+		w := httptest.NewRecorder()
+		h.AssignCharacteristics(w, withOwner(requestWithParams("POST", "/api/investigator/"+inv.ID+"/characteristics", body, []string{inv.ID}), "owner-1", nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestUpdateAttributeFollowsGeneration(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		aged   bool
+		field  string
+		value  int
+		status int
+	}{
+		{"rolled characteristic", "rolled", false, models.AttrStrength, 65, http.StatusOK},
+		{"rolled characteristic above 99", "rolled", false, models.AttrStrength, 120, http.StatusBadRequest},
+		{"point-buy characteristic on its own", "point-buy", false, models.AttrStrength, 65, http.StatusBadRequest},
+		{"quick-fire characteristic on its own", "quick-fire", false, models.AttrEducation, 80, http.StatusBadRequest},
+		{"Luck rolled on its own with point-buy", "point-buy", false, models.AttrLuck, 65, http.StatusOK},
+		{"point-buy characteristic after aging", "point-buy", true, models.AttrStrength, 45, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, store := newTestHandler()
+			inv := agingInvestigator(store, 30)
+			setGeneration(h, inv.ID, tt.method)
+			if tt.aged {
+				inv.Aging = &models.AgingReport{Age: 30}
+			}
+
+			body, _ := json.Marshal(UpdateRequest{Section: "attributes", Field: tt.field, Value: tt.value})
+			w := httptest.NewRecorder()
+			h.UpdateInvestigator(w, withOwner(requestWithParams("PUT", "/api/investigator/"+inv.ID, body, []string{inv.ID}), "owner-1", nil))
+
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			want := 50
+			if tt.status == http.StatusOK {
+				want = tt.value
+			}
+			if got := store.investigators[inv.ID].Attributes[tt.field].Value; got != want {
+				t.Errorf("expected %s %d, got %d", tt.field, want, got)
+			}
+		})
+	}
+}
+
+func TestUpdateCombatKeepsToResources(t *testing.T) {
+	tests := []struct {
+		field  string
+		status int
+	}{
+		{models.AttrHitPoints, http.StatusOK},
+		{models.AttrLuck, http.StatusOK},
+		{models.AttrStrength, http.StatusBadRequest},
+		{models.AttrEducation, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			h, store := newTestHandler()
+			inv := agingInvestigator(store, 30)
+			setGeneration(h, inv.ID, "point-buy")
+
+			body, _ := json.Marshal(UpdateRequest{Section: "combat", Field: tt.field, Value: 5})
+			w := httptest.NewRecorder()
+			h.UpdateInvestigator(w, withOwner(requestWithParams("PUT", "/api/investigator/"+inv.ID, body, []string{inv.ID}), "owner-1", nil))
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
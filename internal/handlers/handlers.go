@@ -5,8 +5,11 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strconv"
 
+	"book-of-shadows/internal/config"
 	"book-of-shadows/internal/errors"
+	"book-of-shadows/internal/validation"
 	"book-of-shadows/models"
 	"book-of-shadows/storage"
 	"book-of-shadows/views"
@@ -15,17 +18,21 @@ import (
 // Handler holds dependencies for HTTP handlers
 type Handler struct {
 	store  storage.Store
+	auth   *config.AuthConfig
 	logger *log.Logger
+	sheet  *characterSheet
 }
 
 // New creates a new Handler with dependencies
-func New(store storage.Store, logger *log.Logger) *Handler {
+func New(store storage.Store, auth *config.AuthConfig, logger *log.Logger) *Handler {
 	if logger == nil {
 		logger = log.New(io.Discard, "", 0)
 	}
 	return &Handler{
 		store:  store,
+		auth:   auth,
 		logger: logger,
+		sheet:  &characterSheet{path: sheetTemplatePath},
 	}
 }
 
@@ -40,6 +47,15 @@ func (h *Handler) respondJSON(w http.ResponseWriter, status int, data interface{
 
 // respondError sends an error response
 func (h *Handler) respondError(w http.ResponseWriter, err error) {
+	// Strict investigators list the rules a refused change breaks
+	if e, ok := err.(validation.RulesError); ok {
+		h.respondJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"error":      "Update breaks the rules",
+			"violations": e.Violations,
+		})
+		return
+	}
+
 	var httpErr errors.HTTPError
 	if e, ok := err.(errors.HTTPError); ok {
 		httpErr = e
@@ -52,6 +68,8 @@ func (h *Handler) respondError(w http.ResponseWriter, err error) {
 			httpErr = errors.NewHTTPError(http.StatusBadRequest, "Invalid request", err)
 		case errors.ErrAlreadyExists:
 			httpErr = errors.NewHTTPError(http.StatusConflict, "Resource already exists", err)
+		case errors.ErrInvalidCookie:
+			httpErr = errors.NewHTTPError(http.StatusBadRequest, "Invalid cookie", err)
 		case errors.ErrCookieTooLarge:
 			httpErr = errors.NewHTTPError(http.StatusRequestEntityTooLarge, "Data too large", err)
 		default:
@@ -85,13 +103,28 @@ func (h *Handler) Generate(w http.ResponseWriter, r *http.Request) {
 	if modeParam == "classic" {
 		mode = models.Classic
 	}
+	era, err := eraFromQuery(r)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
 
-	// Generate investigator
-	investigator := models.RandomInvestigator(mode)
+	// Generate investigator, the same seed always gives the same investigator
+	var investigator *models.Investigator
+	if seedParam := r.URL.Query().Get("seed"); seedParam == "" {
+		investigator = models.RandomInvestigator(mode, era)
+	} else {
+		seed, err := strconv.ParseInt(seedParam, 10, 64)
+		if err != nil || seed < 1 {
+			h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Seed must be a positive whole number", err))
+			return
+		}
+		investigator = models.SeededInvestigator(mode, era, seed)
+	}
 
-	// Save to cookie
-	_, err := h.store.SaveInvestigator(w, investigator)
-	if err != nil {
+	// Save investigator
+	ctx := r.Context()
+	if _, err := h.store.SaveInvestigator(ctx, storage.OwnerFromContext(ctx), investigator); err != nil {
 		h.respondError(w, err)
 		return
 	}
@@ -106,7 +139,8 @@ func (h *Handler) Generate(w http.ResponseWriter, r *http.Request) {
 
 // ListInvestigators returns all investigators
 func (h *Handler) ListInvestigators(w http.ResponseWriter, r *http.Request) {
-	investigators, err := h.store.ListInvestigators(r)
+	ctx := r.Context()
+	investigators, err := h.store.ListInvestigators(ctx, storage.OwnerFromContext(ctx))
 	if err != nil {
 		h.respondError(w, err)
 		return
@@ -129,7 +163,8 @@ func (h *Handler) GetInvestigator(w http.ResponseWriter, r *http.Request) {
 	}
 	id := params[0]
 
-	investigator, err := h.store.GetInvestigator(r, id)
+	ctx := r.Context()
+	investigator, err := h.store.GetInvestigator(ctx, storage.OwnerFromContext(ctx), id)
 	if err != nil {
 		h.respondError(w, err)
 		return
@@ -164,8 +199,9 @@ func (h *Handler) CreateInvestigator(w http.ResponseWriter, r *http.Request) {
 	// Create investigator
 	investigator := models.InvestigatorBaseCreate(processedPayload)
 
-	// Save to cookie
-	key, err := h.store.SaveInvestigator(w, investigator)
+	// Save investigator
+	ctx := r.Context()
+	key, err := h.store.SaveInvestigator(ctx, storage.OwnerFromContext(ctx), investigator)
 	if err != nil {
 		h.respondError(w, err)
 		return
@@ -188,7 +224,9 @@ func (h *Handler) UpdateInvestigator(w http.ResponseWriter, r *http.Request) {
 	id := params[0]
 
 	// Get existing investigator
-	investigator, err := h.store.GetInvestigator(r, id)
+	ctx := r.Context()
+	ownerID := storage.OwnerFromContext(ctx)
+	investigator, err := h.store.GetInvestigator(ctx, ownerID, id)
 	if err != nil {
 		h.respondError(w, err)
 		return
@@ -208,6 +246,15 @@ func (h *Handler) UpdateInvestigator(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Keep the sheet as it was so the change can be undone
+	snapshot, err := investigator.ToJSON()
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	oldValue := revisionValue(investigator, &updateReq)
+	violations := validation.Check(investigator)
+
 	// Apply updates
 	if err := h.applyInvestigatorUpdate(investigator, &updateReq); err != nil {
 		h.respondError(w, err)
@@ -215,11 +262,13 @@ func (h *Handler) UpdateInvestigator(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Save updated investigator
-	if err := h.store.UpdateInvestigator(w, id, investigator); err != nil {
+	if err := h.saveInvestigator(ctx, ownerID, id, investigator, violations); err != nil {
 		h.respondError(w, err)
 		return
 	}
 
+	h.recordRevision(ctx, id, updateReq.Section, updateReq.Field, oldValue, revisionValue(investigator, &updateReq), snapshot)
+
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -233,7 +282,8 @@ func (h *Handler) DeleteInvestigator(w http.ResponseWriter, r *http.Request) {
 	}
 	id := params[0]
 
-	if err := h.store.DeleteInvestigator(w, id); err != nil {
+	ctx := r.Context()
+	if err := h.store.DeleteInvestigator(ctx, storage.OwnerFromContext(ctx), id); err != nil {
 		h.respondError(w, err)
 		return
 	}
@@ -244,7 +294,8 @@ func (h *Handler) DeleteInvestigator(w http.ResponseWriter, r *http.Request) {
 
 // ExportInvestigatorsList exports all investigators for sharing
 func (h *Handler) ExportInvestigatorsList(w http.ResponseWriter, r *http.Request) {
-	exportCode, err := h.store.ExportInvestigatorsList(r)
+	ctx := r.Context()
+	exportCode, err := h.store.ExportInvestigatorsList(ctx, storage.OwnerFromContext(ctx))
 	if err != nil {
 		h.respondError(w, err)
 		return
@@ -274,7 +325,8 @@ func (h *Handler) ImportInvestigatorsList(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	if err := h.store.ImportInvestigatorsList(w, importCode); err != nil {
+	ctx := r.Context()
+	if err := h.store.ImportInvestigatorsList(ctx, storage.OwnerFromContext(ctx), importCode); err != nil {
 		h.respondError(w, err)
 		return
 	}
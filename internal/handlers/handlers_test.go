@@ -5,20 +5,33 @@ import (
 	"context"
 	"encoding/json"
 	stderrors "errors"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"book-of-shadows/internal/config"
 	"book-of-shadows/internal/errors"
 	"book-of-shadows/models"
+	"book-of-shadows/storage"
 )
 
 // MockStore implements storage.Store for testing
 type MockStore struct {
 	investigators map[string]*models.Investigator
+	owners        map[string]string
 	exports       map[string]string
+	users         map[string]*models.User
+	passwords     map[string]string
+	sessions      map[string]*models.User
+	claimed       map[string]string
+	revisions     []*models.Revision
+	campaigns     map[string]*models.Campaign
 	saveError     error
 	getError      error
 }
@@ -26,10 +39,22 @@ type MockStore struct {
 func NewMockStore() *MockStore {
 	return &MockStore{
 		investigators: make(map[string]*models.Investigator),
+		owners:        make(map[string]string),
 		exports:       make(map[string]string),
+		users:         make(map[string]*models.User),
+		passwords:     make(map[string]string),
+		sessions:      make(map[string]*models.User),
+		claimed:       make(map[string]string),
+		campaigns:     make(map[string]*models.Campaign),
 	}
 }
 
+// ownedByOther reports whether an investigator was saved by a different owner
+func (m *MockStore) ownedByOther(ownerID, id string) bool {
+	owner, ok := m.owners[id]
+	return ok && owner != ownerID
+}
+
 // ExportStore methods
 func (m *MockStore) SaveExport(data string) (string, error) {
 	if m.saveError != nil {
@@ -56,60 +81,248 @@ func (m *MockStore) DeleteExpiredExports() error {
 }
 
 // InvestigatorStore methods
-func (m *MockStore) SaveInvestigator(w http.ResponseWriter, inv *models.Investigator) (string, error) {
+func (m *MockStore) SaveInvestigator(ctx context.Context, ownerID string, inv *models.Investigator) (string, error) {
 	if m.saveError != nil {
 		return "", m.saveError
 	}
 	id := "test-inv-id"
 	inv.ID = id
 	m.investigators[id] = inv
+	m.owners[id] = ownerID
 	return id, nil
 }
 
-func (m *MockStore) GetInvestigator(r *http.Request, id string) (*models.Investigator, error) {
+func (m *MockStore) GetInvestigator(ctx context.Context, ownerID, id string) (*models.Investigator, error) {
 	if m.getError != nil {
 		return nil, m.getError
 	}
 	inv, ok := m.investigators[id]
-	if !ok {
+	if !ok || m.ownedByOther(ownerID, id) {
 		return nil, errors.ErrNotFound
 	}
 	return inv, nil
 }
 
-func (m *MockStore) UpdateInvestigator(w http.ResponseWriter, id string, inv *models.Investigator) error {
-	if _, ok := m.investigators[id]; !ok {
+func (m *MockStore) UpdateInvestigator(ctx context.Context, ownerID, id string, inv *models.Investigator) error {
+	if _, ok := m.investigators[id]; !ok || m.ownedByOther(ownerID, id) {
 		return errors.ErrNotFound
 	}
 	m.investigators[id] = inv
 	return nil
 }
 
-func (m *MockStore) DeleteInvestigator(w http.ResponseWriter, id string) error {
-	if _, ok := m.investigators[id]; !ok {
+func (m *MockStore) DeleteInvestigator(ctx context.Context, ownerID, id string) error {
+	if _, ok := m.investigators[id]; !ok || m.ownedByOther(ownerID, id) {
 		return errors.ErrNotFound
 	}
 	delete(m.investigators, id)
 	return nil
 }
 
-func (m *MockStore) ListInvestigators(r *http.Request) (map[string]*models.Investigator, error) {
+func (m *MockStore) ListInvestigators(ctx context.Context, ownerID string) (map[string]*models.Investigator, error) {
 	return m.investigators, nil
 }
 
-func (m *MockStore) ExportInvestigatorsList(r *http.Request) (string, error) {
+func (m *MockStore) ExportInvestigatorsList(ctx context.Context, ownerID string) (string, error) {
 	return "test-export-code", nil
 }
 
-func (m *MockStore) ImportInvestigatorsList(w http.ResponseWriter, uuid string) error {
+func (m *MockStore) ImportInvestigatorsList(ctx context.Context, ownerID, uuid string) error {
+	return nil
+}
+
+func (m *MockStore) ClaimInvestigators(ctx context.Context, fromOwnerID, toUserID string) error {
+	m.claimed[fromOwnerID] = toUserID
+	return nil
+}
+
+// UserStore methods
+func (m *MockStore) CreateUser(ctx context.Context, username, password string) (*models.User, error) {
+	if _, ok := m.users[username]; ok {
+		return nil, errors.ErrAlreadyExists
+	}
+	user := &models.User{ID: "user-" + username, Username: username}
+	m.users[username] = user
+	m.passwords[username] = password
+	return user, nil
+}
+
+func (m *MockStore) AuthenticateUser(ctx context.Context, username, password string) (*models.User, error) {
+	user, ok := m.users[username]
+	if !ok || m.passwords[username] != password {
+		return nil, errors.ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+func (m *MockStore) CreateSession(ctx context.Context, userID string) (string, error) {
+	for _, user := range m.users {
+		if user.ID == userID {
+			token := "token-" + userID
+			m.sessions[token] = user
+			return token, nil
+		}
+	}
+	return "", errors.ErrNotFound
+}
+
+func (m *MockStore) GetSessionUser(ctx context.Context, token string) (*models.User, error) {
+	user, ok := m.sessions[token]
+	if !ok {
+		return nil, errors.ErrNotFound
+	}
+	return user, nil
+}
+
+func (m *MockStore) DeleteSession(ctx context.Context, token string) error {
+	delete(m.sessions, token)
+	return nil
+}
+
+// RevisionStore methods
+func (m *MockStore) SaveRevision(ctx context.Context, ownerID string, rev *models.Revision) error {
+	rev.ID = int64(len(m.revisions) + 1)
+	m.revisions = append(m.revisions, rev)
 	return nil
 }
 
+func (m *MockStore) GetRevision(ctx context.Context, ownerID, investigatorID string, revisionID int64) (*models.Revision, error) {
+	for _, rev := range m.revisions {
+		if rev.ID == revisionID && rev.InvestigatorID == investigatorID && rev.AuthorID == ownerID {
+			return rev, nil
+		}
+	}
+	return nil, errors.ErrNotFound
+}
+
+func (m *MockStore) ListRevisions(ctx context.Context, ownerID, investigatorID string) ([]*models.Revision, error) {
+	revisions := make([]*models.Revision, 0)
+	for i := len(m.revisions) - 1; i >= 0; i-- {
+		if m.revisions[i].InvestigatorID == investigatorID && m.revisions[i].AuthorID == ownerID {
+			revisions = append(revisions, m.revisions[i])
+		}
+	}
+	return revisions, nil
+}
+
+func (m *MockStore) DeleteRevisions(ctx context.Context, ownerID, investigatorID string) error {
+	return nil
+}
+
+func (m *MockStore) CreateCampaign(ctx context.Context, keeperID string, campaign *models.Campaign) (string, error) {
+	campaign.ID = fmt.Sprintf("campaign-%d", len(m.campaigns)+1)
+	campaign.KeeperID = keeperID
+	campaign.JoinCode = fmt.Sprintf("CODE%d", len(m.campaigns)+1)
+	m.campaigns[campaign.ID] = campaign
+	return campaign.ID, nil
+}
+
+func (m *MockStore) GetCampaign(ctx context.Context, keeperID, id string) (*models.Campaign, error) {
+	campaign, ok := m.campaigns[id]
+	if !ok || campaign.KeeperID != keeperID {
+		return nil, errors.ErrNotFound
+	}
+	return campaign, nil
+}
+
+func (m *MockStore) ListCampaigns(ctx context.Context, keeperID string) ([]*models.Campaign, error) {
+	campaigns := make([]*models.Campaign, 0)
+	for _, campaign := range m.campaigns {
+		if campaign.KeeperID == keeperID {
+			campaigns = append(campaigns, campaign)
+		}
+	}
+	return campaigns, nil
+}
+
+func (m *MockStore) DeleteCampaign(ctx context.Context, keeperID, id string) error {
+	if _, err := m.GetCampaign(ctx, keeperID, id); err != nil {
+		return err
+	}
+	delete(m.campaigns, id)
+	return nil
+}
+
+func (m *MockStore) JoinCampaign(ctx context.Context, joinCode, ownerID string, inv *models.Investigator) (*models.Campaign, error) {
+	for _, campaign := range m.campaigns {
+		if campaign.JoinCode == joinCode {
+			campaign.Members = append(campaign.Members, models.CampaignMember{
+				InvestigatorID: inv.ID,
+				OwnerID:        ownerID,
+				Investigator:   inv,
+			})
+			return campaign, nil
+		}
+	}
+	return nil, errors.ErrNotFound
+}
+
+func (m *MockStore) RemoveCampaignMember(ctx context.Context, keeperID, campaignID, investigatorID string) error {
+	campaign, err := m.GetCampaign(ctx, keeperID, campaignID)
+	if err != nil {
+		return err
+	}
+	for i, member := range campaign.Members {
+		if member.InvestigatorID == investigatorID {
+			campaign.Members = append(campaign.Members[:i], campaign.Members[i+1:]...)
+			return nil
+		}
+	}
+	return errors.ErrNotFound
+}
+
+func (m *MockStore) GetCampaignMember(ctx context.Context, keeperID, campaignID, investigatorID string) (*models.CampaignMember, error) {
+	campaign, err := m.GetCampaign(ctx, keeperID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range campaign.Members {
+		if member.InvestigatorID == investigatorID {
+			return &member, nil
+		}
+	}
+	return nil, errors.ErrNotFound
+}
+
+func (m *MockStore) GetMemberInvestigator(ctx context.Context, member *models.CampaignMember) (*models.Investigator, error) {
+	return m.GetInvestigator(ctx, member.OwnerID, member.InvestigatorID)
+}
+
+func (m *MockStore) UpdateMemberInvestigator(ctx context.Context, member *models.CampaignMember, inv *models.Investigator) error {
+	return m.UpdateInvestigator(ctx, member.OwnerID, member.InvestigatorID, inv)
+}
+
+func (m *MockStore) SyncCampaignMember(ctx context.Context, ownerID, investigatorID string, inv *models.Investigator) error {
+	return nil
+}
+
+func (m *MockStore) LeaveCampaigns(ctx context.Context, ownerID, investigatorID string) error {
+	return nil
+}
+
+// testAuthConfig returns the account settings used by handler tests
+func testAuthConfig() *config.AuthConfig {
+	return &config.AuthConfig{
+		SessionCookie:     "session",
+		SessionTTL:        time.Hour,
+		MinPasswordLength: 8,
+	}
+}
+
 // Helper to create a test handler
 func newTestHandler() (*Handler, *MockStore) {
 	store := NewMockStore()
 	logger := log.New(io.Discard, "", 0)
-	return New(store, logger), store
+	return New(store, testAuthConfig(), logger), store
+}
+
+// withOwner attaches an owner and optionally a logged in user to a request
+func withOwner(req *http.Request, ownerID string, user *models.User) *http.Request {
+	ctx := storage.WithOwner(req.Context(), ownerID)
+	if user != nil {
+		ctx = storage.WithUser(ctx, user)
+	}
+	return req.WithContext(ctx)
 }
 
 // Helper to create a request with params context
@@ -131,7 +344,7 @@ func TestNew(t *testing.T) {
 	t.Run("creates handler with store and logger", func(t *testing.T) {
 		store := NewMockStore()
 		logger := log.New(io.Discard, "", 0)
-		h := New(store, logger)
+		h := New(store, testAuthConfig(), logger)
 		if h == nil {
 			t.Error("expected non-nil handler")
 		}
@@ -139,7 +352,7 @@ func TestNew(t *testing.T) {
 
 	t.Run("creates handler with nil logger", func(t *testing.T) {
 		store := NewMockStore()
-		h := New(store, nil)
+		h := New(store, testAuthConfig(), nil)
 		if h == nil {
 			t.Error("expected non-nil handler")
 		}
@@ -187,6 +400,7 @@ func TestRespondError(t *testing.T) {
 		{"invalid data", errors.ErrInvalidData, http.StatusBadRequest},
 		{"invalid attribute", errors.ErrInvalidAttribute, http.StatusBadRequest},
 		{"already exists", errors.ErrAlreadyExists, http.StatusConflict},
+		{"invalid cookie", errors.ErrInvalidCookie, http.StatusBadRequest},
 		{"cookie too large", errors.ErrCookieTooLarge, http.StatusRequestEntityTooLarge},
 		{"generic error", stderrors.New("unknown error"), http.StatusInternalServerError},
 	}
@@ -260,6 +474,169 @@ func TestDeleteInvestigator(t *testing.T) {
 	})
 }
 
+func TestGenerate(t *testing.T) {
+	for _, mode := range []string{"pulp", "classic"} {
+		t.Run("renders a random "+mode+" investigator", func(t *testing.T) {
+			h, store := newTestHandler()
+
+			req := withOwner(httptest.NewRequest("GET", "/api/generate/?mode="+mode, nil), "owner-1", nil)
+			w := httptest.NewRecorder()
+
+			h.Generate(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			for _, inv := range store.investigators {
+				if inv.IsPulp() != (mode == "pulp") {
+					t.Errorf("expected %s investigator, got %s", mode, inv.GameMode)
+				}
+				if strings.Contains(w.Body.String(), "Pulp Talents") != inv.IsPulp() {
+					t.Errorf("expected the Pulp Talents section only on pulp sheets")
+				}
+			}
+		})
+	}
+
+	t.Run("generates an investigator of the requested era", func(t *testing.T) {
+		h, store := newTestHandler()
+
+		req := withOwner(httptest.NewRequest("GET", "/api/generate/?mode=classic&era=gaslight", nil), "owner-1", nil)
+		w := httptest.NewRecorder()
+
+		h.Generate(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		for _, inv := range store.investigators {
+			if inv.Era != models.Gaslight {
+				t.Errorf("expected a gaslight investigator, got %s", inv.Era)
+			}
+			for _, name := range []string{"Computer Use", "Electronics", "Drive Auto"} {
+				if _, ok := inv.Skills[name]; ok {
+					t.Errorf("expected no %s skill in the gaslight era", name)
+				}
+			}
+			// Skills without eras, such as Dodge, belong to every era
+			for name, skill := range inv.Skills {
+				if len(skill.Era) > 0 && !slices.Contains(skill.Era, models.Gaslight) {
+					t.Errorf("expected %s to belong to the gaslight era, got %v", name, skill.Era)
+				}
+			}
+			if !inv.Occupation.AvailableIn(models.Gaslight) {
+				t.Errorf("expected a gaslight occupation, got %s", inv.Occupation.Name)
+			}
+		}
+	})
+
+	t.Run("generates the same investigator from the same seed", func(t *testing.T) {
+		var generated []string
+		for i := 0; i < 2; i++ {
+			h, store := newTestHandler()
+
+			req := withOwner(httptest.NewRequest("GET", "/api/generate/?mode=pulp&era=1920s&seed=1234", nil), "owner-1", nil)
+			w := httptest.NewRecorder()
+
+			h.Generate(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			for _, inv := range store.investigators {
+				if inv.Seed != 1234 {
+					t.Errorf("expected seed 1234 on the investigator, got %d", inv.Seed)
+				}
+				inv.ID = ""
+				data, _ := json.Marshal(inv)
+				generated = append(generated, string(data))
+			}
+		}
+		if len(generated) != 2 || generated[0] != generated[1] {
+			t.Error("expected both investigators generated from seed 1234 to be identical")
+		}
+	})
+
+	t.Run("rejects an invalid seed", func(t *testing.T) {
+		for _, seed := range []string{"abc", "0", "-5"} {
+			h, _ := newTestHandler()
+
+			req := withOwner(httptest.NewRequest("GET", "/api/generate/?seed="+seed, nil), "owner-1", nil)
+			w := httptest.NewRecorder()
+
+			h.Generate(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d for seed %q, got %d", http.StatusBadRequest, seed, w.Code)
+			}
+		}
+	})
+
+	t.Run("rejects an unknown era", func(t *testing.T) {
+		h, store := newTestHandler()
+
+		req := withOwner(httptest.NewRequest("GET", "/api/generate/?era=stone+age", nil), "owner-1", nil)
+		w := httptest.NewRecorder()
+
+		h.Generate(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+		if len(store.investigators) != 0 {
+			t.Errorf("expected nothing saved, got %d investigators", len(store.investigators))
+		}
+	})
+}
+
+func TestListOccupations(t *testing.T) {
+	tests := []struct {
+		era      string
+		included []string
+		excluded []string
+	}{
+		{"gaslight", []string{"Alienist", "Coachman", "Consulting Detective"}, []string{"Aviator", "Gangster, Boss", "Parapsychologist", "Street Punk"}},
+		{"1920s", []string{"Aviator", "Gangster, Boss"}, []string{"Alienist", "Coachman"}},
+		{"modern", []string{"Parapsychologist", "Street Punk"}, []string{"Consulting Detective"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.era, func(t *testing.T) {
+			h, _ := newTestHandler()
+
+			req := httptest.NewRequest("GET", "/api/occupations/?era="+tt.era, nil)
+			w := httptest.NewRecorder()
+
+			h.ListOccupations(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			var response ArchetypeOccupationsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			era, _ := models.ParseEra(tt.era)
+			if len(response.Others) != len(models.OccupationsForEra(era)) {
+				t.Errorf("expected %d occupations, got %d", len(models.OccupationsForEra(era)), len(response.Others))
+			}
+			names := make(map[string]bool)
+			for _, occupation := range response.Others {
+				names[occupation.Name] = true
+			}
+			for _, name := range tt.included {
+				if !names[name] {
+					t.Errorf("expected %s in the %s era", name, tt.era)
+				}
+			}
+			for _, name := range tt.excluded {
+				if names[name] {
+					t.Errorf("expected no %s in the %s era", name, tt.era)
+				}
+			}
+		})
+	}
+}
+
 func TestCreateInvestigator(t *testing.T) {
 	t.Run("creates investigator with valid data", func(t *testing.T) {
 		h, _ := newTestHandler()
@@ -292,6 +669,70 @@ func TestCreateInvestigator(t *testing.T) {
 		}
 	})
 
+	t.Run("creates classic investigator without archetype", func(t *testing.T) {
+		h, store := newTestHandler()
+
+		payload := map[string]interface{}{
+			"name":       "Test Investigator",
+			"age":        "30",
+			"residence":  "Boston",
+			"birthplace": "New York",
+			"mode":       "classic",
+			"occupation": "Antiquarian",
+		}
+		body, _ := json.Marshal(payload)
+
+		req := requestWithParams("POST", "/api/investigator/", body, nil)
+		w := httptest.NewRecorder()
+
+		h.CreateInvestigator(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+
+		var result map[string]string
+		json.Unmarshal(w.Body.Bytes(), &result)
+		inv := store.investigators[result["Key"]]
+		if inv.GameMode != models.Classic || inv.Archetype != nil || inv.ArchetypePoints != 0 {
+			t.Errorf("expected classic investigator without archetype, got mode %s and archetype %v", inv.GameMode, inv.Archetype)
+		}
+	})
+
+	t.Run("creates investigator in the chosen era", func(t *testing.T) {
+		h, store := newTestHandler()
+
+		payload := map[string]interface{}{
+			"name":       "Test Investigator",
+			"age":        "30",
+			"residence":  "Boston",
+			"birthplace": "New York",
+			"mode":       "classic",
+			"era":        "1920s",
+			"occupation": "Antiquarian",
+		}
+		body, _ := json.Marshal(payload)
+
+		req := requestWithParams("POST", "/api/investigator/", body, nil)
+		w := httptest.NewRecorder()
+
+		h.CreateInvestigator(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+
+		var result map[string]string
+		json.Unmarshal(w.Body.Bytes(), &result)
+		inv := store.investigators[result["Key"]]
+		if inv.Era != models.Twenties {
+			t.Errorf("expected a 1920s investigator, got %s", inv.Era)
+		}
+		if _, ok := inv.Skills["Computer Use"]; ok {
+			t.Error("expected no Computer Use skill in the 1920s")
+		}
+	})
+
 	t.Run("returns error for invalid JSON", func(t *testing.T) {
 		h, _ := newTestHandler()
 
@@ -311,7 +752,7 @@ func TestUpdateInvestigator(t *testing.T) {
 		h, store := newTestHandler()
 
 		// Create an investigator first
-		inv := models.RandomInvestigator(models.Pulp)
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
 		inv.ID = "test-id"
 		store.investigators["test-id"] = inv
 
@@ -332,6 +773,26 @@ func TestUpdateInvestigator(t *testing.T) {
 		}
 	})
 
+	t.Run("recalculates wealth when Credit Rating changes", func(t *testing.T) {
+		h, store := newTestHandler()
+
+		inv := models.RandomInvestigator(models.Classic, models.Twenties)
+		inv.ID = "test-id"
+		store.investigators["test-id"] = inv
+
+		body, _ := json.Marshal(UpdateRequest{Section: "skills", Field: "Credit Rating", Value: 30})
+		w := httptest.NewRecorder()
+		h.UpdateInvestigator(w, requestWithParams("PUT", "/api/investigator/test-id", body, []string{"test-id"}))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		want := models.Wealth{Cash: 60, Assets: 1500, SpendingLevel: 10}
+		if got := store.investigators["test-id"].Wealth; got != want {
+			t.Errorf("expected 1920s wealth %+v at Credit Rating 30, got %+v", want, got)
+		}
+	})
+
 	t.Run("returns error for non-existent investigator", func(t *testing.T) {
 		h, _ := newTestHandler()
 
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"fmt"
+	"net/http"
+	"slices"
 	"strconv"
 
 	"book-of-shadows/internal/errors"
@@ -105,14 +107,18 @@ func (h *Handler) updateAttribute(inv *models.Investigator, field string, value
 		return errors.NewValidationError(field, "must be a number")
 	}
 
+	// Use the PDF field name (STR, CON, etc.) for the Name field
+	pdfName := field
+	if mapped, ok := attrNameToPdfField[field]; ok {
+		pdfName = mapped
+	}
+	if err := inv.ValidateCharacteristic(pdfName, intValue); err != nil {
+		return errors.NewHTTPError(http.StatusBadRequest, "Invalid characteristic: "+err.Error(), err)
+	}
+
 	attr, exists := inv.Attributes[field]
 	if !exists {
 		// Create new attribute if it doesn't exist
-		// Use the PDF field name (STR, CON, etc.) for the Name field
-		pdfName := field
-		if mapped, ok := attrNameToPdfField[field]; ok {
-			pdfName = mapped
-		}
 		inv.Attributes[field] = models.Attribute{
 			Name:  pdfName,
 			Value: intValue,
@@ -123,6 +129,7 @@ func (h *Handler) updateAttribute(inv *models.Investigator, field string, value
 	}
 
 	// Recalculate dependent values
+	h.resetSanityAndMagic(inv)
 	h.recalculateDependentAttributes(inv)
 
 	return nil
@@ -139,10 +146,19 @@ func (h *Handler) updateSkill(inv *models.Investigator, field string, value inte
 	if !exists {
 		return errors.ErrInvalidSkill
 	}
+	// The wizard buys skills with points so the pools stay in step
+	if inv.Creating {
+		return errors.NewHTTPError(http.StatusConflict, "Skills are bought with skill points until creation is complete", nil)
+	}
 
 	skill.Value = intValue
 	inv.Skills[field] = skill
 
+	// Money follows Credit Rating
+	if field == "Credit Rating" {
+		inv.SetWealth()
+	}
+
 	return nil
 }
 
@@ -159,6 +175,12 @@ func (h *Handler) updateStats(inv *models.Investigator, field string, _ interfac
 		inv.Unconscious = !inv.Unconscious
 	case "Dying":
 		inv.Dying = !inv.Dying
+	case "StrictRules":
+		// Past creation only the keeper decides, see SetMemberStrictRules
+		if !inv.Creating {
+			return errors.NewHTTPError(http.StatusForbidden, "Only the keeper changes strict rules after creation", nil)
+		}
+		inv.StrictRules = !inv.StrictRules
 	default:
 		return errors.NewValidationError(field, "unknown status field")
 	}
@@ -191,6 +213,8 @@ func (h *Handler) updatePersonalInfo(inv *models.Investigator, field string, val
 		} else {
 			inv.Age = intValue
 		}
+		// MOV follows age, current Sanity and Magic Points are left alone
+		h.recalculateDependentAttributes(inv)
 
 	case "Residence":
 		strVal, ok := value.(string)
@@ -213,6 +237,10 @@ func (h *Handler) updatePersonalInfo(inv *models.Investigator, field string, val
 	return nil
 }
 
+// combatAttributes are the resources the combat section changes, characteristics go through
+// updateAttribute and the generation method
+var combatAttributes = []string{models.AttrHitPoints, models.AttrSanity, models.AttrMagicPoints, models.AttrLuck}
+
 // updateCombat updates combat-related attributes
 func (h *Handler) updateCombat(inv *models.Investigator, field string, value interface{}) error {
 	intValue, err := toInt(value)
@@ -221,7 +249,7 @@ func (h *Handler) updateCombat(inv *models.Investigator, field string, value int
 	}
 
 	attr, exists := inv.Attributes[field]
-	if !exists {
+	if !exists || !slices.Contains(combatAttributes, field) {
 		return errors.ErrInvalidAttribute
 	}
 
@@ -297,6 +325,11 @@ func (h *Handler) updateTalent(inv *models.Investigator, talentName string, valu
 	}
 
 	if shouldAdd {
+		// Talents are a Pulp rule that comes with the archetype
+		if inv.Archetype == nil {
+			return errors.NewValidationError(talentName, "talents need a Pulp archetype")
+		}
+
 		// Check if already at max talents
 		if len(inv.Talents) >= inv.Archetype.AmountOfTalents {
 			return errors.NewValidationError(talentName, "maximum talents already selected")
@@ -402,19 +435,28 @@ func (h *Handler) updateMania(inv *models.Investigator, maniaName string, value
 }
 
 // recalculateDependentAttributes recalculates attributes that depend on other attributes
+// and on age. Current Sanity and Magic Points are left alone, see resetSanityAndMagic.
 func (h *Handler) recalculateDependentAttributes(inv *models.Investigator) {
 	// Recalculate occupation points
-	occupationPoints := inv.CalculateOccupationSkillPoints()
-	inv.UnassignedOccupationPoints = occupationPoints
-	inv.OccupationPoints = occupationPoints
+	inv.OccupationPoints = inv.CalculateOccupationSkillPoints()
 
 	// Recalculate free points
 	if intel, exists := inv.Attributes["Intelligence"]; exists {
-		intPoints := intel.Value * 2
-		inv.FreePoints = intPoints
-		inv.UnassignedFreePoints = intPoints
+		inv.FreePoints = intel.Value * 2
 	}
 
+	// Points already allocated to skills stay spent
+	inv.SyncSkillPools()
+
+	// Recalculate HP, Movement, Build & Damage
+	inv.SetHP()
+	inv.SetMovement()
+	inv.SetBuildAndDMG()
+}
+
+// resetSanityAndMagic starts Sanity and Magic Points over from Power, for when the
+// characteristics themselves are edited
+func (h *Handler) resetSanityAndMagic(inv *models.Investigator) {
 	// Update Sanity based on Power
 	if power, exists := inv.Attributes["Power"]; exists {
 		inv.Attributes["Sanity"] = models.Attribute{
@@ -432,11 +474,6 @@ func (h *Handler) recalculateDependentAttributes(inv *models.Investigator) {
 			MaxValue:      power.Value / 5,
 		}
 	}
-
-	// Recalculate HP, Movement, Build & Damage
-	inv.SetHP()
-	inv.SetMovement()
-	inv.SetBuildAndDMG()
 }
 
 // toInt converts an interface{} to int
@@ -455,4 +492,17 @@ func toInt(value interface{}) (int, error) {
 	default:
 		return 0, fmt.Errorf("cannot convert %T to int", value)
 	}
+}
+
+// eraFromQuery reads the era query parameter, defaulting to Modern when it is absent
+func eraFromQuery(r *http.Request) (models.Era, error) {
+	name := r.URL.Query().Get("era")
+	if name == "" {
+		return models.Modern, nil
+	}
+	era, err := models.ParseEra(name)
+	if err != nil {
+		return 0, errors.NewHTTPError(http.StatusBadRequest, "Unknown era", err)
+	}
+	return era, nil
 }
\ No newline at end of file
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"book-of-shadows/models"
+)
+
+// movementInvestigator stores a Classic investigator with the given STR, DEX, SIZ and age
+func movementInvestigator(store *MockStore, str, dex, siz, age int) *models.Investigator {
+	inv := models.RandomInvestigator(models.Classic, models.Modern)
+	inv.ID = "test-id"
+	inv.Age = age
+	for key, value := range map[string]int{models.AttrStrength: str, models.AttrDexterity: dex, models.AttrSize: siz} {
+		attr := inv.Attributes[key]
+		attr.Value = value
+		inv.Attributes[key] = attr
+	}
+	store.investigators[inv.ID] = inv
+	return inv
+}
+
+func TestSetMovement(t *testing.T) {
+	tests := []struct {
+		name     string
+		str, dex int
+		want     int
+	}{
+		{"both below SIZ", 40, 45, 7},
+		{"STR below, DEX equal", 40, 50, 8},
+		{"STR below, DEX above", 40, 60, 8},
+		{"STR equal, DEX below", 50, 45, 8},
+		{"both equal to SIZ", 50, 50, 8},
+		{"STR equal, DEX above", 50, 60, 8},
+		{"STR above, DEX below", 60, 45, 8},
+		{"STR above, DEX equal", 60, 50, 8},
+		{"both above SIZ", 60, 65, 9},
+	}
+	ages := []struct {
+		age     int
+		penalty int
+	}{
+		{17, 0}, {25, 0}, {39, 0}, {40, 1}, {55, 2}, {60, 3}, {75, 4}, {89, 5},
+	}
+
+	_, store := newTestHandler()
+	for _, tt := range tests {
+		for _, a := range ages {
+			inv := movementInvestigator(store, tt.str, tt.dex, 50, a.age)
+			inv.SetMovement()
+			if want := tt.want - a.penalty; inv.Move != want {
+				t.Errorf("%s at age %d: expected MOV %d, got %d", tt.name, a.age, want, inv.Move)
+			}
+		}
+	}
+}
+
+func TestUpdateRecalculatesMovement(t *testing.T) {
+	tests := []struct {
+		name    string
+		section string
+		field   string
+		value   int
+		want    int
+	}{
+		{"STR rising above SIZ", "attributes", models.AttrStrength, 60, 9},
+		{"DEX dropping below SIZ", "attributes", models.AttrDexterity, 40, 8},
+		{"SIZ rising above both", "attributes", models.AttrSize, 70, 7},
+		{"Age reaching the fifties", "personalInfo", "Age", 52, 9 - 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, store := newTestHandler()
+			// STR 55 and DEX 55 against SIZ 50 give MOV 9
+			inv := movementInvestigator(store, 55, 55, 50, 30)
+			inv.SetMovement()
+
+			body, _ := json.Marshal(UpdateRequest{Section: tt.section, Field: tt.field, Value: tt.value})
+			w := httptest.NewRecorder()
+			h.UpdateInvestigator(w, requestWithParams("PUT", "/api/investigator/test-id", body, []string{"test-id"}))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			if got := store.investigators["test-id"].Move; got != tt.want {
+				t.Errorf("expected MOV %d, got %d", tt.want, got)
+			}
+		})
+	}
+	t.Run("Age leaves current Sanity and Magic Points alone", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := movementInvestigator(store, 55, 55, 50, 30)
+		for key, value := range map[string]int{models.AttrSanity: 12, models.AttrMagicPoints: 3} {
+			attr := inv.Attributes[key]
+			attr.Value = value
+			inv.Attributes[key] = attr
+		}
+
+		body, _ := json.Marshal(UpdateRequest{Section: "personalInfo", Field: "Age", Value: 52})
+		w := httptest.NewRecorder()
+		h.UpdateInvestigator(w, requestWithParams("PUT", "/api/investigator/test-id", body, []string{"test-id"}))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		saved := store.investigators["test-id"]
+		if saved.Attributes[models.AttrSanity].Value != 12 || saved.Attributes[models.AttrMagicPoints].Value != 3 {
+			t.Errorf("expected Sanity 12 and Magic Points 3 to be kept, got %d and %d",
+				saved.Attributes[models.AttrSanity].Value, saved.Attributes[models.AttrMagicPoints].Value)
+		}
+	})
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/internal/validation"
+	"book-of-shadows/models"
+	"book-of-shadows/storage"
+
+	"github.com/google/uuid"
+)
+
+// InventoryResponse is an investigator's weapons and gear
+type InventoryResponse struct {
+	Weapons []models.Weapon `json:"weapons"`
+	Gear    []models.Gear   `json:"gear"`
+}
+
+// ListWeapons returns the weapons catalogue for the era in the query, Modern by default
+func (h *Handler) ListWeapons(w http.ResponseWriter, r *http.Request) {
+	era, err := eraFromQuery(r)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, models.WeaponsForEra(era))
+}
+
+// AddWeapon adds a weapon to an investigator's inventory. A body with only a name takes
+// the rest of the weapon from the catalogue.
+func (h *Handler) AddWeapon(w http.ResponseWriter, r *http.Request) {
+	weapon, ok := h.decodeWeapon(w, r)
+	if !ok {
+		return
+	}
+	weapon.ID = uuid.New().String()
+
+	h.editInventory(w, r, "weapons", func(investigator *models.Investigator, _ []string) (string, interface{}, interface{}, error) {
+		investigator.Weapons = append(investigator.Weapons, weapon)
+		return weapon.Name, nil, weapon, nil
+	})
+}
+
+// UpdateWeapon replaces a weapon in an investigator's inventory
+func (h *Handler) UpdateWeapon(w http.ResponseWriter, r *http.Request) {
+	weapon, ok := h.decodeWeapon(w, r)
+	if !ok {
+		return
+	}
+
+	h.editInventory(w, r, "weapons", func(investigator *models.Investigator, params []string) (string, interface{}, interface{}, error) {
+		index, err := investigator.FindWeapon(params[1])
+		if err != nil {
+			return "", nil, nil, errors.NewHTTPError(http.StatusNotFound, "Weapon not found", err)
+		}
+		old := investigator.Weapons[index]
+		weapon.ID = old.ID
+		investigator.Weapons[index] = weapon
+		return weapon.Name, old, weapon, nil
+	})
+}
+
+// DeleteWeapon removes a weapon from an investigator's inventory
+func (h *Handler) DeleteWeapon(w http.ResponseWriter, r *http.Request) {
+	h.editInventory(w, r, "weapons", func(investigator *models.Investigator, params []string) (string, interface{}, interface{}, error) {
+		index, err := investigator.FindWeapon(params[1])
+		if err != nil {
+			return "", nil, nil, errors.NewHTTPError(http.StatusNotFound, "Weapon not found", err)
+		}
+		old := investigator.Weapons[index]
+		investigator.Weapons = append(investigator.Weapons[:index], investigator.Weapons[index+1:]...)
+		return old.Name, old, nil, nil
+	})
+}
+
+// AddGear adds a possession to an investigator's inventory
+func (h *Handler) AddGear(w http.ResponseWriter, r *http.Request) {
+	gear, ok := h.decodeGear(w, r)
+	if !ok {
+		return
+	}
+	gear.ID = uuid.New().String()
+
+	h.editInventory(w, r, "gear", func(investigator *models.Investigator, _ []string) (string, interface{}, interface{}, error) {
+		investigator.Gear = append(investigator.Gear, gear)
+		return gear.Name, nil, gear, nil
+	})
+}
+
+// UpdateGear replaces a possession in an investigator's inventory
+func (h *Handler) UpdateGear(w http.ResponseWriter, r *http.Request) {
+	gear, ok := h.decodeGear(w, r)
+	if !ok {
+		return
+	}
+
+	h.editInventory(w, r, "gear", func(investigator *models.Investigator, params []string) (string, interface{}, interface{}, error) {
+		index, err := investigator.FindGear(params[1])
+		if err != nil {
+			return "", nil, nil, errors.NewHTTPError(http.StatusNotFound, "Gear not found", err)
+		}
+		old := investigator.Gear[index]
+		gear.ID = old.ID
+		investigator.Gear[index] = gear
+		return gear.Name, old, gear, nil
+	})
+}
+
+// DeleteGear removes a possession from an investigator's inventory
+func (h *Handler) DeleteGear(w http.ResponseWriter, r *http.Request) {
+	h.editInventory(w, r, "gear", func(investigator *models.Investigator, params []string) (string, interface{}, interface{}, error) {
+		index, err := investigator.FindGear(params[1])
+		if err != nil {
+			return "", nil, nil, errors.NewHTTPError(http.StatusNotFound, "Gear not found", err)
+		}
+		old := investigator.Gear[index]
+		investigator.Gear = append(investigator.Gear[:index], investigator.Gear[index+1:]...)
+		return old.Name, old, nil, nil
+	})
+}
+
+// decodeWeapon reads and validates the weapon in a request body, filling a weapon given
+// only by name from the catalogue
+func (h *Handler) decodeWeapon(w http.ResponseWriter, r *http.Request) (models.Weapon, bool) {
+	var weapon models.Weapon
+	if err := json.NewDecoder(r.Body).Decode(&weapon); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid JSON", err))
+		return models.Weapon{}, false
+	}
+	defer r.Body.Close()
+
+	weapon.Name = strings.TrimSpace(weapon.Name)
+	if weapon.Damage == "" {
+		if catalogued, ok := models.FindWeapon(weapon.Name); ok {
+			weapon = catalogued
+		}
+	}
+	weapon.Eras = nil
+	if err := weapon.Validate(); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid weapon: "+err.Error(), err))
+		return models.Weapon{}, false
+	}
+	return weapon, true
+}
+
+// decodeGear reads and validates the possession in a request body, one of it when no
+// quantity is given
+func (h *Handler) decodeGear(w http.ResponseWriter, r *http.Request) (models.Gear, bool) {
+	var gear models.Gear
+	if err := json.NewDecoder(r.Body).Decode(&gear); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid JSON", err))
+		return models.Gear{}, false
+	}
+	defer r.Body.Close()
+
+	gear.Name = strings.TrimSpace(gear.Name)
+	if gear.Name == "" {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Gear needs a name", nil))
+		return models.Gear{}, false
+	}
+	if gear.Quantity < 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Gear quantity cannot be negative", nil))
+		return models.Gear{}, false
+	}
+	if gear.Quantity == 0 {
+		gear.Quantity = 1
+	}
+	return gear, true
+}
+
+// inventoryEdit changes an investigator's inventory and returns the name of the item it
+// touched with its old and new values, nil when the item was added or removed
+type inventoryEdit func(investigator *models.Investigator, params []string) (string, interface{}, interface{}, error)
+
+// editInventory loads the investigator in the path, applies an edit to their inventory,
+// saves them and records the change in a revision of the section. It responds with the
+// investigator's whole inventory.
+func (h *Handler) editInventory(w http.ResponseWriter, r *http.Request, section string, edit inventoryEdit) {
+	params := r.Context().Value("params").([]string)
+	if len(params) == 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing investigator ID", nil))
+		return
+	}
+	id := params[0]
+
+	ctx := r.Context()
+	ownerID := storage.OwnerFromContext(ctx)
+	investigator, err := h.store.GetInvestigator(ctx, ownerID, id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	snapshot, err := investigator.ToJSON()
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	violations := validation.Check(investigator)
+
+	if len(params) < 2 {
+		params = append(params, "")
+	}
+	name, oldValue, newValue, err := edit(investigator, params)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err := h.saveInvestigator(ctx, ownerID, id, investigator, violations); err != nil {
+		h.respondError(w, err)
+		return
+	}
+	h.recordRevision(ctx, id, section, name, oldValue, newValue, snapshot)
+
+	response := InventoryResponse{Weapons: investigator.Weapons, Gear: investigator.Gear}
+	if response.Weapons == nil {
+		response.Weapons = []models.Weapon{}
+	}
+	if response.Gear == nil {
+		response.Gear = []models.Gear{}
+	}
+	h.respondJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"book-of-shadows/models"
+)
+
+func TestListWeapons(t *testing.T) {
+	h, _ := newTestHandler()
+
+	w := httptest.NewRecorder()
+	h.ListWeapons(w, httptest.NewRequest("GET", "/api/weapons/?era=gaslight", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var weapons []models.Weapon
+	if err := json.Unmarshal(w.Body.Bytes(), &weapons); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	names := make(map[string]bool)
+	for _, weapon := range weapons {
+		names[weapon.Name] = true
+	}
+	if !names[".38 revolver"] || names["9mm automatic (Glock 17)"] || names["Thompson submachine gun"] {
+		t.Errorf("expected the Gaslight catalogue, got %v", names)
+	}
+
+	w = httptest.NewRecorder()
+	h.ListWeapons(w, httptest.NewRequest("GET", "/api/weapons/?era=medieval", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for an unknown era, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestWeaponInventory(t *testing.T) {
+	// send calls a handler as owner-1 with the given path params
+	send := func(handler http.HandlerFunc, method string, body interface{}, params ...string) *httptest.ResponseRecorder {
+		var data []byte
+		if body != nil {
+			data, _ = json.Marshal(body)
+		}
+		w := httptest.NewRecorder()
+		handler(w, withOwner(requestWithParams(method, "/", data, params), "owner-1", nil))
+		return w
+	}
+	// decode reads the inventory from a successful response
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) InventoryResponse {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var inventory InventoryResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &inventory); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return inventory
+	}
+
+	t.Run("adds a weapon from the catalogue by name", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.RandomInvestigator(models.Classic, models.Modern)
+		store.SaveInvestigator(context.Background(), "owner-1", inv)
+
+		inventory := decode(t, send(h.AddWeapon, "POST", map[string]string{"name": ".38 Revolver"}, inv.ID))
+
+		if len(inventory.Weapons) != 1 || inventory.Weapons[0].Damage != "1D10" || inventory.Weapons[0].ID == "" {
+			t.Fatalf("expected the catalogue .38 revolver, got %+v", inventory.Weapons)
+		}
+		if saved := store.investigators[inv.ID]; len(saved.Weapons) != 1 || saved.Weapons[0].Skill != "Firearms(Handgun)" {
+			t.Errorf("expected the weapon to be saved, got %+v", saved.Weapons)
+		}
+		if len(store.revisions) != 1 || store.revisions[0].Section != "weapons" {
+			t.Error("expected the new weapon to be recorded in the history")
+		}
+	})
+
+	t.Run("rejects a weapon that cannot be rolled", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.RandomInvestigator(models.Classic, models.Modern)
+		store.SaveInvestigator(context.Background(), "owner-1", inv)
+
+		for _, weapon := range []models.Weapon{
+			{Name: "Heirloom pistol"},
+			{Name: "Cursed dagger", Damage: "1D4+STR"},
+			{Damage: "1D6"},
+		} {
+			if w := send(h.AddWeapon, "POST", weapon, inv.ID); w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d for %+v, got %d", http.StatusBadRequest, weapon, w.Code)
+			}
+		}
+		if len(store.investigators[inv.ID].Weapons) != 0 {
+			t.Error("expected no weapon to be saved")
+		}
+	})
+
+	t.Run("updates and deletes a weapon", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.RandomInvestigator(models.Classic, models.Modern)
+		inv.Weapons = []models.Weapon{{ID: "w1", Name: "Knife, small", Skill: "Fighting(Brawl)", Damage: "1D4+DB"}}
+		store.SaveInvestigator(context.Background(), "owner-1", inv)
+
+		custom := models.Weapon{Name: "Sword cane", Skill: "Fighting(Sword)", Damage: "1D6+DB", Range: "Touch", Attacks: "1"}
+		inventory := decode(t, send(h.UpdateWeapon, "PUT", custom, inv.ID, "w1"))
+		if len(inventory.Weapons) != 1 || inventory.Weapons[0].ID != "w1" || inventory.Weapons[0].Name != "Sword cane" {
+			t.Fatalf("expected the weapon to be replaced in place, got %+v", inventory.Weapons)
+		}
+
+		if w := send(h.DeleteWeapon, "DELETE", nil, inv.ID, "missing"); w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d for an unknown weapon, got %d", http.StatusNotFound, w.Code)
+		}
+		inventory = decode(t, send(h.DeleteWeapon, "DELETE", nil, inv.ID, "w1"))
+		if len(inventory.Weapons) != 0 || len(store.investigators[inv.ID].Weapons) != 0 {
+			t.Errorf("expected the weapon to be removed, got %+v", inventory.Weapons)
+		}
+		if len(store.revisions) != 2 {
+			t.Errorf("expected 2 revisions, got %d", len(store.revisions))
+		}
+	})
+
+	t.Run("keeps gear", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.RandomInvestigator(models.Classic, models.Modern)
+		store.SaveInvestigator(context.Background(), "owner-1", inv)
+
+		inventory := decode(t, send(h.AddGear, "POST", models.Gear{Name: "Flashlight"}, inv.ID))
+		if len(inventory.Gear) != 1 || inventory.Gear[0].Quantity != 1 {
+			t.Fatalf("expected one flashlight, got %+v", inventory.Gear)
+		}
+		id := inventory.Gear[0].ID
+
+		inventory = decode(t, send(h.UpdateGear, "PUT", models.Gear{Name: "Flashlight", Quantity: 2, Notes: "Spare batteries"}, inv.ID, id))
+		if inventory.Gear[0].Quantity != 2 || inventory.Gear[0].ID != id {
+			t.Errorf("expected two flashlights, got %+v", inventory.Gear)
+		}
+
+		for _, gear := range []models.Gear{{Name: " "}, {Name: "Rope", Quantity: -1}} {
+			if w := send(h.AddGear, "POST", gear, inv.ID); w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d for %+v, got %d", http.StatusBadRequest, gear, w.Code)
+			}
+		}
+
+		inventory = decode(t, send(h.DeleteGear, "DELETE", nil, inv.ID, id))
+		if len(inventory.Gear) != 0 || len(store.investigators[inv.ID].Gear) != 0 {
+			t.Errorf("expected the gear to be removed, got %+v", inventory.Gear)
+		}
+	})
+}
+
+func TestConvertInvestigatorToMapWeapons(t *testing.T) {
+	inv := models.RandomInvestigator(models.Classic, models.Twenties)
+	brawl := inv.Skills["Fighting(Brawl)"]
+	brawl.Value = 50
+	inv.Skills["Fighting(Brawl)"] = brawl
+	handgun := inv.Skills["Firearms(Handgun)"]
+	handgun.Value = 40
+	inv.Skills["Firearms(Handgun)"] = handgun
+	revolver, _ := models.FindWeapon(".38 revolver")
+	tommy, _ := models.FindWeapon("Thompson submachine gun")
+	knife, _ := models.FindWeapon("Knife, small")
+	club, _ := models.FindWeapon("Club, small (cosh)")
+	inv.Weapons = []models.Weapon{revolver, tommy, knife, club}
+	inv.Gear = []models.Gear{{Name: "Flashlight"}, {Name: "Rope", Quantity: 2}, {Name: "Camera"}}
+
+	data := convertInvestigatorToMap(inv)
+
+	expected := map[string]string{
+		"Weapon_Regular0": "50", "Weapon_Hard0": "25", "Weapon_Extreme0": "10",
+		"Weapon_Name1": ".38 revolver", "Weapon_Regular1": "40", "Weapon_Hard1": "20", "Weapon_Extreme1": "8",
+		"Weapon_Damage1": "1D10", "Weapon_Ammo1": "6", "Weapon_Malf1": "100",
+		"Weapon_Name2": "Thompson submachine gun", "Weapon_Regular2": "15", "Weapon_Malf2": "96",
+		"Weapon_Name3": "Knife, small", "Weapon_Damage3": "1D4+DB", "Weapon_Range3": "Touch",
+	}
+	for field, want := range expected {
+		if data[field] != want {
+			t.Errorf("expected %s to be %q, got %q", field, want, data[field])
+		}
+	}
+	if _, ok := data["Weapon_Malf3"]; ok {
+		t.Error("expected no malfunction for a knife")
+	}
+	if data["Gear/Possessions"] != "Flashlight\nRope (x2)" || data["Gear/Possessions1"] != "Camera" {
+		t.Errorf("expected the gear split over both columns, got %q and %q", data["Gear/Possessions"], data["Gear/Possessions1"])
+	}
+
+	sheet, err := (&characterSheet{path: testSheetPath}).load()
+	if err != nil {
+		t.Fatalf("failed to load character sheet: %v", err)
+	}
+	fields := strings.Join(sheet.FieldNames(), "\n") + "\n"
+	for name := range data {
+		if !strings.Contains(fields, name+"\n") {
+			t.Errorf("%s is not a field of the character sheet", name)
+		}
+	}
+}
@@ -2,13 +2,38 @@ package handlers
 
 import (
 	"net/http"
+	"slices"
+	"strings"
 
+	"book-of-shadows/models"
+	"book-of-shadows/storage"
 	"book-of-shadows/views"
 )
 
 // KeeperDashboard renders the Keeper tools dashboard
 func (h *Handler) KeeperDashboard(w http.ResponseWriter, r *http.Request) {
-	component := views.KeeperDashboard()
+	ctx := r.Context()
+	investigators, err := h.store.ListInvestigators(ctx, storage.OwnerFromContext(ctx))
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	// Offer the investigators by name for the bout of madness roller
+	sorted := make([]*models.Investigator, 0, len(investigators))
+	for _, inv := range investigators {
+		sorted = append(sorted, inv)
+	}
+	slices.SortFunc(sorted, func(a, b *models.Investigator) int { return strings.Compare(a.Name, b.Name) })
+
+	// along with the investigators that joined the keeper's campaigns
+	campaigns, err := h.store.ListCampaigns(ctx, storage.OwnerFromContext(ctx))
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	component := views.KeeperDashboard(sorted, campaigns)
 	if err := component.Render(r.Context(), w); err != nil {
 		h.logger.Printf("Failed to render keeper dashboard: %v", err)
 		h.respondError(w, err)
@@ -1,56 +1,45 @@
 package handlers
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 
 	"book-of-shadows/internal/errors"
+	"book-of-shadows/internal/pdfform"
+	"book-of-shadows/internal/validation"
 	"book-of-shadows/models"
+	"book-of-shadows/serializers"
+	"book-of-shadows/storage"
 )
 
-// ProcessingOptions defines the options for PDF processing
-type ProcessingOptions struct {
-	InputPath  string            `json:"input_path"`
-	OutputPath string            `json:"output_path"`
-	Metadata   map[string]string `json:"metadata"`
-}
+// sheetTemplatePath is the blank character sheet filled in by ExportPDF
+const sheetTemplatePath = "static/modernSheet.pdf"
 
-// PdfProcessor handles PDF generation via Python script
-type PdfProcessor struct {
-	pythonPath string
-	scriptPath string
+// characterSheet holds the parsed character sheet template, loaded on first use
+type characterSheet struct {
+	path string
+	once sync.Once
+	form *pdfform.Form
+	err  error
 }
 
-// NewPdfProcessor creates a new PDF processor
-func NewPdfProcessor() *PdfProcessor {
-	return &PdfProcessor{
-		pythonPath: "python3",
-		scriptPath: filepath.Join("scripts", "exporter.py"),
-	}
-}
-
-// ProcessPdf processes a PDF with the given options
-func (p *PdfProcessor) ProcessPdf(options ProcessingOptions) error {
-	optionsJSON, err := json.Marshal(options)
-	if err != nil {
-		return fmt.Errorf("failed to marshal options: %w", err)
-	}
-
-	cmd := exec.Command(p.pythonPath, p.scriptPath, string(optionsJSON))
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to run Python script: %w", err)
-	}
-
-	return nil
+// load returns the parsed template
+func (s *characterSheet) load() (*pdfform.Form, error) {
+	s.once.Do(func() {
+		data, err := os.ReadFile(s.path)
+		if err != nil {
+			s.err = fmt.Errorf("failed to read character sheet: %w", err)
+			return
+		}
+		s.form, s.err = pdfform.Parse(data)
+	})
+	return s.form, s.err
 }
 
 // ExportPDF exports an investigator as a PDF
@@ -64,44 +53,95 @@ func (h *Handler) ExportPDF(w http.ResponseWriter, r *http.Request) {
 	id := params[0]
 
 	// Get investigator
-	investigator, err := h.store.GetInvestigator(r, id)
+	ctx := r.Context()
+	investigator, err := h.store.GetInvestigator(ctx, storage.OwnerFromContext(ctx), id)
 	if err != nil {
 		h.respondError(w, err)
 		return
 	}
 
+	sheet, err := h.sheet.load()
+	if err != nil {
+		h.logger.Printf("PDF generation failed: %v", err)
+		h.respondError(w, errors.NewHTTPError(http.StatusInternalServerError, "Error generating PDF", err))
+		return
+	}
+
 	// Convert to PDF map
 	data := convertInvestigatorToMap(investigator)
 
 	// Generate filename
 	fileName := fmt.Sprintf("%s.pdf", strings.ReplaceAll(data["Investigators_Name"], " ", "_"))
-	outputPath := filepath.Join(".", "static", fileName)
 
-	// Process PDF
-	processor := NewPdfProcessor()
-	options := ProcessingOptions{
-		InputPath:  filepath.Join(".", "static", "modernSheet.pdf"),
-		OutputPath: outputPath,
-		Metadata:   data,
-	}
-
-	if err := processor.ProcessPdf(options); err != nil {
+	pdf, size, err := sheet.Fill(data)
+	if err != nil {
 		h.logger.Printf("PDF generation failed: %v", err)
 		h.respondError(w, errors.NewHTTPError(http.StatusInternalServerError, "Error generating PDF", err))
 		return
 	}
 
-	// Serve the file
+	// Stream the filled sheet
 	w.Header().Set("Content-Type", "application/pdf")
-	w.Header().Set("Content-Disposition", "attachment; filename="+fileName)
-	http.ServeFile(w, r, outputPath)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	if _, err := io.Copy(w, pdf); err != nil {
+		h.logger.Printf("Failed to write PDF: %v", err)
+	}
+}
 
-	// Clean up the generated file
-	go func() {
-		if err := os.Remove(outputPath); err != nil {
-			h.logger.Printf("Failed to remove temporary PDF: %v", err)
-		}
-	}()
+// maxSheetUploadBytes caps the size of a character sheet upload kept in memory
+const maxSheetUploadBytes = 8 << 20
+
+// ImportPDF creates an investigator from a filled character sheet PDF
+func (h *Handler) ImportPDF(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(maxSheetUploadBytes); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid upload", err))
+		return
+	}
+
+	file, _, err := r.FormFile("sheet")
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing character sheet", err))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Failed to read character sheet", err))
+		return
+	}
+
+	sheet, err := pdfform.Parse(data)
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid character sheet", err))
+		return
+	}
+
+	values := sheet.Values()
+	if values["Investigators_Name"] == "" {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Character sheet has no investigator name", nil))
+		return
+	}
+
+	investigator := serializers.NewInvestigatorSerializer(values).ToInvestigator()
+	if err := validation.Enforce(investigator, nil); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	// Save investigator
+	ctx := r.Context()
+	key, err := h.store.SaveInvestigator(ctx, storage.OwnerFromContext(ctx), investigator)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	w.Header().Set("HX-Trigger", "import")
+	h.respondJSON(w, http.StatusCreated, map[string]string{
+		"Key": key,
+	})
 }
 
 // convertInvestigatorToMap converts an investigator to a map for PDF export
@@ -111,18 +151,23 @@ func convertInvestigatorToMap(investigator *models.Investigator) map[string]stri
 	// Handle Attributes
 	for key, attr := range investigator.Attributes {
 		data[attr.Name] = strconv.Itoa(attr.Value)
-		data[attr.Name+"_half"] = strconv.Itoa(attr.Value / 2)
-		data[attr.Name+"_fifth"] = strconv.Itoa(attr.Value / 5)
 
-		// Add Starting/Max values for HP, Magic, and Sanity
+		// Add Starting/Max values for HP, Magic, Luck and Sanity
 		// Note: StartingValue is not serialized (json:"-"), so we use MaxValue
-		if key == models.AttrHitPoints {
+		switch key {
+		case models.AttrHitPoints:
 			data["StartingHP"] = strconv.Itoa(attr.MaxValue)
-		} else if key == models.AttrMagicPoints {
+		case models.AttrMagicPoints:
 			data["StartingMagic"] = strconv.Itoa(attr.MaxValue)
-		} else if key == models.AttrSanity {
+		case models.AttrLuck:
+			data["StartingLuck"] = strconv.Itoa(attr.Value)
+		case models.AttrSanity:
 			data["StartingSanity"] = strconv.Itoa(attr.Value) // Starting sanity = current sanity at creation
 			data["MaxSanity"] = strconv.Itoa(attr.MaxValue)
+		default:
+			// Only characteristics have half and fifth boxes on the sheet
+			data[attr.Name+"_half"] = strconv.Itoa(attr.Value / 2)
+			data[attr.Name+"_fifth"] = strconv.Itoa(attr.Value / 5)
 		}
 	}
 
@@ -131,38 +176,69 @@ func convertInvestigatorToMap(investigator *models.Investigator) map[string]stri
 		if skill.Base == 1 {
 			continue
 		}
-		formField := skill.FormName
+		// The sheet names some boxes inconsistently: the Fast Talk half and fifth boxes keep
+		// a trailing space and the Dodge copy in the combat section has no Skill_ prefix
+		formField := "Skill_" + strings.TrimSpace(skill.FormName)
+		fractionField := "Skill_" + skill.FormName
+		if skill.Name == "Dodge_Copy" {
+			formField, fractionField = skill.FormName, skill.FormName
+		}
 		if skill.NeedsFormDef == 1 {
-			data["SkillDef_"+formField] = skill.Name
+			data["SkillDef_"+skill.FormName] = skill.Name
 		}
 		if skill.IsSelected {
-			data["Skill_"+formField+"_Chk"] = "1"
+			data[formField+"_Chk"] = "1"
 		}
-		data["Skill_"+formField] = strconv.Itoa(skill.Value)
-		data["Skill_"+formField+"_half"] = strconv.Itoa(skill.Value / 2)
-		data["Skill_"+formField+"_fifth"] = strconv.Itoa(skill.Value / 5)
+		data[formField] = strconv.Itoa(skill.Value)
+		data[fractionField+"_half"] = strconv.Itoa(skill.Value / 2)
+		data[fractionField+"_fifth"] = strconv.Itoa(skill.Value / 5)
 	}
 
 	// Handle other fields
 	data["Investigators_Name"] = investigator.Name
-	data["Occupation"] = investigator.Occupation.Name
+	if investigator.Occupation != nil {
+		data["Occupation"] = investigator.Occupation.Name
+	}
 	data["Age"] = strconv.Itoa(investigator.Age)
 	data["Residence"] = investigator.Residence
 	data["Birthplace"] = investigator.Birthplace
 	data["MOV"] = strconv.Itoa(investigator.Move)
 	data["DamageBonus"] = investigator.DamageBonus
 	data["Build"] = investigator.Build
-	data["Archetype"] = investigator.Archetype.Name
+	if investigator.Archetype != nil {
+		data["Archetype"] = investigator.Archetype.Name
+	}
 
-	// Handle talents
-	var talents strings.Builder
-	for i, talent := range investigator.Talents {
-		if i > 0 {
-			talents.WriteString(", ")
+	// Handle wealth from Credit Rating
+	data["Cash"] = models.FormatMoney(investigator.Wealth.Cash)
+	data["Assets1"] = models.FormatMoney(investigator.Wealth.Assets)
+	data["SpendingLevel"] = models.FormatMoney(investigator.Wealth.SpendingLevel)
+
+	// Handle status check boxes
+	statuses := map[string]bool{
+		"TempInsanity_Chk":  investigator.TemporaryInsane,
+		"IndefInsanity_Chk": investigator.IndefiniteInsane,
+		"MajorWound_Chk":    investigator.MajorWound,
+		"Unconscious_Chk":   investigator.Unconscious,
+		"Dying_Chk":         investigator.Dying,
+	}
+	for field, checked := range statuses {
+		if checked {
+			data[field] = "1"
+		}
+	}
+
+	// Handle talents, which only Pulp investigators have
+	if investigator.IsPulp() {
+		var talents strings.Builder
+		for i, talent := range investigator.Talents {
+			if i > 0 {
+				talents.WriteString(", ")
+			}
+			talents.WriteString(talent.Name)
 		}
-		talents.WriteString(talent.Name)
+		data["Pulp Talents"] = talents.String()
 	}
-	data["Pulp Talents"] = talents.String()
 
 	// Handle phobias and manias
 	var phobiasManias strings.Builder
@@ -183,5 +259,45 @@ func convertInvestigatorToMap(investigator *models.Investigator) map[string]stri
 	}
 	data["Phobias/Manias"] = phobiasManias.String()
 
+	// Handle weapons. The first row of the table is the unarmed attack, the sheet has
+	// room for three more weapons.
+	brawl := investigator.Skills["Fighting(Brawl)"].Value
+	data["Weapon_Regular0"] = strconv.Itoa(brawl)
+	data["Weapon_Hard0"] = strconv.Itoa(brawl / 2)
+	data["Weapon_Extreme0"] = strconv.Itoa(brawl / 5)
+	for i, weapon := range investigator.Weapons {
+		if i == 3 {
+			break
+		}
+		row := strconv.Itoa(i + 1)
+		chance := investigator.WeaponSkill(weapon)
+		data["Weapon_Name"+row] = weapon.Name
+		data["Weapon_Regular"+row] = strconv.Itoa(chance)
+		data["Weapon_Hard"+row] = strconv.Itoa(chance / 2)
+		data["Weapon_Extreme"+row] = strconv.Itoa(chance / 5)
+		data["Weapon_Damage"+row] = weapon.Damage
+		data["Weapon_Range"+row] = weapon.Range
+		data["Weapon_Attacks"+row] = weapon.Attacks
+		data["Weapon_Ammo"+row] = weapon.Ammo
+		if weapon.Malfunction > 0 {
+			data["Weapon_Malf"+row] = strconv.Itoa(weapon.Malfunction)
+		}
+	}
+
+	// Handle gear, one item per line split over the two columns of the sheet
+	if len(investigator.Gear) > 0 {
+		lines := make([]string, 0, len(investigator.Gear))
+		for _, gear := range investigator.Gear {
+			line := gear.Name
+			if gear.Quantity > 1 {
+				line = fmt.Sprintf("%s (x%d)", gear.Name, gear.Quantity)
+			}
+			lines = append(lines, line)
+		}
+		half := (len(lines) + 1) / 2
+		data["Gear/Possessions"] = strings.Join(lines[:half], "\n")
+		data["Gear/Possessions1"] = strings.Join(lines[half:], "\n")
+	}
+
 	return data
 }
\ No newline at end of file
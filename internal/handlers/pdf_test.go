@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"book-of-shadows/internal/pdfform"
+	"book-of-shadows/models"
+)
+
+// testSheetPath is the character sheet template relative to this package
+const testSheetPath = "../../static/modernSheet.pdf"
+
+func TestConvertInvestigatorToMapMatchesSheet(t *testing.T) {
+	sheet, err := (&characterSheet{path: testSheetPath}).load()
+	if err != nil {
+		t.Fatalf("failed to load character sheet: %v", err)
+	}
+	fields := make(map[string]bool)
+	for _, name := range sheet.FieldNames() {
+		fields[name] = true
+	}
+
+	for _, mode := range []models.GameMode{models.Pulp, models.Classic} {
+		for _, era := range models.Eras {
+			for i := 0; i < 20; i++ {
+				for name := range convertInvestigatorToMap(models.RandomInvestigator(mode, era)) {
+					if !fields[name] {
+						t.Errorf("%s is not a field of the character sheet in the %s era", name, era)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestConvertClassicInvestigatorToMap(t *testing.T) {
+	inv := models.RandomInvestigator(models.Classic, models.Modern)
+	data := convertInvestigatorToMap(inv)
+
+	for _, field := range []string{"Archetype", "Pulp Talents"} {
+		if _, ok := data[field]; ok {
+			t.Errorf("expected no %s on a classic sheet, got %q", field, data[field])
+		}
+	}
+
+	hp := (inv.Attributes[models.AttrConstitution].Value + inv.Attributes[models.AttrSize].Value) / 10
+	if data["CurrentHP"] != strconv.Itoa(hp) {
+		t.Errorf("expected classic HP %d, got %s", hp, data["CurrentHP"])
+	}
+}
+
+func TestConvertInvestigatorToMapWealth(t *testing.T) {
+	tests := []struct {
+		era                    models.Era
+		creditRating           int
+		cash, assets, spending string
+	}{
+		{models.Twenties, 0, "$0.50", "$0", "$0.50"},
+		{models.Twenties, 95, "$1,900", "$190,000", "$250"},
+		{models.Gaslight, 5, "$5", "$50", "$2"},
+		{models.Modern, 30, "$1,200", "$30,000", "$200"},
+		{models.Modern, 99, "$1,000,000", "$100,000,000", "$100,000"},
+	}
+
+	for _, tt := range tests {
+		inv := models.RandomInvestigator(models.Classic, tt.era)
+		credit := inv.Skills["Credit Rating"]
+		credit.Value = tt.creditRating
+		inv.Skills["Credit Rating"] = credit
+		inv.SetWealth()
+
+		data := convertInvestigatorToMap(inv)
+		if data["Cash"] != tt.cash || data["Assets1"] != tt.assets || data["SpendingLevel"] != tt.spending {
+			t.Errorf("%s Credit Rating %d: expected %s, %s, %s, got %s, %s, %s", tt.era, tt.creditRating,
+				tt.cash, tt.assets, tt.spending, data["Cash"], data["Assets1"], data["SpendingLevel"])
+		}
+	}
+}
+
+func TestExportPDF(t *testing.T) {
+	h, store := newTestHandler()
+	h.sheet = &characterSheet{path: testSheetPath}
+
+	inv := models.RandomInvestigator(models.Pulp, models.Modern)
+	inv.Name = "Harvey Walters"
+	store.SaveInvestigator(context.Background(), "owner-1", inv)
+
+	t.Run("streams the filled sheet", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ExportPDF(w, withOwner(requestWithParams("POST", "/", nil, []string{inv.ID}), "owner-1", nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("Content-Disposition"); got != `attachment; filename=Harvey_Walters.pdf` {
+			t.Errorf("unexpected Content-Disposition %q", got)
+		}
+
+		filled, err := pdfform.Parse(w.Body.Bytes())
+		if err != nil {
+			t.Fatalf("failed to parse exported PDF: %v", err)
+		}
+		values := filled.Values()
+		if values["Investigators_Name"] != "Harvey Walters" {
+			t.Errorf("expected name Harvey Walters, got %q", values["Investigators_Name"])
+		}
+		if values["Occupation"] != inv.Occupation.Name {
+			t.Errorf("expected occupation %s, got %q", inv.Occupation.Name, values["Occupation"])
+		}
+	})
+
+	t.Run("returns not found for another owner", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ExportPDF(w, withOwner(requestWithParams("POST", "/", nil, []string{inv.ID}), "owner-2", nil))
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+
+	t.Run("reports a missing template", func(t *testing.T) {
+		h.sheet = &characterSheet{path: "missing.pdf"}
+		w := httptest.NewRecorder()
+		h.ExportPDF(w, withOwner(requestWithParams("POST", "/", nil, []string{inv.ID}), "owner-1", nil))
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+		}
+	})
+}
+
+// sheetUpload builds a multipart request uploading data as the character sheet
+func sheetUpload(t *testing.T, data []byte) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("sheet", "sheet.pdf")
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	part.Write(data)
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/api/investigator/import/pdf", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return withOwner(req, "owner-1", nil)
+}
+
+func TestImportPDF(t *testing.T) {
+	h, store := newTestHandler()
+
+	sheet, err := (&characterSheet{path: testSheetPath}).load()
+	if err != nil {
+		t.Fatalf("failed to load character sheet: %v", err)
+	}
+
+	inv := models.RandomInvestigator(models.Pulp, models.Modern)
+	inv.Name = "Harvey Walters"
+	inv.Phobias = []models.Phobia{models.Phobias["Arachnophobia"]}
+	inv.Manias = []models.Mania{models.Manias["Bibliomania"]}
+	inv.MajorWound = true
+	history := inv.Skills["History"]
+	history.IsSelected = true
+	inv.Skills["History"] = history
+
+	filled, _, err := sheet.Fill(convertInvestigatorToMap(inv))
+	if err != nil {
+		t.Fatalf("failed to fill character sheet: %v", err)
+	}
+	data, _ := io.ReadAll(filled)
+
+	t.Run("imports an exported sheet", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ImportPDF(w, sheetUpload(t, data))
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+		if w.Header().Get("HX-Trigger") != "import" {
+			t.Errorf("expected HX-Trigger import, got %q", w.Header().Get("HX-Trigger"))
+		}
+
+		var response map[string]string
+		json.Unmarshal(w.Body.Bytes(), &response)
+		imported, ok := store.investigators[response["Key"]]
+		if !ok {
+			t.Fatalf("expected investigator %q to be saved", response["Key"])
+		}
+
+		if imported.Name != inv.Name || imported.Occupation.Name != inv.Occupation.Name || imported.Archetype.Name != inv.Archetype.Name {
+			t.Errorf("expected %s, %s, %s, got %s, %s, %s", inv.Name, inv.Occupation.Name, inv.Archetype.Name,
+				imported.Name, imported.Occupation.Name, imported.Archetype.Name)
+		}
+		for key, attr := range inv.Attributes {
+			if imported.Attributes[key].Value != attr.Value {
+				t.Errorf("expected %s %d, got %d", key, attr.Value, imported.Attributes[key].Value)
+			}
+		}
+		for name, skill := range inv.Skills {
+			if skill.Base == 1 || skill.NeedsFormDef == 1 {
+				continue
+			}
+			if imported.Skills[name].Value != skill.Value {
+				t.Errorf("expected %s %d, got %d", name, skill.Value, imported.Skills[name].Value)
+			}
+		}
+		if !imported.Skills["History"].IsSelected {
+			t.Error("expected History to be selected")
+		}
+		if !imported.MajorWound {
+			t.Error("expected major wound to be ticked")
+		}
+		if len(imported.Talents) != len(inv.Talents) {
+			t.Errorf("expected %d talents, got %d", len(inv.Talents), len(imported.Talents))
+		}
+		if len(imported.Phobias) != 1 || imported.Phobias[0].Description == "" || len(imported.Manias) != 1 {
+			t.Errorf("expected the phobia and mania from the catalogs, got %v and %v", imported.Phobias, imported.Manias)
+		}
+	})
+
+	t.Run("rejects a blank sheet", func(t *testing.T) {
+		blank, _, _ := sheet.Fill(map[string]string{})
+		data, _ := io.ReadAll(blank)
+
+		w := httptest.NewRecorder()
+		h.ImportPDF(w, sheetUpload(t, data))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("rejects a file that is not a PDF", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ImportPDF(w, sheetUpload(t, []byte("not a pdf")))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("rejects a request without a sheet", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/investigator/import/pdf", strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		h.ImportPDF(w, withOwner(req, "owner-1", nil))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
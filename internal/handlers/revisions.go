@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/internal/validation"
+	"book-of-shadows/models"
+	"book-of-shadows/storage"
+)
+
+// ListRevisions returns the change history of an investigator, newest first
+func (h *Handler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) == 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing investigator ID", nil))
+		return
+	}
+	id := params[0]
+
+	// Only the owner of the investigator can read its history
+	ctx := r.Context()
+	ownerID := storage.OwnerFromContext(ctx)
+	if _, err := h.store.GetInvestigator(ctx, ownerID, id); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	revisions, err := h.store.ListRevisions(ctx, ownerID, id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, revisions)
+}
+
+// RevertRevision restores an investigator to the state it had before a revision was
+// applied, undoing that change and every later one. The revert is itself recorded so
+// it can be undone as well.
+func (h *Handler) RevertRevision(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) < 2 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing investigator or revision ID", nil))
+		return
+	}
+	id := params[0]
+
+	revisionID, err := strconv.ParseInt(params[1], 10, 64)
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid revision ID", err))
+		return
+	}
+
+	ctx := r.Context()
+	ownerID := storage.OwnerFromContext(ctx)
+	current, err := h.store.GetInvestigator(ctx, ownerID, id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	revision, err := h.store.GetRevision(ctx, ownerID, id, revisionID)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	restored, err := models.InvestigatorFromJSON(revision.Snapshot)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	restored.ID = current.ID
+	// Strict rules are not the player's to undo
+	restored.StrictRules = current.StrictRules
+
+	snapshot, err := current.ToJSON()
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err := h.saveInvestigator(ctx, ownerID, id, restored, validation.Check(current)); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.recordRevision(ctx, id, models.RevisionSectionRevert, params[1], nil, revisionID, snapshot)
+
+	w.Header().Set("HX-Trigger", "reverted")
+	w.WriteHeader(http.StatusOK)
+}
+
+// recordRevision stores a revision for an applied change. The change itself is already
+// saved, so a failure only loses history and is logged instead of failing the request.
+func (h *Handler) recordRevision(ctx context.Context, id, section, field string, oldValue, newValue interface{}, snapshot []byte) {
+	h.recordRevisionFor(ctx, storage.OwnerFromContext(ctx), id, section, field, oldValue, newValue, snapshot)
+}
+
+// recordRevisionFor stores a revision in the history of another owner's investigator,
+// such as a keeper's change to a campaign member, authored by the caller in ctx
+func (h *Handler) recordRevisionFor(ctx context.Context, ownerID, id, section, field string, oldValue, newValue interface{}, snapshot []byte) {
+	revision := &models.Revision{
+		InvestigatorID: id,
+		AuthorID:       storage.OwnerFromContext(ctx),
+		Section:        section,
+		Field:          field,
+		OldValue:       oldValue,
+		NewValue:       newValue,
+		Snapshot:       snapshot,
+	}
+	if user := storage.UserFromContext(ctx); user != nil {
+		revision.AuthorName = user.Username
+	}
+
+	if err := h.store.SaveRevision(ctx, ownerID, revision); err != nil {
+		h.logger.Printf("Failed to record revision for %s: %v", id, err)
+	}
+}
+
+// revisionValue returns the current value of the field targeted by an update request
+func revisionValue(inv *models.Investigator, req *UpdateRequest) interface{} {
+	switch req.Section {
+	case "attributes", "combat":
+		if attr, ok := inv.Attributes[req.Field]; ok {
+			return attr.Value
+		}
+	case "skills":
+		if skill, ok := inv.Skills[req.Field]; ok {
+			return skill.Value
+		}
+	case "skill_check":
+		if skill, ok := inv.Skills[req.Field]; ok {
+			return skill.IsSelected
+		}
+	case "skill_prio":
+		if skill, ok := inv.Skills[req.Field]; ok {
+			return skill.IsPriority
+		}
+	case "skill_name":
+		if skill, ok := inv.Skills[req.Field]; ok {
+			return skill.Name
+		}
+		// After a rename the skill is indexed by its new name
+		if newName, ok := req.Value.(string); ok {
+			if skill, ok := inv.Skills[newName]; ok {
+				return skill.Name
+			}
+		}
+	case "stats":
+		switch req.Field {
+		case "TemporaryInsane":
+			return inv.TemporaryInsane
+		case "IndefiniteInsane":
+			return inv.IndefiniteInsane
+		case "MajorWound":
+			return inv.MajorWound
+		case "Unconscious":
+			return inv.Unconscious
+		case "Dying":
+			return inv.Dying
+		case "StrictRules":
+			return inv.StrictRules
+		}
+	case "personalInfo":
+		switch req.Field {
+		case "Name":
+			return inv.Name
+		case "Age":
+			return inv.Age
+		case "Residence":
+			return inv.Residence
+		case "Birthplace":
+			return inv.Birthplace
+		}
+	case "talents":
+		for _, t := range inv.Talents {
+			if t.Name == req.Field {
+				return true
+			}
+		}
+		return false
+	case "phobias":
+		for _, p := range inv.Phobias {
+			if p.Name == req.Field {
+				return true
+			}
+		}
+		return false
+	case "manias":
+		for _, m := range inv.Manias {
+			if m.Name == req.Field {
+				return true
+			}
+		}
+		return false
+	}
+	return nil
+}
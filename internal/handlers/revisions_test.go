@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"book-of-shadows/models"
+)
+
+// updateName sends a personal info update renaming the investigator
+func updateName(t *testing.T, h *Handler, id, name string) {
+	t.Helper()
+
+	body, _ := json.Marshal(UpdateRequest{Section: "personalInfo", Field: "Name", Value: name})
+	w := httptest.NewRecorder()
+	h.UpdateInvestigator(w, withOwner(requestWithParams("PUT", "/api/investigator/"+id, body, []string{id}), "owner-1", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+}
+
+func TestUpdateInvestigatorRecordsRevision(t *testing.T) {
+	h, store := newTestHandler()
+	inv := models.RandomInvestigator(models.Pulp, models.Modern)
+	inv.Name = "Original"
+	store.SaveInvestigator(context.Background(), "owner-1", inv)
+
+	updateName(t, h, inv.ID, "Renamed")
+
+	if len(store.revisions) != 1 {
+		t.Fatalf("expected 1 revision, got %d", len(store.revisions))
+	}
+	rev := store.revisions[0]
+	if rev.Section != "personalInfo" || rev.Field != "Name" {
+		t.Errorf("expected personalInfo/Name, got %s/%s", rev.Section, rev.Field)
+	}
+	if rev.OldValue != "Original" || rev.NewValue != "Renamed" {
+		t.Errorf("expected Original -> Renamed, got %v -> %v", rev.OldValue, rev.NewValue)
+	}
+	if rev.AuthorID != "owner-1" {
+		t.Errorf("expected author owner-1, got %s", rev.AuthorID)
+	}
+}
+
+func TestListRevisions(t *testing.T) {
+	h, store := newTestHandler()
+	inv := models.RandomInvestigator(models.Pulp, models.Modern)
+	store.SaveInvestigator(context.Background(), "owner-1", inv)
+
+	updateName(t, h, inv.ID, "First")
+	updateName(t, h, inv.ID, "Second")
+
+	t.Run("lists newest revision first", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ListRevisions(w, withOwner(requestWithParams("GET", "/", nil, []string{inv.ID}), "owner-1", nil))
+
+		var revisions []models.Revision
+		if err := json.Unmarshal(w.Body.Bytes(), &revisions); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if len(revisions) != 2 || revisions[0].NewValue != "Second" {
+			t.Errorf("expected 2 revisions starting with Second, got %+v", revisions)
+		}
+	})
+
+	t.Run("hides history from other owners", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ListRevisions(w, withOwner(requestWithParams("GET", "/", nil, []string{inv.ID}), "owner-2", nil))
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
+
+func TestRevertRevision(t *testing.T) {
+	h, store := newTestHandler()
+	inv := models.RandomInvestigator(models.Pulp, models.Modern)
+	inv.Name = "Original"
+	store.SaveInvestigator(context.Background(), "owner-1", inv)
+
+	updateName(t, h, inv.ID, "First")
+	updateName(t, h, inv.ID, "Second")
+
+	t.Run("restores the sheet before the revision", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.RevertRevision(w, withOwner(requestWithParams("POST", "/", nil, []string{inv.ID, "1"}), "owner-1", nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if name := store.investigators[inv.ID].Name; name != "Original" {
+			t.Errorf("expected name Original, got %s", name)
+		}
+		if last := store.revisions[len(store.revisions)-1]; last.Section != models.RevisionSectionRevert {
+			t.Errorf("expected revert to be recorded, got %s", last.Section)
+		}
+	})
+
+	t.Run("returns 400 for invalid revision", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.RevertRevision(w, withOwner(requestWithParams("POST", "/", nil, []string{inv.ID, "abc"}), "owner-1", nil))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("returns 404 for unknown revision", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.RevertRevision(w, withOwner(requestWithParams("POST", "/", nil, []string{inv.ID, "99"}), "owner-1", nil))
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/internal/validation"
+	"book-of-shadows/models"
+	"book-of-shadows/storage"
+)
+
+// SanityLossRequest is the body of a Sanity roll
+type SanityLossRequest struct {
+	Loss string `json:"loss"` // Loss in success/failure notation, e.g. "1/1D6"
+	Day  string `json:"day"`  // Game day the loss happens on, today's date when empty
+}
+
+// LoseSanity makes a Sanity roll for an investigator and applies the loss with any insanity it brings
+func (h *Handler) LoseSanity(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) == 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing investigator ID", nil))
+		return
+	}
+	id := params[0]
+
+	var req SanityLossRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid JSON", err))
+		return
+	}
+	defer r.Body.Close()
+
+	loss, err := models.ParseSanityLoss(req.Loss)
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid sanity loss: "+err.Error(), err))
+		return
+	}
+	day := strings.TrimSpace(req.Day)
+	if day == "" {
+		day = time.Now().UTC().Format(time.DateOnly)
+	}
+
+	ctx := r.Context()
+	ownerID := storage.OwnerFromContext(ctx)
+	investigator, err := h.store.GetInvestigator(ctx, ownerID, id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	snapshot, err := investigator.ToJSON()
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	oldSanity := investigator.Attributes[models.AttrSanity].Value
+	violations := validation.Check(investigator)
+
+	result, err := investigator.LoseSanity(newRand(), loss, day)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err := h.saveInvestigator(ctx, ownerID, id, investigator, violations); err != nil {
+		h.respondError(w, err)
+		return
+	}
+	h.recordRevision(ctx, id, "sanity", models.AttrSanity, oldSanity, result.Sanity, snapshot)
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// BoutRequest is the body of a bout of madness roll
+type BoutRequest struct {
+	Kind         string `json:"kind"`         // "realtime" or "summary", real-time when empty
+	AddCondition bool   `json:"addCondition"` // Append a resulting phobia or mania to the investigator
+}
+
+// RollBout rolls a bout of madness for an investigator
+func (h *Handler) RollBout(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) == 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing investigator ID", nil))
+		return
+	}
+	id := params[0]
+
+	var req BoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid JSON", err))
+		return
+	}
+	defer r.Body.Close()
+
+	kind, err := models.ParseBoutKind(req.Kind)
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Unknown bout of madness", err))
+		return
+	}
+
+	ctx := r.Context()
+	ownerID := storage.OwnerFromContext(ctx)
+	investigator, err := h.store.GetInvestigator(ctx, ownerID, id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	snapshot, err := investigator.ToJSON()
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	violations := validation.Check(investigator)
+	bout := investigator.RollBout(newRand(), kind, req.AddCondition)
+
+	if bout.Added {
+		if err := h.saveInvestigator(ctx, ownerID, id, investigator, violations); err != nil {
+			h.respondError(w, err)
+			return
+		}
+		if bout.Phobia != nil {
+			h.recordRevision(ctx, id, "phobias", bout.Phobia.Name, false, true, snapshot)
+		} else {
+			h.recordRevision(ctx, id, "manias", bout.Mania.Name, false, true, snapshot)
+		}
+	}
+
+	h.respondJSON(w, http.StatusOK, bout)
+}
+
+// RollMemberBout rolls a bout of madness for an investigator in one of the keeper's campaigns.
+// A resulting phobia or mania is written to the sheet of the player that owns it.
+func (h *Handler) RollMemberBout(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) < 2 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing campaign or investigator ID", nil))
+		return
+	}
+
+	var req BoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid JSON", err))
+		return
+	}
+	defer r.Body.Close()
+
+	kind, err := models.ParseBoutKind(req.Kind)
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Unknown bout of madness", err))
+		return
+	}
+
+	ctx := r.Context()
+	member, err := h.store.GetCampaignMember(ctx, storage.OwnerFromContext(ctx), params[0], params[1])
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	// The campaign copy is enough to roll on, the player's own sheet is needed to change it
+	investigator := member.Investigator
+	if req.AddCondition {
+		if investigator, err = h.store.GetMemberInvestigator(ctx, member); err != nil {
+			if err == errors.ErrNotFound {
+				err = errors.NewHTTPError(http.StatusConflict, "The player keeps this investigator in their browser, add the condition from their sheet", err)
+			}
+			h.respondError(w, err)
+			return
+		}
+	}
+
+	snapshot, err := investigator.ToJSON()
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	bout := investigator.RollBout(newRand(), kind, req.AddCondition)
+
+	if bout.Added {
+		if err := h.store.UpdateMemberInvestigator(ctx, member, investigator); err != nil {
+			h.respondError(w, err)
+			return
+		}
+		if bout.Phobia != nil {
+			h.recordRevisionFor(ctx, member.OwnerID, member.InvestigatorID, "phobias", bout.Phobia.Name, false, true, snapshot)
+		} else {
+			h.recordRevisionFor(ctx, member.OwnerID, member.InvestigatorID, "manias", bout.Mania.Name, false, true, snapshot)
+		}
+	}
+
+	h.respondJSON(w, http.StatusOK, bout)
+}
@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"book-of-shadows/models"
+)
+
+func TestLoseSanity(t *testing.T) {
+	// newInvestigator stores an investigator with the given Sanity
+	newInvestigator := func(store *MockStore, sanity int) *models.Investigator {
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		san := inv.Attributes[models.AttrSanity]
+		san.Value = sanity
+		inv.Attributes[models.AttrSanity] = san
+		store.SaveInvestigator(context.Background(), "owner-1", inv)
+		return inv
+	}
+	// loseSanity applies a loss as owner-1 and decodes the result
+	loseSanity := func(t *testing.T, h *Handler, id string, req SanityLossRequest) models.SanityResult {
+		t.Helper()
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		h.LoseSanity(w, withOwner(requestWithParams("POST", "/api/investigator/"+id+"/sanity", body, []string{id}), "owner-1", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var result models.SanityResult
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return result
+	}
+
+	t.Run("applies the loss for the Sanity roll", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := newInvestigator(store, 60)
+
+		result := loseSanity(t, h, inv.ID, SanityLossRequest{Loss: "1/1D6", Day: "day-1"})
+
+		if result.Check.Success && result.Lost != 1 || !result.Check.Success && (result.Lost < 1 || result.Lost > 6) {
+			t.Errorf("expected the loss to match the Sanity roll, got %+v", result)
+		}
+		if got := store.investigators[inv.ID].Attributes[models.AttrSanity].Value; got != 60-result.Lost || result.Sanity != got {
+			t.Errorf("expected Sanity %d, got %d", 60-result.Lost, got)
+		}
+		if len(store.revisions) != 1 || store.revisions[0].Section != "sanity" {
+			t.Error("expected the loss to be recorded in the history")
+		}
+	})
+
+	t.Run("makes an INT roll after losing 5 or more", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := newInvestigator(store, 80)
+
+		result := loseSanity(t, h, inv.ID, SanityLossRequest{Loss: "5/5", Day: "day-1"})
+
+		if result.IntelligenceRoll == nil {
+			t.Fatal("expected an INT roll")
+		}
+		if result.TemporaryInsanity != result.IntelligenceRoll.Success || store.investigators[inv.ID].TemporaryInsane != result.TemporaryInsanity {
+			t.Errorf("expected temporary insanity on a successful INT roll, got %+v", result)
+		}
+		if result.TemporaryInsanity && (result.TemporaryHours < 1 || result.TemporaryHours > 10) {
+			t.Errorf("expected temporary insanity to last 1D10 hours, got %d", result.TemporaryHours)
+		}
+	})
+
+	t.Run("goes indefinitely insane after losing a fifth in a day", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := newInvestigator(store, 50)
+
+		for i := 1; i <= 3; i++ {
+			result := loseSanity(t, h, inv.ID, SanityLossRequest{Loss: "3/3", Day: "day-1"})
+			if result.IndefiniteInsanity || result.Day.Lost != 3*i || result.Day.StartingSanity != 50 {
+				t.Fatalf("expected %d lost today without insanity, got %+v", 3*i, result)
+			}
+		}
+		result := loseSanity(t, h, inv.ID, SanityLossRequest{Loss: "3/3", Day: "day-1"})
+		if !result.IndefiniteInsanity || !store.investigators[inv.ID].IndefiniteInsane {
+			t.Errorf("expected indefinite insanity after losing 12 of 50, got %+v", result)
+		}
+	})
+
+	t.Run("starts a new tally each day and ends temporary insanity", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := newInvestigator(store, 50)
+		inv.TemporaryInsane = true
+		inv.SanityDay = models.SanityDay{Day: "day-1", StartingSanity: 60, Lost: 9}
+
+		result := loseSanity(t, h, inv.ID, SanityLossRequest{Loss: "3/3", Day: "day-2"})
+
+		if result.Day.Lost != 3 || result.Day.StartingSanity != 50 || result.IndefiniteInsanity {
+			t.Errorf("expected a fresh tally for the new day, got %+v", result.Day)
+		}
+		if store.investigators[inv.ID].TemporaryInsane {
+			t.Error("expected yesterday's temporary insanity to be over")
+		}
+	})
+
+	t.Run("goes permanently insane at zero", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := newInvestigator(store, 3)
+
+		result := loseSanity(t, h, inv.ID, SanityLossRequest{Loss: "5/5"})
+
+		if result.Lost != 3 || result.Sanity != 0 || !result.PermanentInsanity || !store.investigators[inv.ID].Insane {
+			t.Errorf("expected Sanity to stop at zero with permanent insanity, got %+v", result)
+		}
+		if result.Day.Day == "" {
+			t.Error("expected the loss to be tallied against today")
+		}
+	})
+
+	tests := []struct {
+		name string
+		id   string
+		loss string
+		code int
+	}{
+		{"loss without a slash", "", "1D6", http.StatusBadRequest},
+		{"invalid dice", "", "1/1D", http.StatusBadRequest},
+		{"loss with variables", "", "1/DB", http.StatusBadRequest},
+		{"unknown investigator", "missing", "1/1D6", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run("rejects "+tt.name, func(t *testing.T) {
+			h, store := newTestHandler()
+			id := tt.id
+			if id == "" {
+				id = newInvestigator(store, 50).ID
+			}
+			body, _ := json.Marshal(SanityLossRequest{Loss: tt.loss})
+			w := httptest.NewRecorder()
+
+			h.LoseSanity(w, withOwner(requestWithParams("POST", "/api/investigator/"+id+"/sanity", body, []string{id}), "owner-1", nil))
+
+			if w.Code != tt.code {
+				t.Errorf("expected status %d, got %d", tt.code, w.Code)
+			}
+		})
+	}
+}
+
+func TestRollBout(t *testing.T) {
+	// rollBout rolls a bout as owner-1
+	rollBout := func(h *Handler, id string, req BoutRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		h.RollBout(w, withOwner(requestWithParams("POST", "/api/investigator/"+id+"/madness", body, []string{id}), "owner-1", nil))
+		return w
+	}
+
+	for _, kind := range []string{"realtime", "summary"} {
+		t.Run("rolls a "+kind+" bout", func(t *testing.T) {
+			h, store := newTestHandler()
+			inv := models.RandomInvestigator(models.Pulp, models.Modern)
+			store.SaveInvestigator(context.Background(), "owner-1", inv)
+
+			w := rollBout(h, inv.ID, BoutRequest{Kind: kind})
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			var bout models.Bout
+			if err := json.Unmarshal(w.Body.Bytes(), &bout); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			unit := map[string]string{"realtime": "rounds", "summary": "hours"}[kind]
+			if bout.Roll < 1 || bout.Roll > 10 || bout.Name == "" || bout.Description == "" {
+				t.Errorf("expected a result from the table, got %+v", bout)
+			}
+			if bout.Duration < 1 || bout.Duration > 10 || bout.DurationUnit != unit {
+				t.Errorf("expected 1D10 %s, got %d %s", unit, bout.Duration, bout.DurationUnit)
+			}
+		})
+	}
+
+	t.Run("adds a resulting phobia or mania", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		inv.Phobias, inv.Manias = nil, nil
+		store.SaveInvestigator(context.Background(), "owner-1", inv)
+
+		gained := 0
+		for i := 0; i < 100; i++ {
+			var bout models.Bout
+			json.Unmarshal(rollBout(h, inv.ID, BoutRequest{Kind: "summary", AddCondition: true}).Body.Bytes(), &bout)
+			if bout.Phobia != nil || bout.Mania != nil {
+				if !bout.Added {
+					t.Fatalf("expected the condition to be added, got %+v", bout)
+				}
+				gained++
+			}
+		}
+
+		saved := store.investigators[inv.ID]
+		if gained == 0 || len(saved.Phobias)+len(saved.Manias) != gained || len(store.revisions) != gained {
+			t.Errorf("expected %d conditions added and recorded, got %d phobias, %d manias and %d revisions",
+				gained, len(saved.Phobias), len(saved.Manias), len(store.revisions))
+		}
+		seen := make(map[string]bool)
+		for _, p := range saved.Phobias {
+			if seen[p.Name] {
+				t.Errorf("expected %s to be added once", p.Name)
+			}
+			seen[p.Name] = true
+		}
+	})
+
+	t.Run("leaves the sheet alone without addCondition", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		inv.Phobias, inv.Manias = nil, nil
+		store.SaveInvestigator(context.Background(), "owner-1", inv)
+
+		for i := 0; i < 50; i++ {
+			rollBout(h, inv.ID, BoutRequest{Kind: "realtime"})
+		}
+
+		if saved := store.investigators[inv.ID]; len(saved.Phobias)+len(saved.Manias) != 0 || len(store.revisions) != 0 {
+			t.Error("expected no conditions to be added")
+		}
+	})
+
+	t.Run("rejects an unknown kind", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		store.SaveInvestigator(context.Background(), "owner-1", inv)
+
+		if w := rollBout(h, inv.ID, BoutRequest{Kind: "weekly"}); w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("rejects an unknown investigator", func(t *testing.T) {
+		h, _ := newTestHandler()
+
+		if w := rollBout(h, "missing", BoutRequest{}); w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
+
+func TestRollMemberBout(t *testing.T) {
+	// rollMemberBout rolls a bout for a campaign member as the keeper
+	rollMemberBout := func(h *Handler, keeperID, campaignID, id string, req BoutRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		path := "/api/campaign/" + campaignID + "/member/" + id + "/madness"
+		h.RollMemberBout(w, withOwner(requestWithParams("POST", path, body, []string{campaignID, id}), keeperID, nil))
+		return w
+	}
+
+	// member creates a campaign for keeper-1 that an investigator of player-1 joined
+	member := func(t *testing.T) (*Handler, *MockStore, *models.Campaign, *models.Investigator) {
+		h, store := newTestHandler()
+		campaign := createCampaign(t, h, "The Haunting")
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		inv.Phobias, inv.Manias = nil, nil
+		store.SaveInvestigator(context.Background(), "player-1", inv)
+		store.JoinCampaign(context.Background(), campaign.JoinCode, "player-1", inv)
+		return h, store, campaign, inv
+	}
+
+	t.Run("adds the condition to the player's sheet", func(t *testing.T) {
+		h, store, campaign, inv := member(t)
+
+		gained := 0
+		for i := 0; i < 100; i++ {
+			w := rollMemberBout(h, "keeper-1", campaign.ID, inv.ID, BoutRequest{Kind: "summary", AddCondition: true})
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			var bout models.Bout
+			json.Unmarshal(w.Body.Bytes(), &bout)
+			if bout.Added {
+				gained++
+			}
+		}
+
+		saved := store.investigators[inv.ID]
+		if gained == 0 || len(saved.Phobias)+len(saved.Manias) != gained || len(store.revisions) != gained {
+			t.Errorf("expected %d conditions added and recorded, got %d phobias, %d manias and %d revisions",
+				gained, len(saved.Phobias), len(saved.Manias), len(store.revisions))
+		}
+		for _, rev := range store.revisions {
+			if rev.AuthorID != "keeper-1" || rev.InvestigatorID != inv.ID {
+				t.Errorf("expected the keeper to author the change to %s, got %+v", inv.ID, rev)
+			}
+		}
+	})
+
+	t.Run("rejects keepers of other campaigns", func(t *testing.T) {
+		h, _, campaign, inv := member(t)
+
+		if w := rollMemberBout(h, "keeper-2", campaign.ID, inv.ID, BoutRequest{Kind: "realtime"}); w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+
+	t.Run("needs the player's sheet to add the condition", func(t *testing.T) {
+		h, store, campaign, inv := member(t)
+		// Sheets kept in the player's browser cookies are out of the keeper's reach
+		delete(store.investigators, inv.ID)
+
+		if w := rollMemberBout(h, "keeper-1", campaign.ID, inv.ID, BoutRequest{Kind: "summary", AddCondition: true}); w.Code != http.StatusConflict {
+			t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+		}
+		if w := rollMemberBout(h, "keeper-1", campaign.ID, inv.ID, BoutRequest{Kind: "summary"}); w.Code != http.StatusOK {
+			t.Errorf("expected the bout to be rolled from the campaign copy, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/internal/validation"
+	"book-of-shadows/models"
+	"book-of-shadows/storage"
+)
+
+// AllocationRequest is the body of a request spending or refunding skill points
+type AllocationRequest struct {
+	Pool   string `json:"pool"`   // occupation, archetype or personal
+	Skill  string `json:"skill"`  // Skill the points go into
+	Points int    `json:"points"` // Points spent, negative to refund them
+}
+
+// PoolStatus is how much of a pool of skill points is spent
+type PoolStatus struct {
+	Total     int `json:"total"`
+	Spent     int `json:"spent"`
+	Remaining int `json:"remaining"`
+}
+
+// SkillPoolsResponse are the pools of an investigator with what each put into every skill
+type SkillPoolsResponse struct {
+	Pools       map[models.SkillPool]PoolStatus `json:"pools"`
+	Allocations models.SkillAllocations         `json:"allocations"`
+	Skill       string                          `json:"skill,omitempty"` // The skill allocated to
+	Value       int                             `json:"value,omitempty"` // Its value after the allocation
+}
+
+// skillPoolsResponse describes the skill point pools of an investigator
+func skillPoolsResponse(investigator *models.Investigator) SkillPoolsResponse {
+	resp := SkillPoolsResponse{Pools: make(map[models.SkillPool]PoolStatus), Allocations: investigator.SkillAllocations}
+	for _, pool := range models.SkillPools {
+		resp.Pools[pool] = PoolStatus{
+			Total:     investigator.PoolTotal(pool),
+			Spent:     investigator.PoolSpent(pool),
+			Remaining: investigator.PoolRemaining(pool),
+		}
+	}
+	if resp.Allocations == nil {
+		resp.Allocations = models.SkillAllocations{}
+	}
+	return resp
+}
+
+// GetSkillPools lists how much of each pool of skill points an investigator spent, and where
+func (h *Handler) GetSkillPools(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) == 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing investigator ID", nil))
+		return
+	}
+	id := params[0]
+
+	ctx := r.Context()
+	investigator, err := h.store.GetInvestigator(ctx, storage.OwnerFromContext(ctx), id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, skillPoolsResponse(investigator))
+}
+
+// AllocateSkillPoints spends points from a pool on a skill, or refunds them to the pool
+func (h *Handler) AllocateSkillPoints(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) == 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing investigator ID", nil))
+		return
+	}
+	id := params[0]
+
+	var req AllocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid JSON", err))
+		return
+	}
+	defer r.Body.Close()
+
+	pool, err := models.ParseSkillPool(req.Pool)
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, err.Error(), err))
+		return
+	}
+
+	ctx := r.Context()
+	ownerID := storage.OwnerFromContext(ctx)
+	investigator, err := h.store.GetInvestigator(ctx, ownerID, id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	snapshot, err := investigator.ToJSON()
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	oldValue := investigator.Skills[req.Skill].Value
+	violations := validation.Check(investigator)
+	if err := investigator.AllocateSkillPoints(pool, req.Skill, req.Points); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Cannot allocate skill points: "+err.Error(), err))
+		return
+	}
+
+	if err := h.saveInvestigator(ctx, ownerID, id, investigator, violations); err != nil {
+		h.respondError(w, err)
+		return
+	}
+	h.recordRevision(ctx, id, "skills", req.Skill, oldValue, investigator.Skills[req.Skill].Value, snapshot)
+
+	resp := skillPoolsResponse(investigator)
+	resp.Skill = req.Skill
+	resp.Value = investigator.Skills[req.Skill].Value
+	h.respondJSON(w, http.StatusOK, resp)
+}
+
+// CompleteCreation ends the wizard, after which skills are edited directly on the sheet
+func (h *Handler) CompleteCreation(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) == 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing investigator ID", nil))
+		return
+	}
+	id := params[0]
+
+	ctx := r.Context()
+	ownerID := storage.OwnerFromContext(ctx)
+	investigator, err := h.store.GetInvestigator(ctx, ownerID, id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	if !investigator.Creating {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	snapshot, err := investigator.ToJSON()
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	violations := validation.Check(investigator)
+
+	investigator.Creating = false
+	if err := h.saveInvestigator(ctx, ownerID, id, investigator, violations); err != nil {
+		h.respondError(w, err)
+		return
+	}
+	h.recordRevision(ctx, id, "creation", "Creating", true, false, snapshot)
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"book-of-shadows/models"
+)
+
+// poolInvestigator stores a Classic Artist with 100 occupation and 50 personal interest
+// points, none spent, and Spot Hidden at 25
+func poolInvestigator(store *MockStore) *models.Investigator {
+	inv := models.SeededInvestigator(models.Classic, models.Modern, 7)
+	inv.ID = "test-id"
+	artist := models.Occupations["Artist"]
+	inv.Occupation = &artist
+	inv.OccupationPoints, inv.FreePoints = 100, 50
+	inv.SkillAllocations = nil
+	inv.SyncSkillPools()
+	skill := inv.Skills["Spot Hidden"]
+	skill.Value = 25
+	inv.Skills["Spot Hidden"] = skill
+	store.investigators[inv.ID] = inv
+	return inv
+}
+
+func TestAllocateSkillPoints(t *testing.T) {
+	// allocate spends points as owner-1
+	allocate := func(h *Handler, req AllocationRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		h.AllocateSkillPoints(w, withOwner(requestWithParams("POST", "/api/investigator/test-id/skill-points", body, []string{"test-id"}), "owner-1", nil))
+		return w
+	}
+	// decode reads the pools from a successful response
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) SkillPoolsResponse {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var result SkillPoolsResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return result
+	}
+
+	t.Run("spends and refunds points per pool", func(t *testing.T) {
+		h, store := newTestHandler()
+		poolInvestigator(store)
+
+		result := decode(t, allocate(h, AllocationRequest{Pool: "occupation", Skill: "Spot Hidden", Points: 30}))
+		if result.Value != 55 || result.Pools[models.PoolOccupation].Remaining != 70 {
+			t.Errorf("expected Spot Hidden 55 with 70 occupation points left, got %+v", result)
+		}
+		result = decode(t, allocate(h, AllocationRequest{Pool: "general", Skill: "Spot Hidden", Points: 10}))
+		if result.Value != 65 || result.Pools[models.PoolPersonal].Remaining != 40 {
+			t.Errorf("expected Spot Hidden 65 with 40 personal interest points left, got %+v", result)
+		}
+		result = decode(t, allocate(h, AllocationRequest{Pool: "occupation", Skill: "Spot Hidden", Points: -10}))
+		if result.Value != 55 || result.Allocations[models.PoolOccupation]["Spot Hidden"] != 20 {
+			t.Errorf("expected 20 occupation points left in Spot Hidden, got %+v", result)
+		}
+
+		saved := store.investigators["test-id"]
+		if saved.UnassignedOccupationPoints != 80 || saved.UnassignedFreePoints != 40 {
+			t.Errorf("expected the unassigned points to follow the pools, got %d and %d", saved.UnassignedOccupationPoints, saved.UnassignedFreePoints)
+		}
+		if len(store.revisions) != 3 || store.revisions[0].Section != "skills" {
+			t.Error("expected every allocation to be recorded in the history")
+		}
+	})
+
+	tests := []struct {
+		name string
+		req  AllocationRequest
+	}{
+		{"overspending a pool", AllocationRequest{Pool: "personal", Skill: "Spot Hidden", Points: 51}},
+		{"refunding points the pool never spent", AllocationRequest{Pool: "personal", Skill: "Spot Hidden", Points: -5}},
+		{"raising a skill above the cap", AllocationRequest{Pool: "occupation", Skill: "Spot Hidden", Points: 66}},
+		{"archetype points for a Classic investigator", AllocationRequest{Pool: "archetype", Skill: "Spot Hidden", Points: 5}},
+		{"buying Cthulhu Mythos", AllocationRequest{Pool: "occupation", Skill: "Cthulhu Mythos", Points: 5}},
+		{"an unknown skill", AllocationRequest{Pool: "occupation", Skill: "Basket Weaving", Points: 5}},
+		{"an unknown pool", AllocationRequest{Pool: "bonus", Skill: "Spot Hidden", Points: 5}},
+	}
+	for _, tt := range tests {
+		t.Run("rejects "+tt.name, func(t *testing.T) {
+			h, store := newTestHandler()
+			poolInvestigator(store)
+
+			if w := allocate(h, tt.req); w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+			}
+			if saved := store.investigators["test-id"]; saved.Skills["Spot Hidden"].Value != 25 || saved.PoolSpent(models.PoolOccupation) != 0 {
+				t.Error("expected a rejected allocation to leave the investigator untouched")
+			}
+		})
+	}
+
+	eligibility := []struct {
+		name    string
+		pool    models.SkillPool
+		skill   string
+		allowed bool
+	}{
+		{"occupation points on a skill of the occupation", models.PoolOccupation, "Psychology", true},
+		{"occupation points on a skill picked from a choice", models.PoolOccupation, "Natural World", true},
+		{"occupation points on Credit Rating", models.PoolOccupation, "Credit Rating", true},
+		{"occupation points on any Art/Craft", models.PoolOccupation, "ArtCraft(Any)", true},
+		{"occupation points on another skill", models.PoolOccupation, "Accounting", false},
+		{"archetype points on a skill of the archetype", models.PoolArchetype, "Climb", true},
+		{"archetype points on another skill", models.PoolArchetype, "Accounting", false},
+		{"personal interest points on any skill", models.PoolPersonal, "Accounting", true},
+	}
+	for _, tt := range eligibility {
+		t.Run("checks "+tt.name, func(t *testing.T) {
+			h, store := newTestHandler()
+			inv := poolInvestigator(store)
+			// Adventurers raise Climb but not Accounting with their archetype points
+			adventurer := models.Archetypes["Adventurer"]
+			inv.Archetype, inv.ArchetypePoints = &adventurer, 100
+
+			w := allocate(h, AllocationRequest{Pool: string(tt.pool), Skill: tt.skill, Points: 5})
+			if tt.allowed && w.Code != http.StatusOK {
+				t.Errorf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			if !tt.allowed && (w.Code != http.StatusBadRequest || inv.PoolSpent(tt.pool) != 0) {
+				t.Errorf("expected status %d and nothing spent, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("keeps spent points when the pools are recalculated", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := poolInvestigator(store)
+		decode(t, allocate(h, AllocationRequest{Pool: "personal", Skill: "Spot Hidden", Points: 20}))
+
+		body, _ := json.Marshal(UpdateRequest{Section: "attributes", Field: models.AttrIntelligence, Value: 60})
+		w := httptest.NewRecorder()
+		h.UpdateInvestigator(w, requestWithParams("PUT", "/api/investigator/test-id", body, []string{"test-id"}))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if inv.FreePoints != 120 || inv.UnassignedFreePoints != 100 {
+			t.Errorf("expected 100 of 120 personal interest points left, got %d of %d", inv.UnassignedFreePoints, inv.FreePoints)
+		}
+	})
+}
+
+func TestGeneratedSkillPools(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		h, store := newTestHandler()
+		inv := models.SeededInvestigator(models.Pulp, models.Modern, seed)
+		inv.ID = "test-id"
+		store.investigators[inv.ID] = inv
+		unassigned := []int{inv.UnassignedArchetypePoints, inv.UnassignedOccupationPoints, inv.UnassignedFreePoints}
+
+		// Typing the same age again recalculates the pools without changing them
+		body, _ := json.Marshal(UpdateRequest{Section: "personalInfo", Field: "Age", Value: inv.Age})
+		w := httptest.NewRecorder()
+		h.UpdateInvestigator(w, requestWithParams("PUT", "/api/investigator/test-id", body, []string{"test-id"}))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		after := []int{inv.UnassignedArchetypePoints, inv.UnassignedOccupationPoints, inv.UnassignedFreePoints}
+		if !slices.Equal(after, unassigned) {
+			t.Errorf("seed %d: expected the points left %v to be kept, got %v", seed, unassigned, after)
+		}
+		for _, pool := range models.SkillPools {
+			if inv.PoolSpent(pool) == 0 && inv.PoolTotal(pool) > 0 {
+				t.Errorf("seed %d: expected the %s points spent at generation to be recorded", seed, pool)
+			}
+		}
+	}
+}
+
+func TestOccupationPointsFormula(t *testing.T) {
+	h, store := newTestHandler()
+	inv := poolInvestigator(store)
+	// Artists earn EDU×2 plus DEX×2 or POW×2, whichever is better
+	for key, value := range map[string]int{models.AttrEducation: 50, models.AttrDexterity: 40, models.AttrPower: 70} {
+		attr := inv.Attributes[key]
+		attr.Value = value
+		inv.Attributes[key] = attr
+	}
+
+	for range 5 {
+		body, _ := json.Marshal(UpdateRequest{Section: "attributes", Field: models.AttrEducation, Value: 50})
+		w := httptest.NewRecorder()
+		h.UpdateInvestigator(w, requestWithParams("PUT", "/api/investigator/test-id", body, []string{"test-id"}))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if inv.OccupationPoints != 50*2+70*2 {
+			t.Fatalf("expected %d occupation points from EDU and POW, got %d", 50*2+70*2, inv.OccupationPoints)
+		}
+	}
+}
+
+func TestGetSkillPools(t *testing.T) {
+	h, store := newTestHandler()
+	inv := poolInvestigator(store)
+	if err := inv.AllocateSkillPoints(models.PoolOccupation, "Spot Hidden", 15); err != nil {
+		t.Fatalf("failed to allocate: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	h.GetSkillPools(w, requestWithParams("GET", "/api/investigator/test-id/skill-points", nil, []string{"test-id"}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var result SkillPoolsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	want := PoolStatus{Total: 100, Spent: 15, Remaining: 85}
+	if result.Pools[models.PoolOccupation] != want || result.Allocations[models.PoolOccupation]["Spot Hidden"] != 15 {
+		t.Errorf("expected %+v with 15 points in Spot Hidden, got %+v", want, result)
+	}
+}
+
+func TestCompleteCreation(t *testing.T) {
+	h, store := newTestHandler()
+	inv := poolInvestigator(store)
+	inv.Creating = true
+	// updateSkill types a skill value as owner-1
+	updateSkill := func() *httptest.ResponseRecorder {
+		body, _ := json.Marshal(UpdateRequest{Section: "skills", Field: "Spot Hidden", Value: 60})
+		w := httptest.NewRecorder()
+		h.UpdateInvestigator(w, withOwner(requestWithParams("PUT", "/api/investigator/test-id", body, []string{"test-id"}), "owner-1", nil))
+		return w
+	}
+
+	if w := updateSkill(); w.Code != http.StatusConflict || inv.Skills["Spot Hidden"].Value != 25 {
+		t.Errorf("expected typed skills to be refused during creation, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := httptest.NewRecorder()
+	h.CompleteCreation(w, withOwner(requestWithParams("POST", "/api/investigator/test-id/complete", nil, []string{"test-id"}), "owner-1", nil))
+	if w.Code != http.StatusOK || inv.Creating {
+		t.Fatalf("expected creation to be complete, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(store.revisions) != 1 || store.revisions[0].Section != "creation" {
+		t.Error("expected completing creation to be recorded in the history")
+	}
+
+	if w := updateSkill(); w.Code != http.StatusOK || inv.Skills["Spot Hidden"].Value != 60 {
+		t.Errorf("expected skills to be typed on the sheet after creation, got %d: %s", w.Code, w.Body.String())
+	}
+}
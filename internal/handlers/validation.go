@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/internal/validation"
+	"book-of-shadows/models"
+	"book-of-shadows/storage"
+)
+
+// ValidationResponse lists the rules an investigator breaks
+type ValidationResponse struct {
+	Legal      bool                   `json:"legal"`
+	Strict     bool                   `json:"strict"` // Whether updates breaking a rule are refused
+	Violations []validation.Violation `json:"violations"`
+}
+
+// CheckRules checks an investigator against the creation rules
+func (h *Handler) CheckRules(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) == 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing investigator ID", nil))
+		return
+	}
+	id := params[0]
+
+	ctx := r.Context()
+	investigator, err := h.store.GetInvestigator(ctx, storage.OwnerFromContext(ctx), id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	violations := validation.Check(investigator)
+	if violations == nil {
+		violations = []validation.Violation{}
+	}
+	h.respondJSON(w, http.StatusOK, ValidationResponse{
+		Legal:      len(violations) == 0,
+		Strict:     investigator.StrictRules,
+		Violations: violations,
+	})
+}
+
+// saveInvestigator stores a changed investigator. Strict investigators refuse changes breaking
+// a rule they kept before, given by the violations checked before the change.
+func (h *Handler) saveInvestigator(ctx context.Context, ownerID, id string, investigator *models.Investigator, before []validation.Violation) error {
+	if err := validation.Enforce(investigator, before); err != nil {
+		return err
+	}
+	return h.store.UpdateInvestigator(ctx, ownerID, id, investigator)
+}
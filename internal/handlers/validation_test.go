@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"book-of-shadows/internal/validation"
+	"book-of-shadows/models"
+)
+
+func TestCheckRules(t *testing.T) {
+	h, store := newTestHandler()
+	inv := models.SeededInvestigator(models.Classic, models.Modern, 7)
+	inv.ID = "test-id"
+	store.investigators[inv.ID] = inv
+
+	// check reads the rules check of the investigator
+	check := func(t *testing.T) ValidationResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.CheckRules(w, requestWithParams("GET", "/api/investigator/test-id/rules", nil, []string{"test-id"}))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var result ValidationResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return result
+	}
+
+	if result := check(t); !result.Legal || len(result.Violations) != 0 {
+		t.Errorf("expected a generated investigator to be legal, got %+v", result)
+	}
+
+	skill := inv.Skills["Spot Hidden"]
+	skill.Value = 150
+	inv.Skills["Spot Hidden"] = skill
+	result := check(t)
+	if result.Legal || len(result.Violations) == 0 || result.Violations[0].Rule != validation.RuleSkillCap {
+		t.Errorf("expected a skill cap violation first, got %+v", result)
+	}
+}
+
+func TestStrictRules(t *testing.T) {
+	// update sends an update for the investigator
+	update := func(h *Handler, section, field string, value interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(UpdateRequest{Section: section, Field: field, Value: value})
+		w := httptest.NewRecorder()
+		h.UpdateInvestigator(w, requestWithParams("PUT", "/api/investigator/test-id", body, []string{"test-id"}))
+		return w
+	}
+
+	t.Run("accepts illegal updates by default", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.SeededInvestigator(models.Classic, models.Modern, 7)
+		inv.ID = "test-id"
+		store.investigators[inv.ID] = inv
+
+		if w := update(h, "skills", "Spot Hidden", 150); w.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("refuses updates breaking a rule once strict", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.SeededInvestigator(models.Classic, models.Modern, 7)
+		inv.ID, inv.Creating = "test-id", true
+		store.investigators[inv.ID] = inv
+
+		if w := update(h, "stats", "StrictRules", true); w.Code != http.StatusOK || !store.investigators["test-id"].StrictRules {
+			t.Fatalf("expected strict rules to be turned on, got status %d", w.Code)
+		}
+		if rev := store.revisions[0]; rev.OldValue != false || rev.NewValue != true {
+			t.Errorf("expected the revision to record false -> true, got %v -> %v", rev.OldValue, rev.NewValue)
+		}
+		inv.Creating = false
+
+		w := update(h, "skills", "Spot Hidden", 150)
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		}
+		var result struct {
+			Violations []validation.Violation `json:"violations"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if len(result.Violations) == 0 || result.Violations[0].Field != "Spot Hidden" {
+			t.Errorf("expected the Spot Hidden violation first, got %v", result.Violations)
+		}
+		if len(store.revisions) != 1 {
+			t.Error("expected the refused update to stay out of the history")
+		}
+
+		if w := update(h, "skills", "Spot Hidden", 60); w.Code != http.StatusOK {
+			t.Errorf("expected a legal update to pass, got status %d", w.Code)
+		}
+	})
+
+	t.Run("leaves strict rules to the keeper after creation", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.SeededInvestigator(models.Classic, models.Modern, 7)
+		inv.ID, inv.StrictRules = "test-id", true
+		store.investigators[inv.ID] = inv
+
+		if w := update(h, "stats", "StrictRules", false); w.Code != http.StatusForbidden {
+			t.Errorf("expected status %d, got %d", http.StatusForbidden, w.Code)
+		}
+		if !store.investigators["test-id"].StrictRules || len(store.revisions) != 0 {
+			t.Error("expected strict rules to stay on")
+		}
+	})
+
+	t.Run("refuses skill points, characteristics and reverts breaking a rule", func(t *testing.T) {
+		// Each change breaks a rule the strict investigator kept
+		changes := map[string]func(h *Handler, store *MockStore) *httptest.ResponseRecorder{
+			"skill points": func(h *Handler, store *MockStore) *httptest.ResponseRecorder {
+				// Artists have a Credit Rating of 50 at most
+				inv := poolInvestigator(store)
+				inv.StrictRules = true
+				body, _ := json.Marshal(AllocationRequest{Pool: "personal", Skill: "Credit Rating", Points: 51 - inv.Skills["Credit Rating"].Value})
+				w := httptest.NewRecorder()
+				h.AllocateSkillPoints(w, requestWithParams("POST", "/api/investigator/test-id/skill-points", body, []string{"test-id"}))
+				return w
+			},
+			"characteristics": func(h *Handler, store *MockStore) *httptest.ResponseRecorder {
+				inv := agingInvestigator(store, 30)
+				inv.ID, inv.StrictRules = "test-id", true
+				store.investigators["test-id"] = inv
+				body, _ := json.Marshal(map[string]int{"STR": 50, "CON": 50, "SIZ": 50, "DEX": 50, "APP": 50, "INT": 50, "POW": 95, "EDU": 50, "LCK": 50})
+				w := httptest.NewRecorder()
+				h.AssignCharacteristics(w, requestWithParams("POST", "/api/investigator/test-id/characteristics", body, []string{"test-id"}))
+				return w
+			},
+			"revert": func(h *Handler, store *MockStore) *httptest.ResponseRecorder {
+				inv := models.SeededInvestigator(models.Classic, models.Modern, 7)
+				inv.ID = "test-id"
+				store.investigators[inv.ID] = inv
+				update(h, "skills", "Spot Hidden", 150)
+				update(h, "skills", "Spot Hidden", 60)
+				inv.StrictRules = true
+				// The second revision goes back to Spot Hidden 150
+				w := httptest.NewRecorder()
+				h.RevertRevision(w, requestWithParams("POST", "/", nil, []string{"test-id", "2"}))
+				return w
+			},
+		}
+		for name, change := range changes {
+			h, store := newTestHandler()
+			if w := change(h, store); w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("%s: expected status %d, got %d: %s", name, http.StatusUnprocessableEntity, w.Code, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("keeps strict rules on when reverting", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.SeededInvestigator(models.Classic, models.Modern, 7)
+		inv.ID = "test-id"
+		store.investigators[inv.ID] = inv
+		update(h, "personalInfo", "Name", "Harvey Walters")
+		inv.StrictRules = true
+
+		w := httptest.NewRecorder()
+		h.RevertRevision(w, requestWithParams("POST", "/", nil, []string{"test-id", "1"}))
+		if w.Code != http.StatusOK || !store.investigators["test-id"].StrictRules {
+			t.Errorf("expected the revert to keep strict rules on, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("keeps the rules through development and the generation method", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.SeededInvestigator(models.Classic, models.Modern, 7)
+		inv.ID, inv.StrictRules = "test-id", true
+		inv.Aging = nil // The generation method is picked before aging
+		store.investigators[inv.ID] = inv
+
+		// Neither ends the creation rules, so the skill cap still holds afterwards
+		w := httptest.NewRecorder()
+		h.Develop(w, requestWithParams("POST", "/api/investigator/test-id/develop", nil, []string{"test-id"}))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected an empty development phase to be refused, got %d: %s", w.Code, w.Body.String())
+		}
+		body, _ := json.Marshal(GenerationRequest{Method: "point-buy"})
+		w = httptest.NewRecorder()
+		h.SetGenerationMethod(w, requestWithParams("POST", "/api/investigator/test-id/generation", body, []string{"test-id"}))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		if len(store.investigators["test-id"].Development) != 0 {
+			t.Error("expected no development phase to be kept")
+		}
+		if w := update(h, "skills", "Spot Hidden", 150); w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected status %d, got %d: %s", http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("lets a rule already broken be worked on, not made worse", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := models.SeededInvestigator(models.Classic, models.Modern, 7)
+		inv.ID = "test-id"
+		inv.StrictRules = true
+		cr := inv.Skills["Credit Rating"]
+		cr.Value = 0
+		inv.Skills["Credit Rating"] = cr
+		store.investigators[inv.ID] = inv
+
+		if w := update(h, "skills", "Credit Rating", 1); w.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if w := update(h, "skills", "Credit Rating", 0); w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected status %d, got %d: %s", http.StatusUnprocessableEntity, w.Code, w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"book-of-shadows/internal/dice"
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/internal/validation"
+	"book-of-shadows/models"
+	"book-of-shadows/storage"
+)
+
+// DamageRequest is the body of a damage request
+type DamageRequest struct {
+	Damage    string `json:"damage"`    // Damage as a number or dice expression, e.g. "1D8+1D4"
+	SpendLuck bool   `json:"spendLuck"` // Pulp only, spend 5 Luck to ignore a major wound
+}
+
+// DamageResponse is a hit taken with the damage roll that caused it
+type DamageResponse struct {
+	Roll dice.Result `json:"roll"`
+	models.DamageResult
+}
+
+// HealRequest is the body of a healing request
+type HealRequest struct {
+	Skill    string `json:"skill"`    // "First Aid" or "Medicine"
+	HealerID string `json:"healerId"` // Investigator making the roll, the patient when empty
+}
+
+// TakeDamage applies a hit to an investigator with its major wound, unconsciousness and dying rules
+func (h *Handler) TakeDamage(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) == 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing investigator ID", nil))
+		return
+	}
+	id := params[0]
+
+	var req DamageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid JSON", err))
+		return
+	}
+	defer r.Body.Close()
+
+	expression, err := dice.Parse(req.Damage)
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid damage: "+err.Error(), err))
+		return
+	}
+	roll, err := expression.Roll(newRand(), nil)
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Cannot roll damage: "+err.Error(), err))
+		return
+	}
+
+	ctx := r.Context()
+	ownerID := storage.OwnerFromContext(ctx)
+	investigator, err := h.store.GetInvestigator(ctx, ownerID, id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	snapshot, err := investigator.ToJSON()
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	oldHP := investigator.Attributes[models.AttrHitPoints].Value
+	violations := validation.Check(investigator)
+
+	result, err := investigator.TakeDamage(newRand(), roll.Total, req.SpendLuck)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	if err := h.saveInvestigator(ctx, ownerID, id, investigator, violations); err != nil {
+		h.respondError(w, err)
+		return
+	}
+	h.recordRevision(ctx, id, "damage", models.AttrHitPoints, oldHP, result.HP, snapshot)
+
+	h.respondJSON(w, http.StatusOK, DamageResponse{Roll: roll, DamageResult: result})
+}
+
+// Heal treats an investigator with First Aid or Medicine
+func (h *Handler) Heal(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value("params").([]string)
+	if len(params) == 0 {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Missing investigator ID", nil))
+		return
+	}
+	id := params[0]
+
+	var req HealRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Invalid JSON", err))
+		return
+	}
+	defer r.Body.Close()
+
+	ctx := r.Context()
+	ownerID := storage.OwnerFromContext(ctx)
+	investigator, err := h.store.GetInvestigator(ctx, ownerID, id)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	healer := investigator
+	healerID := strings.TrimSpace(req.HealerID)
+	if healerID != "" && healerID != id {
+		if healer, err = h.store.GetInvestigator(ctx, ownerID, healerID); err != nil {
+			h.respondError(w, err)
+			return
+		}
+	}
+
+	snapshot, err := investigator.ToJSON()
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	healerSnapshot, err := healer.ToJSON()
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+	oldHP := investigator.Attributes[models.AttrHitPoints].Value
+	alreadyTicked := healer.Skills[req.Skill].IsSelected
+	violations := validation.Check(investigator)
+	healerViolations := validation.Check(healer)
+
+	result, err := investigator.Heal(newRand(), req.Skill, healer)
+	if err != nil {
+		h.respondError(w, errors.NewHTTPError(http.StatusBadRequest, "Cannot heal: "+err.Error(), err))
+		return
+	}
+
+	if err := h.saveInvestigator(ctx, ownerID, id, investigator, violations); err != nil {
+		h.respondError(w, err)
+		return
+	}
+	h.recordRevision(ctx, id, "healing", models.AttrHitPoints, oldHP, result.HP, snapshot)
+
+	// A healer other than the patient keeps their improvement tick on their own sheet
+	if healer != investigator && result.Check.Improvement && !alreadyTicked {
+		if err := h.saveInvestigator(ctx, ownerID, healerID, healer, healerViolations); err != nil {
+			h.respondError(w, err)
+			return
+		}
+		h.recordRevision(ctx, healerID, "skill_check", req.Skill, false, true, healerSnapshot)
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
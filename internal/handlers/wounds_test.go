@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"book-of-shadows/models"
+)
+
+// woundedInvestigator stores an investigator with the given hit points out of 12
+func woundedInvestigator(store *MockStore, mode models.GameMode, hp int) *models.Investigator {
+	inv := models.RandomInvestigator(mode, models.Modern)
+	inv.Attributes[models.AttrHitPoints] = models.Attribute{Name: "CurrentHP", Value: hp, MaxValue: 12}
+	inv.MajorWound, inv.Unconscious, inv.Dying = false, false, false
+	store.SaveInvestigator(context.Background(), "owner-1", inv)
+	return inv
+}
+
+func TestTakeDamage(t *testing.T) {
+	// takeDamage applies a hit as owner-1
+	takeDamage := func(h *Handler, id string, req DamageRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		h.TakeDamage(w, withOwner(requestWithParams("POST", "/api/investigator/"+id+"/damage", body, []string{id}), "owner-1", nil))
+		return w
+	}
+	// decode reads the hit from a successful response
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) DamageResponse {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var result DamageResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return result
+	}
+
+	t.Run("subtracts a light hit", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := woundedInvestigator(store, models.Classic, 12)
+
+		result := decode(t, takeDamage(h, inv.ID, DamageRequest{Damage: "3"}))
+
+		saved := store.investigators[inv.ID]
+		if result.HP != 9 || saved.Attributes[models.AttrHitPoints].Value != 9 {
+			t.Errorf("expected 9 HP left, got %d", result.HP)
+		}
+		if result.MajorWound || saved.MajorWound || saved.Unconscious || result.ConstitutionRoll != nil {
+			t.Errorf("expected no major wound, got %+v", result)
+		}
+		if len(store.revisions) != 1 || store.revisions[0].Section != "damage" {
+			t.Error("expected the hit to be recorded in the history")
+		}
+	})
+
+	t.Run("rolls the damage dice", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := woundedInvestigator(store, models.Classic, 12)
+
+		result := decode(t, takeDamage(h, inv.ID, DamageRequest{Damage: "1D3"}))
+
+		if result.Damage < 1 || result.Damage > 3 || result.Roll.Total != result.Damage || result.HP != 12-result.Damage {
+			t.Errorf("expected 1D3 damage, got %+v", result)
+		}
+	})
+
+	t.Run("marks a major wound at half maximum HP", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := woundedInvestigator(store, models.Classic, 12)
+
+		result := decode(t, takeDamage(h, inv.ID, DamageRequest{Damage: "6"}))
+
+		saved := store.investigators[inv.ID]
+		if !result.MajorWound || !saved.MajorWound || result.ConstitutionRoll == nil {
+			t.Fatalf("expected a major wound with a CON roll, got %+v", result)
+		}
+		if saved.Unconscious != !result.ConstitutionRoll.Success {
+			t.Errorf("expected a failed CON roll to knock the investigator out, got %+v", result)
+		}
+	})
+
+	t.Run("knocks out at zero HP without dying", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := woundedInvestigator(store, models.Classic, 4)
+
+		result := decode(t, takeDamage(h, inv.ID, DamageRequest{Damage: "5"}))
+
+		if result.HP != 0 || !result.Unconscious || result.Dying {
+			t.Errorf("expected unconscious but not dying, got %+v", result)
+		}
+	})
+
+	t.Run("is dying at zero HP with a major wound", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := woundedInvestigator(store, models.Classic, 4)
+		inv.MajorWound = true
+
+		result := decode(t, takeDamage(h, inv.ID, DamageRequest{Damage: "4"}))
+
+		if !result.Dying || !store.investigators[inv.ID].Dying {
+			t.Errorf("expected the investigator to be dying, got %+v", result)
+		}
+	})
+
+	t.Run("kills with more than maximum HP", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := woundedInvestigator(store, models.Classic, 12)
+
+		result := decode(t, takeDamage(h, inv.ID, DamageRequest{Damage: "13"}))
+
+		if !result.Killed || result.HP != 0 {
+			t.Errorf("expected a fatal hit, got %+v", result)
+		}
+	})
+
+	t.Run("lets a Pulp hero spend Luck to ignore a major wound", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := woundedInvestigator(store, models.Pulp, 12)
+		inv.Attributes[models.AttrLuck] = models.Attribute{Name: "CurrentLuck", Value: 50}
+
+		result := decode(t, takeDamage(h, inv.ID, DamageRequest{Damage: "6", SpendLuck: true}))
+
+		saved := store.investigators[inv.ID]
+		if result.MajorWound || saved.MajorWound || result.LuckSpent != 5 || saved.Attributes[models.AttrLuck].Value != 45 {
+			t.Errorf("expected 5 Luck spent instead of a major wound, got %+v", result)
+		}
+	})
+
+	t.Run("does not spend Luck in Classic", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := woundedInvestigator(store, models.Classic, 12)
+		inv.Attributes[models.AttrLuck] = models.Attribute{Name: "CurrentLuck", Value: 50}
+
+		result := decode(t, takeDamage(h, inv.ID, DamageRequest{Damage: "6", SpendLuck: true}))
+
+		if !result.MajorWound || result.LuckSpent != 0 {
+			t.Errorf("expected a major wound, got %+v", result)
+		}
+	})
+
+	t.Run("rejects invalid damage", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := woundedInvestigator(store, models.Classic, 12)
+
+		if w := takeDamage(h, inv.ID, DamageRequest{Damage: "1D"}); w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
+
+func TestHeal(t *testing.T) {
+	// heal treats an investigator as owner-1
+	heal := func(h *Handler, id string, req HealRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		h.Heal(w, withOwner(requestWithParams("POST", "/api/investigator/"+id+"/heal", body, []string{id}), "owner-1", nil))
+		return w
+	}
+	// skilled sets a healing skill high enough to succeed unless fumbled
+	skilled := func(inv *models.Investigator, skill string) {
+		s := inv.Skills[skill]
+		s.Value = 99
+		s.IsSelected = false
+		inv.Skills[skill] = s
+	}
+
+	t.Run("stabilizes a dying investigator with First Aid", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := woundedInvestigator(store, models.Classic, 0)
+		inv.MajorWound, inv.Unconscious, inv.Dying = true, true, true
+		skilled(inv, "First Aid")
+
+		for i := 0; i < 20; i++ {
+			var result models.HealResult
+			w := heal(h, inv.ID, HealRequest{Skill: "First Aid"})
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			json.Unmarshal(w.Body.Bytes(), &result)
+			if result.Check.Success {
+				saved := store.investigators[inv.ID]
+				if !result.Stabilized || saved.Dying || result.HP != 1 || !saved.MajorWound {
+					t.Errorf("expected a stabilized investigator on 1 HP with a major wound, got %+v", result)
+				}
+				if !saved.Skills["First Aid"].IsSelected {
+					t.Error("expected First Aid to be ticked for improvement")
+				}
+				return
+			}
+		}
+		t.Fatal("expected First Aid 99 to succeed at least once")
+	})
+
+	t.Run("heals 1D3 with Medicine and ends a major wound", func(t *testing.T) {
+		h, store := newTestHandler()
+		inv := woundedInvestigator(store, models.Classic, 5)
+		inv.MajorWound = true
+		skilled(inv, "Medicine")
+
+		for i := 0; i < 20; i++ {
+			var result models.HealResult
+			json.Unmarshal(heal(h, inv.ID, HealRequest{Skill: "Medicine"}).Body.Bytes(), &result)
+			if result.Check.Success {
+				if result.Healed < 1 || result.Healed > 3 || result.HP != 5+result.Healed {
+					t.Errorf("expected 1D3 healed, got %+v", result)
+				}
+				if result.MajorWound != (result.HP < 6) {
+					t.Errorf("expected the major wound to end at half maximum HP, got %+v", result)
+				}
+				return
+			}
+		}
+		t.Fatal("expected Medicine 99 to succeed at least once")
+	})
+
+	t.Run("uses another investigator as healer", func(t *testing.T) {
+		h, store := newTestHandler()
+		patient := woundedInvestigator(store, models.Classic, 8)
+		doctor := woundedInvestigator(store, models.Classic, 12)
+		skilled(doctor, "First Aid")
+
+		for i := 0; i < 20; i++ {
+			var result models.HealResult
+			json.Unmarshal(heal(h, patient.ID, HealRequest{Skill: "First Aid", HealerID: doctor.ID}).Body.Bytes(), &result)
+			if result.Check.Value != 99 {
+				t.Fatalf("expected the healer's skill to be used, got %d", result.Check.Value)
+			}
+			if result.Check.Success {
+				if !store.investigators[doctor.ID].Skills["First Aid"].IsSelected {
+					t.Error("expected the healer's First Aid to be ticked")
+				}
+				return
+			}
+		}
+		t.Fatal("expected First Aid 99 to succeed at least once")
+	})
+
+	tests := []struct {
+		name  string
+		skill string
+		dying bool
+	}{
+		{"a skill that does not heal", "Cooking", false},
+		{"Medicine on a dying investigator", "Medicine", true},
+	}
+	for _, tt := range tests {
+		t.Run("rejects "+tt.name, func(t *testing.T) {
+			h, store := newTestHandler()
+			inv := woundedInvestigator(store, models.Classic, 0)
+			inv.Dying = tt.dying
+
+			if w := heal(h, inv.ID, HealRequest{Skill: tt.skill}); w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
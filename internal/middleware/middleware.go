@@ -150,10 +150,30 @@ func ContentTypeJSON(next http.Handler) http.Handler {
 	})
 }
 
+// Upload exempts multipart requests to Path from the JSON content type check
+// and gives them their own body size limit
+type Upload struct {
+	Path     string
+	MaxBytes int64
+}
+
 // ValidateAPIRequest combines common validations for API endpoints
-func ValidateAPIRequest(maxBodyBytes int64) func(http.Handler) http.Handler {
+func ValidateAPIRequest(maxBodyBytes int64, uploads ...Upload) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		maxBodyMiddleware := MaxBodySize(maxBodyBytes)
-		return maxBodyMiddleware(ContentTypeJSON(next))
+		api := maxBodyMiddleware(ContentTypeJSON(next))
+
+		uploadHandlers := make(map[string]http.Handler, len(uploads))
+		for _, upload := range uploads {
+			uploadHandlers[upload.Path] = MaxBodySize(upload.MaxBytes)(next)
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if upload, ok := uploadHandlers[r.URL.Path]; ok && strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+				upload.ServeHTTP(w, r)
+				return
+			}
+			api.ServeHTTP(w, r)
+		})
 	}
 }
\ No newline at end of file
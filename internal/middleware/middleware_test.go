@@ -223,6 +223,64 @@ func TestContentTypeJSON(t *testing.T) {
 	})
 }
 
+func TestValidateAPIRequest(t *testing.T) {
+	handler := ValidateAPIRequest(10, Upload{Path: "/upload", MaxBytes: 100})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("allows multipart uploads to upload paths", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/upload", strings.NewReader(strings.Repeat("a", 50)))
+		req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+	})
+
+	t.Run("limits uploads to their own size", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/upload", strings.NewReader(strings.Repeat("a", 150)))
+		req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("expected status %d, got %d", http.StatusRequestEntityTooLarge, w.Code)
+		}
+	})
+
+	t.Run("rejects multipart requests to other paths", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/test", strings.NewReader("a"))
+		req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("expected status %d, got %d", http.StatusUnsupportedMediaType, w.Code)
+		}
+	})
+
+	t.Run("keeps the API limit for JSON requests to upload paths", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/upload", strings.NewReader(strings.Repeat("a", 50)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("expected status %d, got %d", http.StatusRequestEntityTooLarge, w.Code)
+		}
+	})
+}
+
 func TestChain(t *testing.T) {
 	var order []string
 
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+
+	"book-of-shadows/internal/config"
+	"book-of-shadows/storage"
+
+	"github.com/google/uuid"
+)
+
+// Session identifies the caller and attaches the owner ID and the HTTP exchange used by
+// cookie-backed stores to the request context. A valid login session makes the user the
+// owner; otherwise the browser is identified through a long-lived anonymous owner cookie.
+func Session(cfg *config.Config, users storage.UserStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ownerID := ""
+			if cookie, err := r.Cookie(cfg.Cookie.OwnerName); err == nil {
+				ownerID = cookie.Value
+			}
+
+			if ownerID == "" {
+				ownerID = uuid.New().String()
+				http.SetCookie(w, &http.Cookie{
+					Name:     cfg.Cookie.OwnerName,
+					Value:    ownerID,
+					Path:     "/",
+					MaxAge:   cfg.Cookie.MaxAge,
+					HttpOnly: cfg.Cookie.HttpOnly,
+					Secure:   cfg.Cookie.Secure,
+					SameSite: http.SameSite(cfg.Cookie.SameSite),
+				})
+			}
+
+			ctx := storage.WithOwner(r.Context(), ownerID)
+
+			if cookie, err := r.Cookie(cfg.Auth.SessionCookie); err == nil && users != nil {
+				// Unknown or expired sessions fall back to the anonymous owner
+				if user, err := users.GetSessionUser(ctx, cookie.Value); err == nil {
+					ctx = storage.WithUser(ctx, user)
+					ctx = storage.WithOwner(ctx, user.ID)
+				}
+			}
+
+			ctx = storage.WithCookieSession(ctx, w, r)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
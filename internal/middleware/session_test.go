@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"book-of-shadows/internal/config"
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/models"
+	"book-of-shadows/storage"
+)
+
+// sessionUsers is a UserStore that only knows a fixed set of session tokens
+type sessionUsers map[string]*models.User
+
+func (s sessionUsers) CreateUser(ctx context.Context, username, password string) (*models.User, error) {
+	return nil, errors.ErrInvalidData
+}
+
+func (s sessionUsers) AuthenticateUser(ctx context.Context, username, password string) (*models.User, error) {
+	return nil, errors.ErrInvalidCredentials
+}
+
+func (s sessionUsers) CreateSession(ctx context.Context, userID string) (string, error) {
+	return "", errors.ErrInvalidData
+}
+
+func (s sessionUsers) GetSessionUser(ctx context.Context, token string) (*models.User, error) {
+	if user, ok := s[token]; ok {
+		return user, nil
+	}
+	return nil, errors.ErrNotFound
+}
+
+func (s sessionUsers) DeleteSession(ctx context.Context, token string) error {
+	return nil
+}
+
+func TestSession(t *testing.T) {
+	cfg := &config.Config{
+		Cookie: config.CookieConfig{OwnerName: "owner", MaxAge: 3600, HttpOnly: true},
+		Auth:   config.AuthConfig{SessionCookie: "session"},
+	}
+	users := sessionUsers{"valid-token": {ID: "user-1", Username: "harvey"}}
+
+	t.Run("issues owner cookie for new browsers", func(t *testing.T) {
+		var ownerID string
+		handler := Session(cfg, users)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ownerID = storage.OwnerFromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest("GET", "/test", nil)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if ownerID == "" {
+			t.Fatal("expected owner ID in context")
+		}
+		cookies := w.Result().Cookies()
+		if len(cookies) != 1 || cookies[0].Name != "owner" || cookies[0].Value != ownerID {
+			t.Error("expected owner cookie matching the context owner ID")
+		}
+	})
+
+	t.Run("reuses existing owner cookie", func(t *testing.T) {
+		var ownerID string
+		handler := Session(cfg, users)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ownerID = storage.OwnerFromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.AddCookie(&http.Cookie{Name: "owner", Value: "existing-owner"})
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if ownerID != "existing-owner" {
+			t.Errorf("expected existing-owner, got %s", ownerID)
+		}
+		if len(w.Result().Cookies()) != 0 {
+			t.Error("expected no new cookie")
+		}
+	})
+
+	t.Run("logged in user becomes the owner", func(t *testing.T) {
+		var ownerID string
+		var user *models.User
+		handler := Session(cfg, users)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ownerID = storage.OwnerFromContext(r.Context())
+			user = storage.UserFromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.AddCookie(&http.Cookie{Name: "owner", Value: "existing-owner"})
+		req.AddCookie(&http.Cookie{Name: "session", Value: "valid-token"})
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if ownerID != "user-1" || user == nil || user.Username != "harvey" {
+			t.Errorf("expected user-1 as owner, got %s", ownerID)
+		}
+	})
+
+	t.Run("unknown session stays anonymous", func(t *testing.T) {
+		var ownerID string
+		var user *models.User
+		handler := Session(cfg, users)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ownerID = storage.OwnerFromContext(r.Context())
+			user = storage.UserFromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.AddCookie(&http.Cookie{Name: "owner", Value: "existing-owner"})
+		req.AddCookie(&http.Cookie{Name: "session", Value: "expired-token"})
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if ownerID != "existing-owner" || user != nil {
+			t.Errorf("expected anonymous owner, got %s", ownerID)
+		}
+	})
+}
@@ -0,0 +1,504 @@
+// Package pdfform reads and fills the interactive form (AcroForm) fields of a PDF.
+// Filled values are appended to the original document as an incremental update,
+// so the template is never rewritten and no temporary files are needed.
+package pdfform
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// objectHeader matches the "num gen obj" line that starts an indirect object
+var objectHeader = regexp.MustCompile(`(\d+)[\x00\t\n\f\r ]+(\d+)[\x00\t\n\f\r ]+obj`)
+
+// pushButtonFlag marks button fields that hold no value
+const pushButtonFlag = 1 << 16
+
+// Form is a parsed PDF document with form fields
+type Form struct {
+	data    []byte
+	objects map[int]Object
+	gens    map[int]int
+	trailer *Dict
+	xref    int
+	fields  []*field
+}
+
+// field is a terminal form field and the object that defines it
+type field struct {
+	name   string
+	num    int
+	dict   *Dict
+	button bool
+	kids   []int
+}
+
+// Parse reads the objects and form fields of a PDF document
+func Parse(data []byte) (*Form, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return nil, fmt.Errorf("not a PDF document")
+	}
+
+	f := &Form{
+		data:    data,
+		objects: make(map[int]Object),
+		gens:    make(map[int]int),
+	}
+	f.scanObjects()
+
+	if err := f.readTrailer(); err != nil {
+		return nil, err
+	}
+	f.collectFields()
+
+	return f, nil
+}
+
+// FieldNames returns the sorted, fully qualified names of the form fields
+func (f *Form) FieldNames() []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0, len(f.fields))
+	for _, fld := range f.fields {
+		if !seen[fld.name] {
+			seen[fld.name] = true
+			names = append(names, fld.name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Values returns the current value of every field that has one.
+// Checked boxes are reported as "1" and unchecked boxes are left out.
+func (f *Form) Values() map[string]string {
+	values := make(map[string]string)
+	for _, fld := range f.fields {
+		switch v := f.inherited(fld.dict, "V").(type) {
+		case String:
+			if text := v.Text(); text != "" {
+				values[fld.name] = text
+			}
+		case Name:
+			if fld.button && v != "Off" && v != "" {
+				values[fld.name] = "1"
+			}
+		}
+	}
+	return values
+}
+
+// Fill returns the document with the given field values.
+// Text fields take the value as is; check boxes are ticked by "1", "true", "yes" or "on".
+// Values for unknown fields are ignored.
+func (f *Form) Fill(values map[string]string) (io.Reader, int64, error) {
+	update, err := f.update(values)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	size := int64(len(f.data) + len(update))
+	return io.MultiReader(bytes.NewReader(f.data), bytes.NewReader(update)), size, nil
+}
+
+// update builds the incremental update that stores the field values
+func (f *Form) update(values map[string]string) ([]byte, error) {
+	changed := make(map[int]*Dict)
+	changedDict := func(num int) *Dict {
+		if dict, ok := changed[num]; ok {
+			return dict
+		}
+		dict := f.objects[num].(*Dict).Clone()
+		changed[num] = dict
+		return dict
+	}
+
+	for _, fld := range f.fields {
+		value, ok := values[fld.name]
+		if !ok {
+			continue
+		}
+
+		if !fld.button {
+			changedDict(fld.num).Set("V", TextString(value))
+			continue
+		}
+
+		// Check boxes store the name of their "on" appearance, shared with their widgets
+		widgets := append([]int{fld.num}, fld.kids...)
+		state := Name("Off")
+		if isChecked(value) {
+			state = f.onState(widgets)
+		}
+		changedDict(fld.num).Set("V", state)
+		for _, num := range widgets {
+			if f.objects[num].(*Dict).Get("AP") != nil {
+				changedDict(num).Set("AS", state)
+			}
+		}
+	}
+
+	if err := f.requestAppearances(changedDict); err != nil {
+		return nil, err
+	}
+
+	return f.writeUpdate(changed), nil
+}
+
+// requestAppearances asks viewers to draw the new values instead of the stale appearances
+func (f *Form) requestAppearances(changedDict func(int) *Dict) error {
+	root, ok := f.trailer.Get("Root").(Ref)
+	if !ok {
+		return fmt.Errorf("document has no catalog")
+	}
+	catalog, ok := f.objects[root.Num].(*Dict)
+	if !ok {
+		return fmt.Errorf("document has no catalog")
+	}
+
+	switch acroForm := catalog.Get("AcroForm").(type) {
+	case Ref:
+		form, ok := f.objects[acroForm.Num].(*Dict)
+		if !ok {
+			return fmt.Errorf("invalid AcroForm")
+		}
+		if needs, _ := form.Get("NeedAppearances").(Bool); !needs {
+			changedDict(acroForm.Num).Set("NeedAppearances", Bool(true))
+		}
+	case *Dict:
+		if needs, _ := acroForm.Get("NeedAppearances").(Bool); !needs {
+			form := acroForm.Clone()
+			form.Set("NeedAppearances", Bool(true))
+			changedDict(root.Num).Set("AcroForm", form)
+		}
+	default:
+		return fmt.Errorf("document has no form")
+	}
+
+	return nil
+}
+
+// writeUpdate serializes the changed objects with their cross-reference section and trailer
+func (f *Form) writeUpdate(changed map[int]*Dict) []byte {
+	nums := make([]int, 0, len(changed))
+	for num := range changed {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+
+	var buf bytes.Buffer
+	buf.WriteString("\n")
+
+	offsets := make(map[int]int, len(nums))
+	for _, num := range nums {
+		offsets[num] = len(f.data) + buf.Len()
+		fmt.Fprintf(&buf, "%d %d obj\n", num, f.gens[num])
+		writeObject(&buf, changed[num])
+		buf.WriteString("\nendobj\n")
+	}
+
+	xrefOffset := len(f.data) + buf.Len()
+	// The head of the free list keeps readers that expect a zero-indexed section happy
+	buf.WriteString("xref\n0 1\n0000000000 65535 f \n")
+	for i := 0; i < len(nums); {
+		// Consecutive object numbers share a subsection
+		j := i + 1
+		for j < len(nums) && nums[j] == nums[j-1]+1 {
+			j++
+		}
+		fmt.Fprintf(&buf, "%d %d\n", nums[i], j-i)
+		for _, num := range nums[i:j] {
+			fmt.Fprintf(&buf, "%010d %05d n \n", offsets[num], f.gens[num])
+		}
+		i = j
+	}
+
+	trailer := NewDict()
+	trailer.Set("Size", Number(strconv.Itoa(f.size())))
+	for _, key := range []Name{"Root", "Info", "ID"} {
+		if value := f.trailer.Get(key); value != nil {
+			trailer.Set(key, value)
+		}
+	}
+	trailer.Set("Prev", Number(strconv.Itoa(f.xref)))
+
+	buf.WriteString("trailer\n")
+	writeObject(&buf, trailer)
+	fmt.Fprintf(&buf, "\nstartxref\n%d\n%%%%EOF\n", xrefOffset)
+
+	return buf.Bytes()
+}
+
+// size returns the number of entries the cross-reference table must cover
+func (f *Form) size() int {
+	size, _ := Int(f.trailer.Get("Size"))
+	for num := range f.objects {
+		if num >= size {
+			size = num + 1
+		}
+	}
+	return size
+}
+
+// scanObjects reads every indirect object in file order, so objects redefined by
+// incremental updates replace the original definitions
+func (f *Form) scanObjects() {
+	pos := 0
+	for {
+		loc := objectHeader.FindSubmatchIndex(f.data[pos:])
+		if loc == nil {
+			return
+		}
+		start, end := pos+loc[0], pos+loc[1]
+		if start > 0 && !isWhitespace(f.data[start-1]) && !isDelimiter(f.data[start-1]) {
+			pos = start + 1
+			continue
+		}
+
+		num, _ := strconv.Atoi(string(f.data[pos+loc[2] : pos+loc[3]]))
+		gen, _ := strconv.Atoi(string(f.data[pos+loc[4] : pos+loc[5]]))
+
+		p := &parser{data: f.data, pos: end}
+		obj, err := p.parseObject()
+		if err != nil {
+			pos = end
+			continue
+		}
+		f.objects[num] = obj
+		f.gens[num] = gen
+
+		p.skipSpace()
+		if p.hasPrefix("stream") {
+			p.pos = f.skipStream(p.pos, obj)
+		}
+		pos = p.pos
+	}
+}
+
+// skipStream returns the offset just past the data of a stream starting at pos
+func (f *Form) skipStream(pos int, obj Object) int {
+	pos += len("stream")
+	if pos < len(f.data) && f.data[pos] == '\r' {
+		pos++
+	}
+	if pos < len(f.data) && f.data[pos] == '\n' {
+		pos++
+	}
+
+	if dict, ok := obj.(*Dict); ok {
+		if length, ok := Int(dict.Get("Length")); ok && pos+length <= len(f.data) {
+			pos += length
+		}
+	}
+
+	end := bytes.Index(f.data[pos:], []byte("endstream"))
+	if end < 0 {
+		return len(f.data)
+	}
+	return pos + end + len("endstream")
+}
+
+// readTrailer finds the last cross-reference section and its trailer dictionary
+func (f *Form) readTrailer() error {
+	idx := bytes.LastIndex(f.data, []byte("startxref"))
+	if idx < 0 {
+		return fmt.Errorf("missing startxref")
+	}
+	p := &parser{data: f.data, pos: idx + len("startxref")}
+	p.skipSpace()
+	xref, err := strconv.Atoi(p.token())
+	if err != nil {
+		return fmt.Errorf("invalid startxref: %w", err)
+	}
+	f.xref = xref
+
+	if idx := bytes.LastIndex(f.data, []byte("trailer")); idx >= 0 {
+		p := &parser{data: f.data, pos: idx + len("trailer")}
+		p.skipSpace()
+		if p.hasPrefix("<<") {
+			trailer, err := p.parseDict()
+			if err != nil {
+				return fmt.Errorf("invalid trailer: %w", err)
+			}
+			f.trailer = trailer
+			return nil
+		}
+	}
+
+	// Cross-reference streams carry the trailer entries in their own dictionary
+	loc := objectHeader.FindIndex(f.data[min(xref, len(f.data)):])
+	if loc == nil || loc[0] != 0 {
+		return fmt.Errorf("missing trailer")
+	}
+	p = &parser{data: f.data, pos: xref + loc[1]}
+	dict, err := p.parseObject()
+	if err != nil {
+		return fmt.Errorf("invalid trailer: %w", err)
+	}
+	trailer, ok := dict.(*Dict)
+	if !ok {
+		return fmt.Errorf("missing trailer")
+	}
+	f.trailer = trailer
+	return nil
+}
+
+// collectFields walks the field tree of the AcroForm
+func (f *Form) collectFields() {
+	var roots Array
+	if root, ok := f.trailer.Get("Root").(Ref); ok {
+		if catalog, ok := f.objects[root.Num].(*Dict); ok {
+			if acroForm, ok := f.resolve(catalog.Get("AcroForm")).(*Dict); ok {
+				roots, _ = f.resolve(acroForm.Get("Fields")).(Array)
+			}
+		}
+	}
+
+	visited := make(map[int]bool)
+	var walk func(ref Ref, parent string)
+	walk = func(ref Ref, parent string) {
+		dict, ok := f.objects[ref.Num].(*Dict)
+		if !ok || visited[ref.Num] {
+			return
+		}
+		visited[ref.Num] = true
+
+		name := parent
+		if title, ok := dict.Get("T").(String); ok {
+			if name != "" {
+				name += "."
+			}
+			name += title.Text()
+		}
+
+		// Kids with their own names are fields; kids without are the widgets of this field
+		var widgets []int
+		kids, _ := f.resolve(dict.Get("Kids")).(Array)
+		for _, kid := range kids {
+			kidRef, ok := kid.(Ref)
+			if !ok {
+				continue
+			}
+			if kidDict, ok := f.objects[kidRef.Num].(*Dict); ok && kidDict.Get("T") == nil {
+				widgets = append(widgets, kidRef.Num)
+				continue
+			}
+			walk(kidRef, name)
+		}
+
+		if dict.Get("T") == nil || (len(kids) > 0 && len(widgets) == 0) {
+			return
+		}
+
+		fieldType, _ := f.inherited(dict, "FT").(Name)
+		flags, _ := Int(f.inherited(dict, "Ff"))
+		if fieldType == "Btn" && flags&pushButtonFlag != 0 {
+			return
+		}
+
+		f.fields = append(f.fields, &field{
+			name:   name,
+			num:    ref.Num,
+			dict:   dict,
+			button: fieldType == "Btn",
+			kids:   widgets,
+		})
+	}
+
+	for _, item := range roots {
+		if ref, ok := item.(Ref); ok {
+			walk(ref, "")
+		}
+	}
+
+	// Widgets placed on pages but missing from the field list still carry values
+	for _, num := range f.pageWidgets() {
+		if !visited[num] {
+			walk(Ref{Num: num, Gen: f.gens[num]}, "")
+		}
+	}
+}
+
+// pageWidgets returns the widget annotations of every page that define a field name
+func (f *Form) pageWidgets() []int {
+	var widgets []int
+	nums := make([]int, 0, len(f.objects))
+	for num := range f.objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+
+	for _, num := range nums {
+		page, ok := f.objects[num].(*Dict)
+		if !ok || page.Get("Type") != Name("Page") {
+			continue
+		}
+		annots, _ := f.resolve(page.Get("Annots")).(Array)
+		for _, annot := range annots {
+			ref, ok := annot.(Ref)
+			if !ok {
+				continue
+			}
+			if dict, ok := f.objects[ref.Num].(*Dict); ok && dict.Get("Subtype") == Name("Widget") && dict.Get("T") != nil && dict.Get("Parent") == nil {
+				widgets = append(widgets, ref.Num)
+			}
+		}
+	}
+	return widgets
+}
+
+// onState returns the appearance name that shows a check box as ticked
+func (f *Form) onState(widgets []int) Name {
+	for _, num := range widgets {
+		dict, ok := f.objects[num].(*Dict)
+		if !ok {
+			continue
+		}
+		ap, ok := f.resolve(dict.Get("AP")).(*Dict)
+		if !ok {
+			continue
+		}
+		normal, ok := f.resolve(ap.Get("N")).(*Dict)
+		if !ok {
+			continue
+		}
+		for _, state := range normal.Keys() {
+			if state != "Off" {
+				return state
+			}
+		}
+	}
+	return "Yes"
+}
+
+// inherited looks up an inheritable field attribute on the field and its parents
+func (f *Form) inherited(dict *Dict, key Name) Object {
+	for depth := 0; dict != nil && depth < 32; depth++ {
+		if value := dict.Get(key); value != nil {
+			return f.resolve(value)
+		}
+		dict, _ = f.resolve(dict.Get("Parent")).(*Dict)
+	}
+	return nil
+}
+
+// resolve follows an indirect reference
+func (f *Form) resolve(obj Object) Object {
+	if ref, ok := obj.(Ref); ok {
+		return f.objects[ref.Num]
+	}
+	return obj
+}
+
+// isChecked reports whether a value ticks a check box
+func isChecked(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package pdfform
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// templatePath is the character sheet shipped with the application
+const templatePath = "../../static/modernSheet.pdf"
+
+// parseTemplate parses the character sheet template
+func parseTemplate(t *testing.T) (*Form, []byte) {
+	t.Helper()
+
+	data, err := os.ReadFile(templatePath)
+	if err != nil {
+		t.Fatalf("failed to read template: %v", err)
+	}
+	form, err := Parse(data)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	return form, data
+}
+
+// fill fills form and parses the result
+func fill(t *testing.T, form *Form, values map[string]string) (*Form, []byte) {
+	t.Helper()
+
+	reader, size, err := form.Fill(values)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read filled PDF: %v", err)
+	}
+	if int64(len(data)) != size {
+		t.Errorf("expected size %d, got %d", len(data), size)
+	}
+
+	filled, err := Parse(data)
+	if err != nil {
+		t.Fatalf("failed to parse filled PDF: %v", err)
+	}
+	return filled, data
+}
+
+func TestTemplateFieldNames(t *testing.T) {
+	form, _ := parseTemplate(t)
+	got := strings.Join(form.FieldNames(), "\n") + "\n"
+
+	golden := filepath.Join("testdata", "modernSheet.golden")
+	if *update {
+		if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("field names differ from %s, run go test -update to review the change", golden)
+	}
+}
+
+func TestFill(t *testing.T) {
+	form, original := parseTemplate(t)
+
+	values := map[string]string{
+		"Investigators_Name":   "Zoë (the \"Professor\") O'Hara",
+		"STR":                  "65",
+		"Skill_Accounting_Chk": "1",
+		"Skill_Anthropology":   "",
+		"Not_A_Field":          "ignored",
+	}
+	filled, data := fill(t, form, values)
+
+	t.Run("appends an incremental update", func(t *testing.T) {
+		if !bytes.HasPrefix(data, original) {
+			t.Error("expected the template to be left untouched")
+		}
+		if !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+			t.Error("expected the update to end with an EOF marker")
+		}
+	})
+
+	t.Run("reads back the values", func(t *testing.T) {
+		got := filled.Values()
+		for _, name := range []string{"Investigators_Name", "STR", "Skill_Accounting_Chk"} {
+			if got[name] != values[name] {
+				t.Errorf("expected %s to be %q, got %q", name, values[name], got[name])
+			}
+		}
+		if _, ok := got["Skill_Anthropology"]; ok {
+			t.Error("expected empty value to be left out")
+		}
+		if _, ok := got["Skill_Appraise_Chk"]; ok {
+			t.Error("expected unchecked box to be left out")
+		}
+	})
+
+	t.Run("fills a filled document again", func(t *testing.T) {
+		refilled, _ := fill(t, filled, map[string]string{"STR": "70", "Skill_Accounting_Chk": "0"})
+		got := refilled.Values()
+		if got["STR"] != "70" {
+			t.Errorf("expected STR 70, got %q", got["STR"])
+		}
+		if got["Investigators_Name"] != values["Investigators_Name"] {
+			t.Errorf("expected earlier values to be kept, got %q", got["Investigators_Name"])
+		}
+		if _, ok := got["Skill_Accounting_Chk"]; ok {
+			t.Error("expected box to be unchecked")
+		}
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Run("rejects other documents", func(t *testing.T) {
+		if _, err := Parse([]byte("hello")); err == nil {
+			t.Error("expected error for non PDF data")
+		}
+	})
+
+	t.Run("round trips strings and names", func(t *testing.T) {
+		source := `<< /T (a \(b\) \\ c\101) /H <FEFF00E9> /N#20ame /x /A [1 0 R -2.5 true null] >>`
+		p := &parser{data: []byte(source)}
+		obj, err := p.parseObject()
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		dict := obj.(*Dict)
+		if got := dict.Get("T").(String).Text(); got != `a (b) \ cA` {
+			t.Errorf("unexpected literal string %q", got)
+		}
+		if got := dict.Get("H").(String).Text(); got != "é" {
+			t.Errorf("unexpected hex string %q", got)
+		}
+
+		var buf bytes.Buffer
+		writeObject(&buf, dict)
+		reparsed, err := (&parser{data: buf.Bytes()}).parseObject()
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", buf.String(), err)
+		}
+		var again bytes.Buffer
+		writeObject(&again, reparsed)
+		if buf.String() != again.String() {
+			t.Errorf("expected stable serialization, got %q and %q", buf.String(), again.String())
+		}
+		if reparsed.(*Dict).Get("N ame") != Name("x") {
+			t.Errorf("expected escaped name to round trip, got %q", buf.String())
+		}
+	})
+}
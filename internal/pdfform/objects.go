@@ -0,0 +1,189 @@
+package pdfform
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"unicode/utf16"
+)
+
+// Object is a PDF object: Name, String, Number, Bool, Null, Ref, Array or *Dict
+type Object interface{}
+
+// Name is a PDF name without its leading slash
+type Name string
+
+// String is the decoded content of a literal or hexadecimal PDF string
+type String []byte
+
+// Number keeps the text of a PDF number so it is written back unchanged
+type Number string
+
+// Bool is a PDF boolean
+type Bool bool
+
+// Null is the PDF null object
+type Null struct{}
+
+// Ref is an indirect reference to another object
+type Ref struct {
+	Num int
+	Gen int
+}
+
+// Array is a PDF array
+type Array []Object
+
+// Dict is a PDF dictionary that remembers the order of its keys
+type Dict struct {
+	keys   []Name
+	values map[Name]Object
+}
+
+// NewDict creates an empty dictionary
+func NewDict() *Dict {
+	return &Dict{values: make(map[Name]Object)}
+}
+
+// Get returns the value stored under key, or nil
+func (d *Dict) Get(key Name) Object {
+	return d.values[key]
+}
+
+// Set stores value under key, appending new keys after the existing ones
+func (d *Dict) Set(key Name, value Object) {
+	if _, ok := d.values[key]; !ok {
+		d.keys = append(d.keys, key)
+	}
+	d.values[key] = value
+}
+
+// Keys returns the keys in the order they appear in the file
+func (d *Dict) Keys() []Name {
+	return d.keys
+}
+
+// Clone returns a shallow copy of the dictionary
+func (d *Dict) Clone() *Dict {
+	clone := NewDict()
+	for _, key := range d.keys {
+		clone.Set(key, d.values[key])
+	}
+	return clone
+}
+
+// Int returns the integer value of a Number object
+func Int(obj Object) (int, bool) {
+	n, ok := obj.(Number)
+	if !ok {
+		return 0, false
+	}
+	value, err := strconv.Atoi(string(n))
+	return value, err == nil
+}
+
+// TextString encodes s as a PDF text string, using UTF-16BE when it is not plain ASCII
+func TextString(s string) String {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return String(s)
+	}
+
+	encoded := []byte{0xFE, 0xFF}
+	for _, unit := range utf16.Encode([]rune(s)) {
+		encoded = append(encoded, byte(unit>>8), byte(unit))
+	}
+	return String(encoded)
+}
+
+// Text decodes a PDF text string, which is UTF-16BE with a byte order mark or PDFDocEncoding
+func (s String) Text() string {
+	if len(s) >= 2 && s[0] == 0xFE && s[1] == 0xFF {
+		units := make([]uint16, 0, len(s)/2)
+		for i := 2; i+1 < len(s); i += 2 {
+			units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+		}
+		return string(utf16.Decode(units))
+	}
+
+	// PDFDocEncoding matches Latin-1 for every character a form value is likely to hold
+	runes := make([]rune, len(s))
+	for i, b := range s {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+// writeObject serializes obj in PDF syntax
+func writeObject(buf *bytes.Buffer, obj Object) {
+	switch v := obj.(type) {
+	case nil, Null:
+		buf.WriteString("null")
+	case Name:
+		writeName(buf, v)
+	case String:
+		writeString(buf, v)
+	case Number:
+		buf.WriteString(string(v))
+	case Bool:
+		buf.WriteString(strconv.FormatBool(bool(v)))
+	case Ref:
+		fmt.Fprintf(buf, "%d %d R", v.Num, v.Gen)
+	case Array:
+		buf.WriteString("[")
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteString(" ")
+			}
+			writeObject(buf, item)
+		}
+		buf.WriteString("]")
+	case *Dict:
+		buf.WriteString("<<")
+		for _, key := range v.keys {
+			buf.WriteString(" ")
+			writeName(buf, key)
+			buf.WriteString(" ")
+			writeObject(buf, v.values[key])
+		}
+		buf.WriteString(" >>")
+	default:
+		panic(fmt.Sprintf("pdfform: cannot serialize %T", obj))
+	}
+}
+
+// writeName writes a name, escaping characters that are not regular characters
+func writeName(buf *bytes.Buffer, name Name) {
+	buf.WriteByte('/')
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c < '!' || c > '~' || c == '#' || isDelimiter(c) {
+			fmt.Fprintf(buf, "#%02X", c)
+			continue
+		}
+		buf.WriteByte(c)
+	}
+}
+
+// writeString writes a literal string, escaping delimiters and non-printable bytes
+func writeString(buf *bytes.Buffer, s String) {
+	buf.WriteByte('(')
+	for _, c := range s {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c < ' ' || c > '~':
+			fmt.Fprintf(buf, "\\%03o", c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte(')')
+}
@@ -0,0 +1,262 @@
+package pdfform
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// parser reads PDF objects from a byte slice
+type parser struct {
+	data []byte
+	pos  int
+}
+
+// isWhitespace reports whether c is a PDF whitespace character
+func isWhitespace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+// isDelimiter reports whether c is a PDF delimiter character
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+// skipSpace skips whitespace and comments
+func (p *parser) skipSpace() {
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if c == '%' {
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' && p.data[p.pos] != '\r' {
+				p.pos++
+			}
+			continue
+		}
+		if !isWhitespace(c) {
+			return
+		}
+		p.pos++
+	}
+}
+
+// token reads a run of regular characters
+func (p *parser) token() string {
+	start := p.pos
+	for p.pos < len(p.data) && !isWhitespace(p.data[p.pos]) && !isDelimiter(p.data[p.pos]) {
+		p.pos++
+	}
+	return string(p.data[start:p.pos])
+}
+
+// hasPrefix reports whether the remaining input starts with s
+func (p *parser) hasPrefix(s string) bool {
+	return bytes.HasPrefix(p.data[p.pos:], []byte(s))
+}
+
+// parseObject reads the next object
+func (p *parser) parseObject() (Object, error) {
+	p.skipSpace()
+	if p.pos >= len(p.data) {
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+
+	switch c := p.data[p.pos]; {
+	case p.hasPrefix("<<"):
+		return p.parseDict()
+	case c == '<':
+		return p.parseHexString()
+	case c == '(':
+		return p.parseLiteralString()
+	case c == '[':
+		return p.parseArray()
+	case c == '/':
+		p.pos++
+		return p.parseName(), nil
+	}
+
+	start := p.pos
+	tok := p.token()
+	switch tok {
+	case "":
+		return nil, fmt.Errorf("unexpected %q at offset %d", p.data[p.pos], p.pos)
+	case "true":
+		return Bool(true), nil
+	case "false":
+		return Bool(false), nil
+	case "null":
+		return Null{}, nil
+	}
+
+	if _, err := strconv.ParseFloat(tok, 64); err != nil {
+		return nil, fmt.Errorf("unexpected token %q at offset %d", tok, start)
+	}
+
+	// An integer may be the object number of an indirect reference
+	if num, err := strconv.Atoi(tok); err == nil {
+		save := p.pos
+		p.skipSpace()
+		if gen, err := strconv.Atoi(p.token()); err == nil {
+			p.skipSpace()
+			if p.token() == "R" {
+				return Ref{Num: num, Gen: gen}, nil
+			}
+		}
+		p.pos = save
+	}
+
+	return Number(tok), nil
+}
+
+// parseDict reads a dictionary starting at "<<"
+func (p *parser) parseDict() (*Dict, error) {
+	p.pos += 2
+	dict := NewDict()
+	for {
+		p.skipSpace()
+		if p.hasPrefix(">>") {
+			p.pos += 2
+			return dict, nil
+		}
+		if p.pos >= len(p.data) || p.data[p.pos] != '/' {
+			return nil, fmt.Errorf("expected dictionary key at offset %d", p.pos)
+		}
+		p.pos++
+		key := p.parseName()
+
+		value, err := p.parseObject()
+		if err != nil {
+			return nil, err
+		}
+		dict.Set(key, value)
+	}
+}
+
+// parseArray reads an array starting at "["
+func (p *parser) parseArray() (Array, error) {
+	p.pos++
+	array := Array{}
+	for {
+		p.skipSpace()
+		if p.pos < len(p.data) && p.data[p.pos] == ']' {
+			p.pos++
+			return array, nil
+		}
+		item, err := p.parseObject()
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, item)
+	}
+}
+
+// parseName reads a name after its slash, decoding #xx escapes
+func (p *parser) parseName() Name {
+	raw := p.token()
+	var name []byte
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '#' && i+2 < len(raw) {
+			if b, err := strconv.ParseUint(raw[i+1:i+3], 16, 8); err == nil {
+				name = append(name, byte(b))
+				i += 2
+				continue
+			}
+		}
+		name = append(name, raw[i])
+	}
+	return Name(name)
+}
+
+// parseHexString reads a string written as hexadecimal digits
+func (p *parser) parseHexString() (String, error) {
+	p.pos++
+	var digits []byte
+	for p.pos < len(p.data) && p.data[p.pos] != '>' {
+		if !isWhitespace(p.data[p.pos]) {
+			digits = append(digits, p.data[p.pos])
+		}
+		p.pos++
+	}
+	if p.pos >= len(p.data) {
+		return nil, fmt.Errorf("unterminated hex string")
+	}
+	p.pos++
+
+	// A missing final digit is assumed to be 0
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	decoded := make(String, len(digits)/2)
+	for i := range decoded {
+		b, err := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex string: %w", err)
+		}
+		decoded[i] = byte(b)
+	}
+	return decoded, nil
+}
+
+// parseLiteralString reads a string in parentheses, handling nesting and escapes
+func (p *parser) parseLiteralString() (String, error) {
+	p.pos++
+	var s String
+	depth := 1
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		p.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s, nil
+			}
+		case '\\':
+			if p.pos >= len(p.data) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			c = p.data[p.pos]
+			p.pos++
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				// Line continuation
+				if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+					p.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if c >= '0' && c <= '7' {
+					value := int(c - '0')
+					for i := 0; i < 2 && p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '7'; i++ {
+						value = value*8 + int(p.data[p.pos]-'0')
+						p.pos++
+					}
+					c = byte(value)
+				}
+			}
+		}
+		s = append(s, c)
+	}
+	return nil, fmt.Errorf("unterminated string")
+}
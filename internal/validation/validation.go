@@ -0,0 +1,302 @@
+// Package validation checks investigators against the creation rules of Call of Cthulhu and
+// Pulp Cthulhu: skill and characteristic caps, skill point budgets, the Credit Rating range
+// of the occupation and the number of talents of the archetype.
+package validation
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"book-of-shadows/models"
+)
+
+// Rules a violation can break
+const (
+	RuleSkillCap           = "skill-cap"
+	RuleCharacteristicCap  = "characteristic-cap"
+	RuleCoreCharacteristic = "core-characteristic"
+	RulePointBudget        = "point-budget"
+	RuleCreditRating       = "credit-rating"
+	RuleTalents            = "talents"
+)
+
+// Violation is one rule an investigator breaks
+type Violation struct {
+	Rule    string `json:"rule"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Excess  int    `json:"-"` // How far past the rule the investigator went, to tell when it grows
+}
+
+// Key identifies the rule and field of a violation whatever the values involved
+func (v Violation) Key() string {
+	return v.Rule + ":" + v.Field
+}
+
+// Characteristic caps at creation, the Pulp core characteristic reaches higher
+const (
+	classicCap = 90
+	pulpCap    = 95
+	eduCap     = 99 // EDU improves with age up to 99
+)
+
+// cappedCharacteristics are held to the creation cap, EDU only to eduCap
+var cappedCharacteristics = []string{
+	models.AttrStrength, models.AttrConstitution, models.AttrSize, models.AttrDexterity,
+	models.AttrAppearance, models.AttrIntelligence, models.AttrPower,
+}
+
+// unbudgetedSkills are kept on the sheet but never bought with skill points
+var unbudgetedSkills = []string{"Dodge_Copy", "Cthulhu Mythos"}
+
+// Check lists the rules an investigator breaks. Caps, budgets and the Credit Rating range
+// are creation rules, they stop applying once the investigator went through a development phase.
+func Check(inv *models.Investigator) []Violation {
+	var violations []Violation
+	if len(inv.Development) == 0 {
+		violations = append(violations, checkCharacteristics(inv)...)
+		violations = append(violations, checkCore(inv)...)
+		violations = append(violations, checkSkills(inv)...)
+		violations = append(violations, checkBudgets(inv)...)
+		violations = append(violations, checkCreditRating(inv)...)
+	}
+	violations = append(violations, checkTalents(inv)...)
+	return violations
+}
+
+// RulesError refuses a change breaking a rule a strict investigator kept before it
+type RulesError struct {
+	Violations []Violation
+}
+
+func (e RulesError) Error() string {
+	return fmt.Sprintf("change breaks %d rule(s)", len(e.Violations))
+}
+
+// Enforce returns a RulesError when a strict investigator breaks a rule it did not break before
+// the change, or breaks one further, given by the violations checked before it. New investigators
+// break none before.
+func Enforce(inv *models.Investigator, before []Violation) error {
+	if !inv.StrictRules {
+		return nil
+	}
+	if introduced := Introduced(before, Check(inv)); len(introduced) > 0 {
+		return RulesError{Violations: introduced}
+	}
+	return nil
+}
+
+// Introduced returns the violations in after that were not already in before, or went further
+// past the rule than before
+func Introduced(before, after []Violation) []Violation {
+	known := make(map[string]int, len(before))
+	for _, v := range before {
+		known[v.Key()] = v.Excess
+	}
+	var introduced []Violation
+	for _, v := range after {
+		if excess, ok := known[v.Key()]; !ok || v.Excess > excess {
+			introduced = append(introduced, v)
+		}
+	}
+	return introduced
+}
+
+func checkCharacteristics(inv *models.Investigator) []Violation {
+	var violations []Violation
+	for _, key := range cappedCharacteristics {
+		attr, ok := inv.Attributes[key]
+		if !ok {
+			continue
+		}
+		// Only the archetype's core characteristic reaches the Pulp cap
+		limit := classicCap
+		if inv.IsPulp() && inv.Archetype != nil && slices.Contains(inv.Archetype.CoreCharacteristic, key) {
+			limit = pulpCap
+		}
+		if attr.Value > limit {
+			violations = append(violations, Violation{
+				Rule:    RuleCharacteristicCap,
+				Field:   key,
+				Message: fmt.Sprintf("%s %d is above the starting cap of %d", key, attr.Value, limit),
+				Excess:  attr.Value - limit,
+			})
+		}
+	}
+	if edu, ok := inv.Attributes[models.AttrEducation]; ok && edu.Value > eduCap {
+		violations = append(violations, Violation{
+			Rule:    RuleCharacteristicCap,
+			Field:   models.AttrEducation,
+			Message: fmt.Sprintf("%s %d is above %d", models.AttrEducation, edu.Value, eduCap),
+			Excess:  edu.Value - eduCap,
+		})
+	}
+	return violations
+}
+
+// checkCore checks the Pulp archetype's core characteristic reached CoreMinimum before aging
+// took its toll. Archetypes offering a choice of core characteristic need one of them to.
+func checkCore(inv *models.Investigator) []Violation {
+	if !inv.IsPulp() || inv.Archetype == nil || len(inv.Archetype.CoreCharacteristic) == 0 {
+		return nil
+	}
+	best := 0
+	for _, key := range inv.Archetype.CoreCharacteristic {
+		attr, ok := inv.Attributes[key]
+		if !ok {
+			return nil
+		}
+		value := attr.Value
+		if inv.Aging != nil {
+			value += inv.Aging.Deductions[key]
+			if key == models.AttrAppearance {
+				value += inv.Aging.APPLoss
+			}
+		}
+		best = max(best, value)
+	}
+	if best < models.CoreMinimum {
+		field := strings.Join(inv.Archetype.CoreCharacteristic, " or ")
+		return []Violation{{
+			Rule:    RuleCoreCharacteristic,
+			Field:   field,
+			Message: fmt.Sprintf("%s %d is below the %d of the core characteristic of a %s", field, best, models.CoreMinimum, inv.Archetype.Name),
+			Excess:  models.CoreMinimum - best,
+		}}
+	}
+	return nil
+}
+
+func checkSkills(inv *models.Investigator) []Violation {
+	limit := inv.SkillCap()
+	var violations []Violation
+	for _, name := range sortedSkills(inv) {
+		skill := inv.Skills[name]
+		switch {
+		case name == "Cthulhu Mythos" && skill.Value > skill.Default:
+			violations = append(violations, Violation{
+				Rule:    RuleSkillCap,
+				Field:   name,
+				Message: "Cthulhu Mythos cannot be bought at creation",
+				Excess:  skill.Value - skill.Default,
+			})
+		// Skills starting from a characteristic, such as Language (Own) from EDU, may start higher
+		case skill.Value > limit && skill.Value > skill.Default:
+			violations = append(violations, Violation{
+				Rule:    RuleSkillCap,
+				Field:   name,
+				Message: fmt.Sprintf("%s %d is above the starting cap of %d", name, skill.Value, limit),
+				Excess:  skill.Value - limit,
+			})
+		}
+	}
+	return violations
+}
+
+// poolNames name the pools in violations
+var poolNames = map[models.SkillPool]string{
+	models.PoolArchetype:  "Archetype",
+	models.PoolOccupation: "Occupation",
+	models.PoolPersonal:   "Personal Interest",
+}
+
+// checkBudgets checks each pool on its own from what it put into every skill: no more spent than
+// it holds, and only on skills it can raise. Points raised without a pool, typed on the sheet or
+// imported, must fit in what the pools have left.
+func checkBudgets(inv *models.Investigator) []Violation {
+	var violations []Violation
+	allocated := make(map[string]int)
+	left := 0
+	for _, pool := range models.SkillPools {
+		name := poolNames[pool]
+		if spent, total := inv.PoolSpent(pool), inv.PoolTotal(pool); spent > total {
+			violations = append(violations, Violation{
+				Rule:    RulePointBudget,
+				Field:   name,
+				Message: fmt.Sprintf("%d %s points spent out of %d", spent, name, total),
+				Excess:  spent - total,
+			})
+		} else {
+			left += total - spent
+		}
+
+		eligible := inv.PoolSkills(pool)
+		for _, skill := range slices.Sorted(maps.Keys(inv.SkillAllocations[pool])) {
+			points := inv.SkillAllocations[pool][skill]
+			allocated[skill] += points
+			if !slices.Contains(eligible, skill) {
+				violations = append(violations, Violation{
+					Rule:    RulePointBudget,
+					Field:   skill,
+					Message: fmt.Sprintf("%s points cannot raise %s", name, skill),
+					Excess:  points,
+				})
+			}
+		}
+	}
+
+	unallocated := 0
+	for _, name := range sortedSkills(inv) {
+		if skill := inv.Skills[name]; !slices.Contains(unbudgetedSkills, name) {
+			unallocated += max(0, skill.Value-skill.Default-allocated[name])
+		}
+	}
+	if unallocated > left {
+		violations = append(violations, Violation{
+			Rule:    RulePointBudget,
+			Field:   "Skills",
+			Message: fmt.Sprintf("%d skill points spent outside the pools, %d left in them", unallocated, left),
+			Excess:  unallocated - left,
+		})
+	}
+	return violations
+}
+
+func checkCreditRating(inv *models.Investigator) []Violation {
+	if inv.Occupation == nil {
+		return nil
+	}
+	cr, ok := inv.Skills["Credit Rating"]
+	if !ok {
+		return nil
+	}
+	limits := inv.Occupation.CreditRating
+	if cr.Value < limits.Min || cr.Value > limits.Max {
+		return []Violation{{
+			Rule:    RuleCreditRating,
+			Field:   "Credit Rating",
+			Message: fmt.Sprintf("Credit Rating %d is outside the %d-%d of a %s", cr.Value, limits.Min, limits.Max, inv.Occupation.Name),
+			Excess:  max(limits.Min-cr.Value, cr.Value-limits.Max),
+		}}
+	}
+	return nil
+}
+
+func checkTalents(inv *models.Investigator) []Violation {
+	allowed := 0
+	if inv.IsPulp() && inv.Archetype != nil {
+		allowed = inv.Archetype.AmountOfTalents
+	}
+	if len(inv.Talents) > allowed {
+		return []Violation{{
+			Rule:    RuleTalents,
+			Field:   "Talents",
+			Message: fmt.Sprintf("%d talents taken, %d allowed", len(inv.Talents), allowed),
+			Excess:  len(inv.Talents) - allowed,
+		}}
+	}
+	return nil
+}
+
+// sortedSkills returns the skill names in order so violations are listed the same way every time
+func sortedSkills(inv *models.Investigator) []string {
+	names := make([]string, 0, len(inv.Skills))
+	for name := range inv.Skills {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
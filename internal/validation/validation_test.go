@@ -0,0 +1,145 @@
+package validation
+
+import (
+	"testing"
+
+	"book-of-shadows/models"
+)
+
+func TestCheckGeneratedInvestigators(t *testing.T) {
+	for seed := int64(1); seed <= 50; seed++ {
+		for _, mode := range []models.GameMode{models.Classic, models.Pulp} {
+			inv := models.SeededInvestigator(mode, models.Twenties, seed)
+			if violations := Check(inv); len(violations) > 0 {
+				t.Errorf("expected seed %d in %s to be legal, got %v", seed, mode, violations)
+			}
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   models.GameMode
+		breaks func(inv *models.Investigator)
+		rule   string
+		field  string
+	}{
+		{"skill above the Classic cap", models.Classic, func(inv *models.Investigator) {
+			setSkill(inv, "Spot Hidden", 91)
+		}, RuleSkillCap, "Spot Hidden"},
+		{"skill above the Pulp cap", models.Pulp, func(inv *models.Investigator) {
+			setSkill(inv, "Spot Hidden", 150)
+		}, RuleSkillCap, "Spot Hidden"},
+		{"bought Cthulhu Mythos", models.Classic, func(inv *models.Investigator) {
+			setSkill(inv, "Cthulhu Mythos", 5)
+		}, RuleSkillCap, "Cthulhu Mythos"},
+		{"characteristic above the cap", models.Classic, func(inv *models.Investigator) {
+			setAttribute(inv, models.AttrPower, 95)
+		}, RuleCharacteristicCap, models.AttrPower},
+		{"overspent occupation pool", models.Classic, func(inv *models.Investigator) {
+			inv.OccupationPoints = inv.PoolSpent(models.PoolOccupation) - 10
+		}, RulePointBudget, "Occupation"},
+		{"occupation points on a skill outside the occupation", models.Classic, func(inv *models.Investigator) {
+			inv.SkillAllocations[models.PoolOccupation]["Cthulhu Mythos"] = 5
+		}, RulePointBudget, "Cthulhu Mythos"},
+		{"archetype points on a skill outside the archetype", models.Pulp, func(inv *models.Investigator) {
+			inv.SkillAllocations[models.PoolArchetype]["Credit Rating"] = 5
+		}, RulePointBudget, "Credit Rating"},
+		{"points spent outside the pools", models.Classic, func(inv *models.Investigator) {
+			skill := inv.Skills["Spot Hidden"]
+			skill.Value += inv.PoolRemaining(models.PoolOccupation) + inv.PoolRemaining(models.PoolPersonal) + 1
+			inv.Skills["Spot Hidden"] = skill
+		}, RulePointBudget, "Skills"},
+		{"Pulp core characteristic below 90", models.Pulp, func(inv *models.Investigator) {
+			adventurer := models.Archetypes["Adventurer"]
+			inv.Archetype = &adventurer
+			setAttribute(inv, models.AttrDexterity, 85)
+			setAttribute(inv, models.AttrAppearance, 85)
+			inv.Aging = nil
+		}, RuleCoreCharacteristic, "Dexterity or Appearance"},
+		{"Credit Rating outside the occupation", models.Classic, func(inv *models.Investigator) {
+			setSkill(inv, "Credit Rating", inv.Occupation.CreditRating.Max+1)
+		}, RuleCreditRating, "Credit Rating"},
+		{"too many talents", models.Pulp, func(inv *models.Investigator) {
+			for len(inv.Talents) <= inv.Archetype.AmountOfTalents {
+				inv.Talents = append(inv.Talents, models.Talent{Name: "Extra"})
+			}
+		}, RuleTalents, "Talents"},
+		{"talents for a Classic investigator", models.Classic, func(inv *models.Investigator) {
+			inv.Talents = []models.Talent{{Name: "Keen Vision"}}
+		}, RuleTalents, "Talents"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv := models.SeededInvestigator(tt.mode, models.Modern, 7)
+			tt.breaks(inv)
+
+			violations := Check(inv)
+			for _, v := range violations {
+				if v.Rule == tt.rule && v.Field == tt.field {
+					return
+				}
+			}
+			t.Errorf("expected a %s violation on %s, got %v", tt.rule, tt.field, violations)
+		})
+	}
+
+	t.Run("allows the Pulp core characteristic up to 95", func(t *testing.T) {
+		inv := models.SeededInvestigator(models.Pulp, models.Modern, 7)
+		setAttribute(inv, inv.Archetype.CoreCharacteristic[0], 95)
+		if violations := Check(inv); len(violations) > 0 {
+			t.Errorf("expected no violations, got %v", violations)
+		}
+	})
+
+	t.Run("counts the Pulp core characteristic before aging", func(t *testing.T) {
+		inv := models.SeededInvestigator(models.Pulp, models.Modern, 7)
+		core := inv.Archetype.CoreCharacteristic
+		for _, key := range core {
+			setAttribute(inv, key, 80)
+		}
+		inv.Aging = &models.AgingReport{Age: 45, Deductions: map[string]int{core[0]: 10}}
+		if violations := Check(inv); len(violations) > 0 {
+			t.Errorf("expected no violations, got %v", violations)
+		}
+	})
+
+	t.Run("stops applying creation rules after development", func(t *testing.T) {
+		inv := models.SeededInvestigator(models.Classic, models.Modern, 7)
+		setSkill(inv, "Spot Hidden", 97)
+		inv.Development = []models.DevelopmentReport{{}}
+		if violations := Check(inv); len(violations) > 0 {
+			t.Errorf("expected no violations, got %v", violations)
+		}
+	})
+}
+
+func TestIntroduced(t *testing.T) {
+	before := []Violation{
+		{Rule: RuleCreditRating, Field: "Credit Rating", Message: "Credit Rating 0 is outside", Excess: 10},
+		{Rule: RuleTalents, Field: "Talents", Message: "3 talents taken, 2 allowed", Excess: 1},
+	}
+	after := []Violation{
+		{Rule: RuleCreditRating, Field: "Credit Rating", Message: "Credit Rating 5 is outside", Excess: 5},
+		{Rule: RuleTalents, Field: "Talents", Message: "4 talents taken, 2 allowed", Excess: 2},
+		{Rule: RuleSkillCap, Field: "Spot Hidden", Excess: 1},
+	}
+	introduced := Introduced(before, after)
+	if len(introduced) != 2 || introduced[0].Field != "Talents" || introduced[1].Field != "Spot Hidden" {
+		t.Errorf("expected the worse talents and the new skill cap violation, got %v", introduced)
+	}
+}
+
+func setSkill(inv *models.Investigator, name string, value int) {
+	skill := inv.Skills[name]
+	skill.Name = name
+	skill.Value = value
+	inv.Skills[name] = skill
+}
+
+func setAttribute(inv *models.Investigator, key string, value int) {
+	attr := inv.Attributes[key]
+	attr.Value = value
+	inv.Attributes[key] = attr
+}
@@ -30,18 +30,21 @@ type Server struct {
 func NewServer() (*Server, error) {
 	// Load configuration
 	cfg := config.New()
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
 
 	// Setup logger
 	logger := log.New(os.Stdout, "[book-of-shadows] ", log.LstdFlags|log.Lshortfile)
 
 	// Create store
-	store, err := storage.NewAppStore(cfg)
+	store, err := storage.NewAppStore(cfg, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create store: %w", err)
 	}
 
 	// Create handlers with dependencies
-	h := handlers.New(store, logger)
+	h := handlers.New(store, &cfg.Auth, logger)
 
 	// Create wizard handler with dependencies
 	wizardHandler := wizard.New(store, logger)
@@ -68,6 +71,14 @@ func (s *Server) setupRoutes() *RadixTree {
 	router.GET("/", s.handlers.Home)
 	router.GET("api/generate/", s.handlers.Generate)
 
+	// Account routes
+	router.GET("login", s.handlers.LoginPage)
+	router.GET("register", s.handlers.RegisterPage)
+	router.POST("api/auth/register", s.handlers.Register)
+	router.POST("api/auth/login", s.handlers.Login)
+	router.POST("api/auth/logout", s.handlers.Logout)
+	router.GET("api/auth/me", s.handlers.CurrentUser)
+
 	// Investigator CRUD operations
 	router.GET("api/investigator", s.handlers.ListInvestigators)
 	router.POST("api/investigator/", s.handlers.CreateInvestigator)
@@ -75,13 +86,40 @@ func (s *Server) setupRoutes() *RadixTree {
 	router.PUT("api/investigator/{:id}", s.handlers.UpdateInvestigator)
 	router.DELETE("api/investigator/{:id}", s.handlers.DeleteInvestigator)
 
+	// Revision history
+	router.GET("api/investigator/{:id}/history", s.handlers.ListRevisions)
+	router.POST("api/investigator/{:id}/history/{:revision}/revert", s.handlers.RevertRevision)
+
 	// Export/Import operations
 	router.POST("api/investigator/PDF/{:id}", s.handlers.ExportPDF)
 	router.GET("api/investigator/list/export", s.handlers.ExportInvestigatorsList)
 	router.POST("api/investigator/list/import/", s.handlers.ImportInvestigatorsList)
+	router.POST("api/investigator/import/pdf", s.handlers.ImportPDF)
 
 	// Other routes
 	router.GET("api/archetype/{:name}/occupations/", s.handlers.GetArchetypeOccupations)
+	router.GET("api/occupations/", s.handlers.ListOccupations)
+	router.POST("api/roll", s.handlers.Roll)
+	router.POST("api/investigator/{:id}/check", s.handlers.Check)
+	router.POST("api/investigator/{:id}/develop", s.handlers.Develop)
+	router.POST("api/investigator/{:id}/sanity", s.handlers.LoseSanity)
+	router.POST("api/investigator/{:id}/madness", s.handlers.RollBout)
+	router.POST("api/investigator/{:id}/damage", s.handlers.TakeDamage)
+	router.POST("api/investigator/{:id}/heal", s.handlers.Heal)
+	router.POST("api/investigator/{:id}/aging", s.handlers.ApplyAging)
+	router.POST("api/investigator/{:id}/generation", s.handlers.SetGenerationMethod)
+	router.POST("api/investigator/{:id}/characteristics", s.handlers.AssignCharacteristics)
+	router.GET("api/investigator/{:id}/rules", s.handlers.CheckRules)
+	router.GET("api/investigator/{:id}/skill-points", s.handlers.GetSkillPools)
+	router.POST("api/investigator/{:id}/skill-points", s.handlers.AllocateSkillPoints)
+	router.POST("api/investigator/{:id}/complete", s.handlers.CompleteCreation)
+	router.GET("api/weapons/", s.handlers.ListWeapons)
+	router.POST("api/investigator/{:id}/weapons", s.handlers.AddWeapon)
+	router.PUT("api/investigator/{:id}/weapons/{:weapon}", s.handlers.UpdateWeapon)
+	router.DELETE("api/investigator/{:id}/weapons/{:weapon}", s.handlers.DeleteWeapon)
+	router.POST("api/investigator/{:id}/gear", s.handlers.AddGear)
+	router.PUT("api/investigator/{:id}/gear/{:item}", s.handlers.UpdateGear)
+	router.DELETE("api/investigator/{:id}/gear/{:item}", s.handlers.DeleteGear)
 	router.POST("api/report-issue", s.handlers.ReportIssue)
 
 	// Wizard routes
@@ -94,6 +132,17 @@ func (s *Server) setupRoutes() *RadixTree {
 	router.GET("keeper", s.handlers.KeeperDashboard)
 	router.GET("keeper/chase", s.handlers.ChaseTracker)
 	router.GET("keeper/combat", s.handlers.CombatTracker)
+	router.GET("keeper/campaigns", s.handlers.CampaignsPage)
+	router.GET("keeper/campaigns/{:id}", s.handlers.PartyOverview)
+
+	// Campaign routes
+	router.POST("api/campaign", s.handlers.CreateCampaign)
+	router.POST("api/campaign/join", s.handlers.JoinCampaign)
+	router.GET("api/campaign/{:id}/party", s.handlers.GetCampaignParty)
+	router.DELETE("api/campaign/{:id}", s.handlers.DeleteCampaign)
+	router.DELETE("api/campaign/{:id}/member/{:investigator}", s.handlers.RemoveCampaignMember)
+	router.POST("api/campaign/{:id}/member/{:investigator}/madness", s.handlers.RollMemberBout)
+	router.POST("api/campaign/{:id}/member/{:investigator}/strict", s.handlers.SetMemberStrictRules)
 
 	return router
 }
@@ -109,7 +158,9 @@ func (s *Server) Run() error {
 		middleware.Logger(s.logger),
 		middleware.SecurityHeaders,
 		middleware.RequestID,
-		middleware.ValidateAPIRequest(1<<20), // 1MB max body size
+		middleware.Session(s.config, s.store),
+		// 1MB max body size, 8MB for character sheet uploads
+		middleware.ValidateAPIRequest(1<<20, middleware.Upload{Path: "/api/investigator/import/pdf", MaxBytes: 8 << 20}),
 	)
 
 	// Create HTTP server with timeouts
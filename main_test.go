@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"log"
@@ -9,6 +10,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"book-of-shadows/internal/config"
 	"book-of-shadows/internal/errors"
 	"book-of-shadows/internal/handlers"
 	"book-of-shadows/models"
@@ -54,14 +56,14 @@ func (m *MockAppStore) DeleteExpiredExports() error {
 }
 
 // InvestigatorStore methods
-func (m *MockAppStore) SaveInvestigator(w http.ResponseWriter, inv *models.Investigator) (string, error) {
+func (m *MockAppStore) SaveInvestigator(ctx context.Context, ownerID string, inv *models.Investigator) (string, error) {
 	id := "test-inv-id"
 	inv.ID = id
 	m.investigators[id] = inv
 	return id, nil
 }
 
-func (m *MockAppStore) GetInvestigator(r *http.Request, id string) (*models.Investigator, error) {
+func (m *MockAppStore) GetInvestigator(ctx context.Context, ownerID, id string) (*models.Investigator, error) {
 	inv, ok := m.investigators[id]
 	if !ok {
 		return nil, errors.ErrNotFound
@@ -69,7 +71,7 @@ func (m *MockAppStore) GetInvestigator(r *http.Request, id string) (*models.Inve
 	return inv, nil
 }
 
-func (m *MockAppStore) UpdateInvestigator(w http.ResponseWriter, id string, inv *models.Investigator) error {
+func (m *MockAppStore) UpdateInvestigator(ctx context.Context, ownerID, id string, inv *models.Investigator) error {
 	if _, ok := m.investigators[id]; !ok {
 		return errors.ErrNotFound
 	}
@@ -77,7 +79,7 @@ func (m *MockAppStore) UpdateInvestigator(w http.ResponseWriter, id string, inv
 	return nil
 }
 
-func (m *MockAppStore) DeleteInvestigator(w http.ResponseWriter, id string) error {
+func (m *MockAppStore) DeleteInvestigator(ctx context.Context, ownerID, id string) error {
 	if _, ok := m.investigators[id]; !ok {
 		return errors.ErrNotFound
 	}
@@ -85,15 +87,102 @@ func (m *MockAppStore) DeleteInvestigator(w http.ResponseWriter, id string) erro
 	return nil
 }
 
-func (m *MockAppStore) ListInvestigators(r *http.Request) (map[string]*models.Investigator, error) {
+func (m *MockAppStore) ListInvestigators(ctx context.Context, ownerID string) (map[string]*models.Investigator, error) {
 	return m.investigators, nil
 }
 
-func (m *MockAppStore) ExportInvestigatorsList(r *http.Request) (string, error) {
+func (m *MockAppStore) ExportInvestigatorsList(ctx context.Context, ownerID string) (string, error) {
 	return "test-export-code", nil
 }
 
-func (m *MockAppStore) ImportInvestigatorsList(w http.ResponseWriter, uuid string) error {
+func (m *MockAppStore) ImportInvestigatorsList(ctx context.Context, ownerID, uuid string) error {
+	return nil
+}
+
+func (m *MockAppStore) ClaimInvestigators(ctx context.Context, fromOwnerID, toUserID string) error {
+	return nil
+}
+
+// UserStore methods
+func (m *MockAppStore) CreateUser(ctx context.Context, username, password string) (*models.User, error) {
+	return &models.User{ID: "test-user-id", Username: username}, nil
+}
+
+func (m *MockAppStore) AuthenticateUser(ctx context.Context, username, password string) (*models.User, error) {
+	return nil, errors.ErrInvalidCredentials
+}
+
+func (m *MockAppStore) CreateSession(ctx context.Context, userID string) (string, error) {
+	return "test-session-token", nil
+}
+
+func (m *MockAppStore) GetSessionUser(ctx context.Context, token string) (*models.User, error) {
+	return nil, errors.ErrNotFound
+}
+
+func (m *MockAppStore) DeleteSession(ctx context.Context, token string) error {
+	return nil
+}
+
+// RevisionStore methods
+func (m *MockAppStore) SaveRevision(ctx context.Context, ownerID string, rev *models.Revision) error {
+	return nil
+}
+
+func (m *MockAppStore) GetRevision(ctx context.Context, ownerID, investigatorID string, revisionID int64) (*models.Revision, error) {
+	return nil, errors.ErrNotFound
+}
+
+func (m *MockAppStore) ListRevisions(ctx context.Context, ownerID, investigatorID string) ([]*models.Revision, error) {
+	return []*models.Revision{}, nil
+}
+
+func (m *MockAppStore) DeleteRevisions(ctx context.Context, ownerID, investigatorID string) error {
+	return nil
+}
+
+// CampaignStore methods
+func (m *MockAppStore) CreateCampaign(ctx context.Context, keeperID string, campaign *models.Campaign) (string, error) {
+	return "", nil
+}
+
+func (m *MockAppStore) GetCampaign(ctx context.Context, keeperID, id string) (*models.Campaign, error) {
+	return nil, errors.ErrNotFound
+}
+
+func (m *MockAppStore) ListCampaigns(ctx context.Context, keeperID string) ([]*models.Campaign, error) {
+	return []*models.Campaign{}, nil
+}
+
+func (m *MockAppStore) DeleteCampaign(ctx context.Context, keeperID, id string) error {
+	return errors.ErrNotFound
+}
+
+func (m *MockAppStore) JoinCampaign(ctx context.Context, joinCode, ownerID string, inv *models.Investigator) (*models.Campaign, error) {
+	return nil, errors.ErrNotFound
+}
+
+func (m *MockAppStore) RemoveCampaignMember(ctx context.Context, keeperID, campaignID, investigatorID string) error {
+	return errors.ErrNotFound
+}
+
+func (m *MockAppStore) GetCampaignMember(ctx context.Context, keeperID, campaignID, investigatorID string) (*models.CampaignMember, error) {
+	return nil, errors.ErrNotFound
+}
+
+func (m *MockAppStore) GetMemberInvestigator(ctx context.Context, member *models.CampaignMember) (*models.Investigator, error) {
+	return nil, errors.ErrNotFound
+}
+
+func (m *MockAppStore) UpdateMemberInvestigator(ctx context.Context, member *models.CampaignMember, inv *models.Investigator) error {
+	return errors.ErrNotFound
+}
+
+func (m *MockAppStore) SyncCampaignMember(ctx context.Context, ownerID, investigatorID string, inv *models.Investigator) error {
+	return nil
+}
+
+func (m *MockAppStore) LeaveCampaigns(ctx context.Context, ownerID, investigatorID string) error {
 	return nil
 }
 
@@ -106,7 +195,7 @@ func (m *MockAppStore) Close() error {
 func newTestServer() *TestServer {
 	store := NewMockAppStore()
 	logger := log.New(io.Discard, "", 0)
-	h := handlers.New(store, logger)
+	h := handlers.New(store, &config.New().Auth, logger)
 
 	router := NewRouter()
 
@@ -162,7 +251,7 @@ func TestIntegrationCreateAndGetInvestigator(t *testing.T) {
 
 	t.Run("get investigator returns 200", func(t *testing.T) {
 		// Pre-populate the store
-		inv := models.RandomInvestigator(models.Pulp)
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
 		inv.ID = "existing-id"
 		ts.store.investigators["existing-id"] = inv
 
@@ -193,7 +282,7 @@ func TestIntegrationUpdateInvestigator(t *testing.T) {
 
 	t.Run("update investigator returns 200", func(t *testing.T) {
 		// Pre-populate the store
-		inv := models.RandomInvestigator(models.Pulp)
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
 		inv.ID = "test-update-id"
 		ts.store.investigators["test-update-id"] = inv
 
@@ -240,7 +329,7 @@ func TestIntegrationDeleteInvestigator(t *testing.T) {
 
 	t.Run("delete investigator returns 200", func(t *testing.T) {
 		// Pre-populate the store
-		inv := models.RandomInvestigator(models.Pulp)
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
 		inv.ID = "test-delete-id"
 		ts.store.investigators["test-delete-id"] = inv
 
@@ -287,12 +376,12 @@ func TestIntegrationListInvestigators(t *testing.T) {
 
 	t.Run("list with investigators returns data", func(t *testing.T) {
 		// Add some investigators
-		inv1 := models.RandomInvestigator(models.Pulp)
+		inv1 := models.RandomInvestigator(models.Pulp, models.Modern)
 		inv1.ID = "inv-1"
 		inv1.Name = "Investigator 1"
 		ts.store.investigators["inv-1"] = inv1
 
-		inv2 := models.RandomInvestigator(models.Pulp)
+		inv2 := models.RandomInvestigator(models.Pulp, models.Modern)
 		inv2.ID = "inv-2"
 		inv2.Name = "Investigator 2"
 		ts.store.investigators["inv-2"] = inv2
@@ -0,0 +1,237 @@
+package models
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+
+	"book-of-shadows/internal/dice"
+)
+
+// AgeBracket holds the rulebook modifiers for investigators of an age range
+type AgeBracket struct {
+	MinAge      int      `json:"minAge"`
+	MaxAge      int      `json:"maxAge"`
+	EDUChecks   int      `json:"eduChecks"`   // Improvement checks made for EDU
+	EDULoss     int      `json:"eduLoss"`     // Taken from EDU, only for the youngest
+	Deduction   int      `json:"deduction"`   // Points taken from the characteristics in DeductFrom
+	DeductFrom  []string `json:"deductFrom"`  // Attribute keys the deduction is split between
+	APPLoss     int      `json:"appLoss"`     // Taken from APP
+	LuckRolls   int      `json:"luckRolls"`   // Luck is rolled this many times keeping the best
+	MovePenalty int      `json:"movePenalty"` // Taken from MOV
+}
+
+var physicalCharacteristics = []string{AttrStrength, AttrConstitution, AttrDexterity}
+
+// AgeBrackets are the age modifiers of the Investigator Handbook, youngest first
+var AgeBrackets = []AgeBracket{
+	{MinAge: 15, MaxAge: 19, EDULoss: 5, Deduction: 5, DeductFrom: []string{AttrStrength, AttrSize}, LuckRolls: 2},
+	{MinAge: 20, MaxAge: 39, EDUChecks: 1, LuckRolls: 1},
+	{MinAge: 40, MaxAge: 49, EDUChecks: 2, Deduction: 5, DeductFrom: physicalCharacteristics, APPLoss: 5, LuckRolls: 1, MovePenalty: 1},
+	{MinAge: 50, MaxAge: 59, EDUChecks: 3, Deduction: 10, DeductFrom: physicalCharacteristics, APPLoss: 10, LuckRolls: 1, MovePenalty: 2},
+	{MinAge: 60, MaxAge: 69, EDUChecks: 4, Deduction: 20, DeductFrom: physicalCharacteristics, APPLoss: 15, LuckRolls: 1, MovePenalty: 3},
+	{MinAge: 70, MaxAge: 79, EDUChecks: 4, Deduction: 40, DeductFrom: physicalCharacteristics, APPLoss: 20, LuckRolls: 1, MovePenalty: 4},
+	{MinAge: 80, MaxAge: 89, EDUChecks: 4, Deduction: 80, DeductFrom: physicalCharacteristics, APPLoss: 25, LuckRolls: 1, MovePenalty: 5},
+}
+
+// AgeBracketFor returns the bracket of an age. Ages outside the table use the nearest one.
+func AgeBracketFor(age int) AgeBracket {
+	for _, bracket := range AgeBrackets {
+		if age <= bracket.MaxAge {
+			return bracket
+		}
+	}
+	return AgeBrackets[len(AgeBrackets)-1]
+}
+
+// RandomAge picks an age anywhere in the age table, from the youngest bracket to the oldest
+func RandomAge(r *rand.Rand) int {
+	youngest, oldest := AgeBrackets[0].MinAge, AgeBrackets[len(AgeBrackets)-1].MaxAge
+	return youngest + r.Intn(oldest-youngest+1)
+}
+
+// EDUImprovement is one improvement check made for EDU with age
+type EDUImprovement struct {
+	Roll     int  `json:"roll"` // The 1D100 rolled against EDU
+	Improved bool `json:"improved"`
+	Gain     int  `json:"gain"` // The 1D10 added to EDU
+}
+
+// AgingReport records the age modifiers applied to an investigator
+type AgingReport struct {
+	Age             int              `json:"age"`
+	EDUImprovements []EDUImprovement `json:"eduImprovements"`
+	EDULoss         int              `json:"eduLoss,omitempty"`
+	Deductions      map[string]int   `json:"deductions,omitempty"` // Points taken from each characteristic
+	APPLoss         int              `json:"appLoss,omitempty"`
+	LuckRolls       []int            `json:"luckRolls,omitempty"` // Every Luck roll when the best of several is kept
+	MovePenalty     int              `json:"movePenalty,omitempty"`
+}
+
+var agingLuckRoll = dice.MustParse("3D6*5")
+
+// ApplyAging applies the modifiers of the investigator's age bracket to their characteristics.
+// deductions says how many points to take from each characteristic of the bracket, and
+// must add up to its deduction. Without them the points are spread evenly. Characteristics
+// never drop below 1 and EDU never rises above 99. Aging is applied once, at creation.
+func (i *Investigator) ApplyAging(r *rand.Rand, deductions map[string]int) (AgingReport, error) {
+	if i.Aging != nil {
+		return AgingReport{}, fmt.Errorf("aging was already applied at age %d", i.Aging.Age)
+	}
+	bracket := AgeBracketFor(i.Age)
+	report := AgingReport{Age: i.Age, EDUImprovements: []EDUImprovement{}, MovePenalty: bracket.MovePenalty}
+
+	split, err := i.splitDeduction(bracket, deductions)
+	if err != nil {
+		return AgingReport{}, err
+	}
+
+	edu := i.Attributes[AttrEducation]
+	for n := 0; n < bracket.EDUChecks; n++ {
+		check := EDUImprovement{Roll: r.Intn(100) + 1}
+		if check.Roll > edu.Value {
+			check.Improved = true
+			check.Gain = min(improvementRoll.Total(r), 99-edu.Value)
+			edu.Value += check.Gain
+		}
+		report.EDUImprovements = append(report.EDUImprovements, check)
+	}
+	if bracket.EDULoss > 0 {
+		report.EDULoss = min(bracket.EDULoss, edu.Value-1)
+		edu.Value -= report.EDULoss
+	}
+	i.Attributes[AttrEducation] = edu
+
+	if len(split) > 0 {
+		report.Deductions = split
+		for key, points := range split {
+			attr := i.Attributes[key]
+			attr.Value -= points
+			i.Attributes[key] = attr
+		}
+	}
+
+	if bracket.APPLoss > 0 {
+		app := i.Attributes[AttrAppearance]
+		report.APPLoss = min(bracket.APPLoss, app.Value-1)
+		app.Value -= report.APPLoss
+		i.Attributes[AttrAppearance] = app
+	}
+
+	if bracket.LuckRolls > 1 {
+		luck := i.Attributes[AttrLuck]
+		report.LuckRolls = []int{luck.Value}
+		for n := 1; n < bracket.LuckRolls; n++ {
+			roll := agingLuckRoll.Total(r)
+			report.LuckRolls = append(report.LuckRolls, roll)
+			luck.Value = max(luck.Value, roll)
+		}
+		i.Attributes[AttrLuck] = luck
+	}
+
+	i.Aging = &report
+	return report, nil
+}
+
+// splitDeduction checks the deductions asked for against the bracket, or spreads the
+// bracket's deduction evenly when none are given
+func (i *Investigator) splitDeduction(bracket AgeBracket, deductions map[string]int) (map[string]int, error) {
+	if bracket.Deduction == 0 {
+		if len(deductions) > 0 {
+			return nil, fmt.Errorf("investigators aged %d-%d take no deductions", bracket.MinAge, bracket.MaxAge)
+		}
+		return nil, nil
+	}
+
+	split := make(map[string]int)
+	if len(deductions) == 0 {
+		// Take a point at a time from each characteristic in turn that can spare one
+		for remaining := bracket.Deduction; remaining > 0; {
+			taken := false
+			for _, key := range bracket.DeductFrom {
+				if remaining > 0 && i.Attributes[key].Value-split[key] > 1 {
+					split[key]++
+					remaining--
+					taken = true
+				}
+			}
+			if !taken {
+				break
+			}
+		}
+		return split, nil
+	}
+
+	total := 0
+	for name, points := range deductions {
+		key, ok := i.deductibleKey(bracket, name)
+		if !ok {
+			return nil, fmt.Errorf("%s cannot take an age deduction, use %s", name, strings.Join(bracket.DeductFrom, ", "))
+		}
+		if points < 0 {
+			return nil, fmt.Errorf("deduction from %s cannot be negative", name)
+		}
+		if i.Attributes[key].Value-split[key]-points < 1 {
+			return nil, fmt.Errorf("deducting %d from %s would leave it below 1", points, name)
+		}
+		split[key] += points
+		total += points
+	}
+	if total != bracket.Deduction {
+		return nil, fmt.Errorf("deductions add up to %d, investigators aged %d-%d take %d", total, bracket.MinAge, bracket.MaxAge, bracket.Deduction)
+	}
+	for key, points := range split {
+		if points == 0 {
+			delete(split, key)
+		}
+	}
+	return split, nil
+}
+
+// deductibleKey finds the characteristic of the bracket a deduction names by key or abbreviation
+func (i *Investigator) deductibleKey(bracket AgeBracket, name string) (string, bool) {
+	for _, key := range bracket.DeductFrom {
+		if strings.EqualFold(key, name) || strings.EqualFold(i.Attributes[key].Name, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// Summary describes the changes aging made, such as "EDU +7, STR -3, APP -5, MOV -1"
+func (r AgingReport) Summary(attributes map[string]Attribute) string {
+	var changes []string
+	gained := 0
+	for _, check := range r.EDUImprovements {
+		gained += check.Gain
+	}
+	if gained-r.EDULoss != 0 {
+		changes = append(changes, fmt.Sprintf("EDU %+d", gained-r.EDULoss))
+	}
+	keys := make([]string, 0, len(r.Deductions))
+	for key := range r.Deductions {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		name := key
+		if attr, ok := attributes[key]; ok && attr.Name != "" {
+			name = attr.Name
+		}
+		changes = append(changes, fmt.Sprintf("%s -%d", name, r.Deductions[key]))
+	}
+	if r.APPLoss > 0 {
+		changes = append(changes, fmt.Sprintf("APP -%d", r.APPLoss))
+	}
+	if len(r.LuckRolls) > 1 {
+		changes = append(changes, fmt.Sprintf("Luck best of %v", r.LuckRolls))
+	}
+	if r.MovePenalty > 0 {
+		changes = append(changes, fmt.Sprintf("MOV -%d", r.MovePenalty))
+	}
+	if len(changes) == 0 {
+		return "No changes"
+	}
+	return strings.Join(changes, ", ")
+}
@@ -74,8 +74,8 @@ func (a *Archetype) GetDescription() string {
 	return desc.String()
 }
 
-func PickRandomArchetype() *Archetype {
-	archetypeName := ArchetypesList[rand.Intn(len(ArchetypesList))]
+func PickRandomArchetype(r *rand.Rand) *Archetype {
+	archetypeName := ArchetypesList[r.Intn(len(ArchetypesList))]
 	archetype := Archetypes[archetypeName]
 	return &archetype
 }
@@ -2,6 +2,9 @@ package models
 
 import (
 	"fmt"
+	"math/rand"
+
+	"book-of-shadows/internal/dice"
 )
 
 const (
@@ -30,22 +33,26 @@ func (a *Attribute) String() string {
 	return fmt.Sprintf("%v: %v", a.Name, a.Value)
 }
 
-func (a *Attribute) Initialize(isCore bool) {
-	rolled := 0
-	if a.Name == "SIZ" || a.Name == "INT" || a.Name == "EDU" {
-		if isCore {
-			rolled = coreRoll()
-		} else {
-			rolled = (rollD6() + rollD6() + 6) * 5
-		}
-
-	} else {
-
-		if isCore {
-			rolled = coreRoll()
-		} else {
-			rolled = (rollD6() + rollD6() + rollD6()) * 5
-		}
+// Characteristic rolls from the rulebook, Pulp core characteristics roll higher
+var (
+	standardRoll = dice.MustParse("3D6*5")
+	educatedRoll = dice.MustParse("(2D6+6)*5")
+	coreRoll     = dice.MustParse("(1D6+13)*5")
+)
+
+// CoreMinimum is the least a Pulp archetype's core characteristic starts at
+const CoreMinimum = 90
+
+func (a *Attribute) Initialize(r *rand.Rand, isCore bool) {
+	roll := standardRoll
+	if isCore {
+		roll = coreRoll
+	} else if a.Name == "SIZ" || a.Name == "INT" || a.Name == "EDU" {
+		roll = educatedRoll
+	}
+	rolled := roll.Total(r)
+	if isCore {
+		rolled = max(rolled, CoreMinimum)
 	}
 	a.Value = rolled
 	a.StartingValue = rolled
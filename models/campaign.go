@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// Campaign groups the investigators a keeper runs together
+type Campaign struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	Era        Era              `json:"era"`
+	GameMode   GameMode         `json:"game_mode"`
+	KeeperID   string           `json:"-"`
+	KeeperName string           `json:"keeper_name,omitempty"`
+	JoinCode   string           `json:"join_code"`
+	Members    []CampaignMember `json:"members"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+// CampaignMember is an investigator that joined a campaign.
+// Investigator holds the latest copy of the sheet, kept in sync as the player edits it.
+type CampaignMember struct {
+	InvestigatorID string        `json:"investigator_id"`
+	OwnerID        string        `json:"-"` // The player the investigator belongs to
+	Investigator   *Investigator `json:"-"`
+	JoinedAt       time.Time     `json:"joined_at"`
+}
+
+// PartyStatus summarises the resources and conditions of a campaign member
+type PartyStatus struct {
+	InvestigatorID   string `json:"investigator_id"`
+	Name             string `json:"name"`
+	Occupation       string `json:"occupation"`
+	HP               int    `json:"hp"`
+	MaxHP            int    `json:"max_hp"`
+	Sanity           int    `json:"sanity"`
+	MagicPoints      int    `json:"magic_points"`
+	MaxMagicPoints   int    `json:"max_magic_points"`
+	Luck             int    `json:"luck"`
+	TemporaryInsane  bool   `json:"temporary_insane"`
+	IndefiniteInsane bool   `json:"indefinite_insane"`
+	MajorWound       bool   `json:"major_wound"`
+	Unconscious      bool   `json:"unconscious"`
+	Dying            bool   `json:"dying"`
+	StrictRules      bool   `json:"strict_rules"`
+}
+
+// PartyStatus returns the at-a-glance status of the member's investigator
+func (m CampaignMember) PartyStatus() PartyStatus {
+	status := PartyStatus{InvestigatorID: m.InvestigatorID}
+	inv := m.Investigator
+	if inv == nil {
+		return status
+	}
+
+	status.Name = inv.Name
+	if inv.Occupation != nil {
+		status.Occupation = inv.Occupation.Name
+	}
+	status.HP = inv.Attributes[AttrHitPoints].Value
+	status.MaxHP = inv.Attributes[AttrHitPoints].MaxValue
+	status.Sanity = inv.Attributes[AttrSanity].Value
+	status.MagicPoints = inv.Attributes[AttrMagicPoints].Value
+	status.MaxMagicPoints = inv.Attributes[AttrMagicPoints].MaxValue
+	status.Luck = inv.Attributes[AttrLuck].Value
+	status.TemporaryInsane = inv.TemporaryInsane
+	status.IndefiniteInsane = inv.IndefiniteInsane
+	status.MajorWound = inv.MajorWound
+	status.Unconscious = inv.Unconscious
+	status.Dying = inv.Dying
+	status.StrictRules = inv.StrictRules
+
+	return status
+}
+
+// Party returns the status of every member of the campaign
+func (c *Campaign) Party() []PartyStatus {
+	party := make([]PartyStatus, 0, len(c.Members))
+	for _, member := range c.Members {
+		party = append(party, member.PartyStatus())
+	}
+	return party
+}
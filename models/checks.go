@@ -0,0 +1,220 @@
+package models
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"book-of-shadows/internal/dice"
+)
+
+// Difficulty is how hard a check is, deciding the fraction of the value that must be rolled
+type Difficulty int
+
+const (
+	RegularDifficulty Difficulty = iota
+	HardDifficulty
+	ExtremeDifficulty
+)
+
+func (d Difficulty) String() string {
+	switch d {
+	case RegularDifficulty:
+		return "Regular"
+	case HardDifficulty:
+		return "Hard"
+	case ExtremeDifficulty:
+		return "Extreme"
+	default:
+		return fmt.Sprintf("Difficulty(%d)", int(d))
+	}
+}
+
+// ParseDifficulty returns the difficulty matching a name such as "hard", empty is Regular
+func ParseDifficulty(name string) (Difficulty, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "regular":
+		return RegularDifficulty, nil
+	case "hard":
+		return HardDifficulty, nil
+	case "extreme":
+		return ExtremeDifficulty, nil
+	default:
+		return 0, fmt.Errorf("unknown difficulty %q", name)
+	}
+}
+
+// target returns the number a check of this difficulty must roll equal or under
+func (d Difficulty) target(value int) int {
+	switch d {
+	case HardDifficulty:
+		return value / 2
+	case ExtremeDifficulty:
+		return value / 5
+	default:
+		return value
+	}
+}
+
+// SuccessLevel is the outcome of a percentile roll against a value
+type SuccessLevel int
+
+const (
+	Fumble SuccessLevel = iota
+	Failure
+	RegularSuccess
+	HardSuccess
+	ExtremeSuccess
+	CriticalSuccess
+)
+
+func (l SuccessLevel) String() string {
+	switch l {
+	case Fumble:
+		return "Fumble"
+	case Failure:
+		return "Failure"
+	case RegularSuccess:
+		return "Regular Success"
+	case HardSuccess:
+		return "Hard Success"
+	case ExtremeSuccess:
+		return "Extreme Success"
+	case CriticalSuccess:
+		return "Critical Success"
+	default:
+		return fmt.Sprintf("SuccessLevel(%d)", int(l))
+	}
+}
+
+// MarshalText writes the level by name so API clients need not know the numbers
+func (l SuccessLevel) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// MarshalText writes the difficulty by name
+func (d Difficulty) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText reads a difficulty by name
+func (d *Difficulty) UnmarshalText(text []byte) error {
+	difficulty, err := ParseDifficulty(string(text))
+	if err != nil {
+		return err
+	}
+	*d = difficulty
+	return nil
+}
+
+// UnmarshalText reads a level by name
+func (l *SuccessLevel) UnmarshalText(text []byte) error {
+	for level := Fumble; level <= CriticalSuccess; level++ {
+		if level.String() == string(text) {
+			*l = level
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown success level %q", text)
+}
+
+// ResolveCheck returns the success level of a 1D100 roll against a skill or characteristic
+// of the given value at the given difficulty. A 1 is always a critical and a 100 always a
+// fumble. When the number needed is under 50, any roll of 96 or more is a fumble.
+func ResolveCheck(roll, value int, difficulty Difficulty) SuccessLevel {
+	needed := difficulty.target(value)
+	switch {
+	case roll == 1:
+		return CriticalSuccess
+	case roll >= 100, needed < 50 && roll >= 96:
+		return Fumble
+	case roll <= value/5:
+		return ExtremeSuccess
+	case roll <= value/2:
+		return HardSuccess
+	case roll <= value:
+		return RegularSuccess
+	default:
+		return Failure
+	}
+}
+
+// Succeeds reports whether a level passes a check of the given difficulty
+func (l SuccessLevel) Succeeds(difficulty Difficulty) bool {
+	if l == CriticalSuccess {
+		return true
+	}
+	return l >= RegularSuccess+SuccessLevel(difficulty)
+}
+
+// CheckResult is the outcome of a skill or characteristic check
+type CheckResult struct {
+	Target      string       `json:"target"` // Skill or characteristic checked
+	Value       int          `json:"value"`  // Its value at the time of the check
+	Difficulty  Difficulty   `json:"difficulty"`
+	Roll        dice.Roll    `json:"roll"` // The percentile roll with any bonus or penalty dice
+	Level       SuccessLevel `json:"level"`
+	Success     bool         `json:"success"`
+	Improvement bool         `json:"improvement"` // Whether the skill was ticked for improvement
+}
+
+// unimprovableSkills never earn an improvement check from a successful roll
+var unimprovableSkills = []string{"Cthulhu Mythos", "Credit Rating"}
+
+// Check rolls against one of the investigator's skills or characteristics, named by skill
+// name, attribute key such as "Power" or abbreviation such as "POW". With markImprovement
+// a successful skill roll ticks the skill for the development phase, unless a bonus die
+// helped the roll.
+func (i *Investigator) Check(r *rand.Rand, name string, difficulty Difficulty, bonus, penalty int, markImprovement bool) (CheckResult, error) {
+	target, value, isSkill, ok := i.checkTarget(name)
+	if !ok {
+		return CheckResult{}, fmt.Errorf("%q is not a skill or characteristic of the investigator", name)
+	}
+
+	roll := dice.Percentile(r, bonus, penalty)
+	level := ResolveCheck(roll.Total, value, difficulty)
+	result := CheckResult{
+		Target:     target,
+		Value:      value,
+		Difficulty: difficulty,
+		Roll:       roll,
+		Level:      level,
+		Success:    level.Succeeds(difficulty),
+	}
+
+	if markImprovement && result.Success && isSkill && bonus <= penalty {
+		skill := i.Skills[target]
+		for _, unimprovable := range unimprovableSkills {
+			if skill.Name == unimprovable {
+				return result, nil
+			}
+		}
+		skill.IsSelected = true
+		i.Skills[target] = skill
+		result.Improvement = true
+	}
+	return result, nil
+}
+
+// characteristicAliases names the characteristics whose stored abbreviation is not the usual one
+var characteristicAliases = map[string]string{
+	"LUCK": AttrLuck,
+	"LCK":  AttrLuck,
+	"SAN":  AttrSanity,
+}
+
+// checkTarget finds the skill or characteristic a check names
+func (i *Investigator) checkTarget(name string) (target string, value int, isSkill, ok bool) {
+	if skill, found := i.Skills[name]; found && skill.Base == 0 && name != "Dodge_Copy" {
+		return name, skill.Value, true, true
+	}
+	if alias, found := characteristicAliases[strings.ToUpper(name)]; found {
+		name = alias
+	}
+	for key, attr := range i.Attributes {
+		if strings.EqualFold(key, name) || strings.EqualFold(attr.Name, name) {
+			return key, attr.Value, false, true
+		}
+	}
+	return "", 0, false, false
+}
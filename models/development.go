@@ -0,0 +1,101 @@
+package models
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
+	"time"
+
+	"book-of-shadows/internal/dice"
+)
+
+// masterySkillValue is the skill value that earns a Sanity reward when first reached
+const masterySkillValue = 90
+
+var (
+	improvementRoll = dice.MustParse("1D10")
+	masteryReward   = dice.MustParse("2D6")
+)
+
+// SkillImprovement is the improvement roll made for one checked skill
+type SkillImprovement struct {
+	Skill    string `json:"skill"`
+	Roll     int    `json:"roll"`     // The 1D100 improvement roll
+	Improved bool   `json:"improved"` // Whether the roll was over the skill or over 95
+	Gain     int    `json:"gain"`     // The 1D10 added to the skill
+	From     int    `json:"from"`
+	To       int    `json:"to"`
+}
+
+// DevelopmentReport records what an investigator gained in one development phase
+type DevelopmentReport struct {
+	Date         time.Time          `json:"date"`
+	Improvements []SkillImprovement `json:"improvements"`
+	SanityGained int                `json:"sanityGained"` // 2D6 for each skill that reached 90%
+}
+
+// Develop runs the development phase at the end of a session. Every skill ticked for
+// improvement is rolled against, and improves by 1D10 when the roll is over its value or
+// over 95. A skill reaching 90% for the first time rewards 2D6 Sanity. The ticks are
+// cleared and the report kept on the investigator. Credit Rating and Cthulhu Mythos never
+// improve this way, and investigators still in creation have nothing to develop yet.
+func (i *Investigator) Develop(r *rand.Rand, date time.Time) (DevelopmentReport, error) {
+	if i.Creating {
+		return DevelopmentReport{}, fmt.Errorf("investigators develop once creation is complete")
+	}
+
+	names := make([]string, 0)
+	for name, skill := range i.Skills {
+		if skill.IsSelected && !slices.Contains(unimprovableSkills, skill.Name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return DevelopmentReport{}, fmt.Errorf("nothing to develop, no skill is ticked for improvement")
+	}
+	sort.Strings(names)
+
+	report := DevelopmentReport{Date: date, Improvements: []SkillImprovement{}}
+	creditRating := i.Skills["Credit Rating"].Value
+	for _, name := range names {
+		skill := i.Skills[name]
+		skill.IsSelected = false
+
+		improvement := SkillImprovement{Skill: name, Roll: r.Intn(100) + 1, From: skill.Value, To: skill.Value}
+		if improvement.Roll > skill.Value || improvement.Roll > 95 {
+			improvement.Improved = true
+			improvement.Gain = improvementRoll.Total(r)
+			improvement.To = skill.Value + improvement.Gain
+			if improvement.From < masterySkillValue && improvement.To >= masterySkillValue {
+				report.SanityGained += masteryReward.Total(r)
+			}
+			skill.Value = improvement.To
+			if name == "Dodge" {
+				if dodge, ok := i.Skills["Dodge_Copy"]; ok {
+					dodge.Value = skill.Value
+					i.Skills["Dodge_Copy"] = dodge
+				}
+			}
+		}
+		i.Skills[name] = skill
+		report.Improvements = append(report.Improvements, improvement)
+	}
+
+	if report.SanityGained > 0 {
+		san := i.Attributes[AttrSanity]
+		san.Value = min(san.Value+report.SanityGained, i.MaxSanity())
+		i.Attributes[AttrSanity] = san
+	}
+	if i.Skills["Credit Rating"].Value != creditRating {
+		i.SetWealth()
+	}
+
+	i.Development = append(i.Development, report)
+	return report, nil
+}
+
+// MaxSanity is the highest Sanity the investigator can have, 99 less their Cthulhu Mythos
+func (i *Investigator) MaxSanity() int {
+	return 99 - i.Skills["Cthulhu Mythos"].Value
+}
@@ -0,0 +1,168 @@
+package models
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+// GenerationMethod is how the characteristics of an investigator are generated
+type GenerationMethod string
+
+const (
+	GenerationRolled        GenerationMethod = "rolled"          // Rolled or typed one at a time, the default
+	GenerationPointBuy      GenerationMethod = "point-buy"       // PointBuyPool points divided between them
+	GenerationQuickFire     GenerationMethod = "quick-fire"      // QuickFireArray assigned between them
+	GenerationRollAndAssign GenerationMethod = "roll-and-assign" // A set rolled once and assigned between them
+)
+
+// GenerationMethods lists the generation methods in the order the wizard offers them
+var GenerationMethods = []GenerationMethod{GenerationRolled, GenerationPointBuy, GenerationQuickFire, GenerationRollAndAssign}
+
+// PointBuyPool is the number of points divided between the characteristics with point-buy
+const PointBuyPool = 460
+
+// Point-buy keeps each characteristic within what the dice could have rolled
+const (
+	pointBuyMin = 15
+	pointBuyMax = 90
+)
+
+// QuickFireArray are the values assigned between the characteristics with quick-fire
+var QuickFireArray = []int{40, 50, 50, 50, 60, 60, 70, 80}
+
+// generatedCharacteristics are the characteristics a generation method covers, by abbreviation.
+// Luck is always rolled on its own.
+var generatedCharacteristics = []string{"STR", "CON", "SIZ", "DEX", "APP", "INT", "POW", "EDU"}
+
+func (m GenerationMethod) String() string {
+	switch m {
+	case GenerationRolled:
+		return "Roll Each"
+	case GenerationPointBuy:
+		return "Point-Buy"
+	case GenerationQuickFire:
+		return "Quick-Fire"
+	case GenerationRollAndAssign:
+		return "Roll and Assign"
+	default:
+		return string(m)
+	}
+}
+
+// ParseGenerationMethod returns the generation method matching a name such as "point-buy".
+// An empty name is the default of rolling each characteristic.
+func ParseGenerationMethod(name string) (GenerationMethod, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return GenerationRolled, nil
+	}
+	for _, method := range GenerationMethods {
+		if string(method) == name {
+			return method, nil
+		}
+	}
+	return "", fmt.Errorf("unknown generation method %q", name)
+}
+
+// Generation returns the investigator's generation method, rolling each characteristic
+// for investigators that never picked one
+func (i *Investigator) Generation() GenerationMethod {
+	if i.GenerationMethod == "" {
+		return GenerationRolled
+	}
+	return i.GenerationMethod
+}
+
+// SetGenerationMethod switches the method the characteristics are generated with. Roll and
+// assign rolls the set to assign from here, five 3D6×5 and three (2D6+6)×5, best first.
+// The set is rolled once and kept, so picking the method again does not roll a better one.
+func (i *Investigator) SetGenerationMethod(r *rand.Rand, method GenerationMethod) {
+	i.GenerationMethod = method
+	if method != GenerationRollAndAssign || len(i.GenerationPool) > 0 {
+		return
+	}
+	for _, name := range generatedCharacteristics {
+		roll := standardRoll
+		if name == "SIZ" || name == "INT" || name == "EDU" {
+			roll = educatedRoll
+		}
+		i.GenerationPool = append(i.GenerationPool, roll.Total(r))
+	}
+	slices.Sort(i.GenerationPool)
+	slices.Reverse(i.GenerationPool)
+}
+
+// GenerationValues returns the values the characteristics are assigned from, nil when
+// the method does not assign a set
+func (i *Investigator) GenerationValues() []int {
+	switch i.Generation() {
+	case GenerationQuickFire:
+		return QuickFireArray
+	case GenerationRollAndAssign:
+		return i.GenerationPool
+	default:
+		return nil
+	}
+}
+
+// ValidateCharacteristic checks an edit of one characteristic, by abbreviation, against the
+// generation method. Until aging ends generation, methods other than rolling only assign the
+// characteristics all at once through ValidateCharacteristics.
+func (i *Investigator) ValidateCharacteristic(name string, value int) error {
+	if i.Aging != nil || !slices.Contains(generatedCharacteristics, name) {
+		return nil
+	}
+	if method := i.Generation(); method != GenerationRolled {
+		return fmt.Errorf("%s assigns %s together with the other characteristics", method, name)
+	}
+	if value < 1 || value > 99 {
+		return fmt.Errorf("%s must be between 1 and 99", name)
+	}
+	return nil
+}
+
+// ValidateCharacteristics checks characteristics keyed by abbreviation against the
+// investigator's generation method
+func (i *Investigator) ValidateCharacteristics(data map[string]int) error {
+	for _, name := range append(slices.Clone(generatedCharacteristics), "LCK") {
+		value, ok := data[name]
+		if !ok {
+			return fmt.Errorf("%s is missing", name)
+		}
+		if value < 1 || value > 99 {
+			return fmt.Errorf("%s must be between 1 and 99", name)
+		}
+	}
+
+	switch method := i.Generation(); method {
+	case GenerationPointBuy:
+		total := 0
+		for _, name := range generatedCharacteristics {
+			value := data[name]
+			if value < pointBuyMin || value > pointBuyMax {
+				return fmt.Errorf("%s must be between %d and %d with point-buy", name, pointBuyMin, pointBuyMax)
+			}
+			total += value
+		}
+		if total != PointBuyPool {
+			return fmt.Errorf("point-buy spends %d points, %d were spent", PointBuyPool, total)
+		}
+	case GenerationQuickFire, GenerationRollAndAssign:
+		values := i.GenerationValues()
+		if len(values) == 0 {
+			return fmt.Errorf("no values were rolled to assign")
+		}
+		assigned := make([]int, 0, len(generatedCharacteristics))
+		for _, name := range generatedCharacteristics {
+			assigned = append(assigned, data[name])
+		}
+		slices.Sort(assigned)
+		want := slices.Sorted(slices.Values(values))
+		if !slices.Equal(assigned, want) {
+			return fmt.Errorf("%s assigns each of %v once", method, values)
+		}
+	}
+	return nil
+}
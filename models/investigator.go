@@ -7,21 +7,78 @@ import (
 	"math/rand"
 	"slices"
 	"strings"
+
+	"book-of-shadows/internal/dice"
 )
 
 type Era int
 type GameMode int
 
+// Era values are stored by number, so new eras are appended
 const (
 	Twenties Era = iota
 	Modern
+	Gaslight
 )
 
+// Eras lists the eras in chronological order
+var Eras = []Era{Gaslight, Twenties, Modern}
+
 const (
 	Classic GameMode = iota
 	Pulp
 )
 
+func (e Era) String() string {
+	switch e {
+	case Twenties:
+		return "1920s"
+	case Modern:
+		return "Modern"
+	case Gaslight:
+		return "Gaslight"
+	default:
+		return fmt.Sprintf("Era(%d)", int(e))
+	}
+}
+
+// ParseEra returns the era matching a name such as "1920s", "gaslight" or "modern"
+func ParseEra(name string) (Era, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "1920s", "twenties":
+		return Twenties, nil
+	case "modern":
+		return Modern, nil
+	case "gaslight", "1890s", "victorian":
+		return Gaslight, nil
+	default:
+		return 0, fmt.Errorf("unknown era %q", name)
+	}
+}
+
+func (m GameMode) String() string {
+	switch m {
+	case Classic:
+		return "Classic"
+	case Pulp:
+		return "Pulp"
+	default:
+		return fmt.Sprintf("GameMode(%d)", int(m))
+	}
+}
+
+// ParseGameMode returns the game mode matching a name such as "pulp" or "classic"
+func ParseGameMode(name string) (GameMode, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "classic":
+		return Classic, nil
+	case "pulp":
+		return Pulp, nil
+	default:
+		return 0, fmt.Errorf("unknown game mode %q", name)
+	}
+}
+
 type ProfilePic struct {
 	FilePath string `json:"path"`
 	FileName string `json:"name"`
@@ -31,12 +88,13 @@ func (pp *ProfilePic) String() string {
 	return fmt.Sprintf("%v", pp.FilePath)
 }
 
-func rollD6() int {
-	return rand.Intn(6) + 1
-}
-
-func coreRoll() int {
-	return (rollD6() + 13) * 5
+// random returns the source the investigator's generation draws from. Investigators
+// generated from a seed keep theirs so the same seed always rolls the same investigator.
+func (i *Investigator) random() *rand.Rand {
+	if i.rng == nil {
+		i.rng = rand.New(rand.NewSource(rand.Int63()))
+	}
+	return i.rng
 }
 
 func (i *Investigator) SetHP() {
@@ -55,10 +113,19 @@ func (i *Investigator) SetHP() {
 
 }
 
+// IsPulp reports whether the investigator is played with the Pulp Cthulhu rules,
+// which add archetypes, talents and doubled hit points
+func (i *Investigator) IsPulp() bool {
+	return i.GameMode == Pulp
+}
+
 func (i *Investigator) PickRandomTalents() {
+	if i.Archetype == nil {
+		return
+	}
 	// ToDo: Need to support archetype talent class or specific talent suggestion
 	for j := 0; j < i.Archetype.AmountOfTalents; j++ {
-		talentName := TalentsList[rand.Intn(len(TalentsList))]
+		talentName := TalentsList[i.random().Intn(len(TalentsList))]
 		i.Talents = append(i.Talents, Talents[talentName])
 	}
 
@@ -82,6 +149,16 @@ var buildDamageTable = []buildDamageRange{
 	{524, "+5D6", "+6"},
 }
 
+// DiceVars returns the investigator's values that dice expressions can name, such as DB
+// for the damage bonus in "1D10+1D4+DB"
+func (i *Investigator) DiceVars() dice.Vars {
+	damageBonus := strings.TrimSpace(i.DamageBonus)
+	if damageBonus == "" || strings.EqualFold(damageBonus, "None") {
+		damageBonus = "0"
+	}
+	return dice.Vars{"DB": damageBonus}
+}
+
 func (i *Investigator) SetBuildAndDMG() {
 	compoundValue := i.Attributes[AttrStrength].Value + i.Attributes[AttrSize].Value
 
@@ -103,15 +180,19 @@ func (i *Investigator) SetBuildAndDMG() {
 	}
 }
 
+// SetMovement sets the movement rate from STR and DEX against SIZ: 7 when both are lower,
+// 9 when both are higher and 8 otherwise, less 1 for each decade from the forties on
 func (i *Investigator) SetMovement() {
-	// if both are not greater or lesser than size it means one of the two is.
-	if i.Attributes[AttrDexterity].Value < i.Attributes[AttrSize].Value && i.Attributes[AttrStrength].Value < i.Attributes[AttrSize].Value {
+	str, dex, siz := i.Attributes[AttrStrength].Value, i.Attributes[AttrDexterity].Value, i.Attributes[AttrSize].Value
+	switch {
+	case str < siz && dex < siz:
 		i.Move = 7
-	} else if i.Attributes[AttrStrength].Value > i.Attributes[AttrSize].Value && i.Attributes[AttrDexterity].Value > i.Attributes[AttrSize].Value {
-		i.Move = 9
-	} else {
+	case str > siz && dex > siz:
 		i.Move = 9
+	default:
+		i.Move = 8
 	}
+	i.Move -= AgeBracketFor(i.Age).MovePenalty
 }
 
 func (i *Investigator) InitializeAttributes() {
@@ -120,25 +201,35 @@ func (i *Investigator) InitializeAttributes() {
 	coreCharacteristics := make(map[string]bool)
 	if i.Archetype != nil {
 		// there is one chore characteristic per each character
-		pickedCore := rand.Intn(len(i.Archetype.CoreCharacteristic))
+		pickedCore := i.random().Intn(len(i.Archetype.CoreCharacteristic))
 		coreCharacteristics[i.Archetype.CoreCharacteristic[pickedCore]] = true
 	}
 	// Initialize each attribute
 	isPulp := i.GameMode == Pulp // or however you check for pulp mode
 
-	for key, _ := range i.Attributes {
+	// Roll in a fixed order so a seed always gives the same attributes
+	keys := make([]string, 0, len(i.Attributes))
+	for key := range i.Attributes {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
 
 		// An attribute is core if we're in pulp mode AND it's in core characteristics
 		isCore := isPulp && coreCharacteristics[key]
 
 		// Initialize the attribute
 		attribute := i.Attributes[key]
-		attribute.Initialize(isCore)
+		attribute.Initialize(i.random(), isCore)
 		i.Attributes[key] = attribute
 	}
 
 }
 
+// CalculateOccupationSkillPoints returns the occupation points of the investigator. When the
+// formula offers a choice of characteristic the best one counts, so the points stay the same
+// every time they are recalculated.
 func (i *Investigator) CalculateOccupationSkillPoints() int {
 	formula := i.Occupation.SkillPoints
 	points := 0
@@ -146,35 +237,37 @@ func (i *Investigator) CalculateOccupationSkillPoints() int {
 		attr := i.Attributes[skillAttr.Name]
 		points += attr.Value * skillAttr.Multiplier
 	}
-	if len(formula.Options) > 0 {
-		picked := rand.Intn(len(formula.Options))
-		optional := formula.Options[picked]
-		attrOptional := i.Attributes[optional.Name]
-		points += attrOptional.Value * optional.Multiplier
+	best := 0
+	for _, optional := range formula.Options {
+		best = max(best, i.Attributes[optional.Name].Value*optional.Multiplier)
 	}
-	return points
+	return points + best
 }
 
-func (i *Investigator) AssignSkillPoints(assignablePoints int, skills []string) int {
+// AssignSkillPoints spends points from a pool at random on skills, recording what it put into
+// each in SkillAllocations, and returns the points it could not spend. Occupation points first
+// raise Credit Rating to the minimum of the occupation.
+func (i *Investigator) AssignSkillPoints(pool SkillPool, assignablePoints int, skills []string) int {
 	skillLimit := 90
 	if i.GameMode == Pulp {
 		skillLimit = 95
 	}
 	CR := i.Skills["Credit Rating"]
-	if CR.Value < i.Occupation.CreditRating.Min {
+	if pool == PoolOccupation && CR.Value < i.Occupation.CreditRating.Min {
 		creditPointsBase := i.Occupation.CreditRating.Min - CR.Value
 		assignablePoints -= creditPointsBase
-		CR.Value = creditPointsBase
+		CR.Value += creditPointsBase
 		i.Skills["Credit Rating"] = CR
+		i.recordAllocation(pool, "Credit Rating", creditPointsBase)
 	}
 	for assignablePoints > 0 {
-		skillPicked := rand.Intn(len(skills))
-		skillName := skills[skillPicked]
+		skillPicked := i.random().Intn(len(skills))
+		skillName := i.Era.SkillName(skills[skillPicked])
 		skill, ok := i.Skills[skillName]
 		if !ok || skill.Base == 1 {
 			continue
 		}
-		pointsToAssign := rand.Intn(50) + 5
+		pointsToAssign := i.random().Intn(50) + 5
 
 		if assignablePoints < pointsToAssign || assignablePoints-pointsToAssign < 0 {
 			pointsToAssign = assignablePoints
@@ -190,6 +283,7 @@ func (i *Investigator) AssignSkillPoints(assignablePoints int, skills []string)
 		skill.Value += pointsToAssign
 
 		i.Skills[skillName] = skill
+		i.recordAllocation(pool, skillName, pointsToAssign)
 	}
 	return assignablePoints
 }
@@ -207,14 +301,20 @@ func (i *Investigator) GetSkills() {
 }
 
 func (i *Investigator) AssignOccupation() {
-	occupation := rand.Intn(len(OccupationsList))
+	candidates := OccupationsForEra(i.Era)
 	if i.GameMode == Pulp && i.Archetype != nil {
-		if len(i.Archetype.SuggestedOccupations) > 0 {
-			occupation = rand.Intn(len(i.Archetype.SuggestedOccupations))
+		suggested := make([]string, 0, len(i.Archetype.SuggestedOccupations))
+		for _, name := range i.Archetype.SuggestedOccupations {
+			if occupation, ok := Occupations[name]; ok && occupation.AvailableIn(i.Era) {
+				suggested = append(suggested, name)
+			}
+		}
+		if len(suggested) > 0 {
+			candidates = suggested
 		}
 	}
 
-	pickedOccupation := Occupations[OccupationsList[occupation]]
+	pickedOccupation := Occupations[candidates[i.random().Intn(len(candidates))]]
 	i.Occupation = &pickedOccupation
 }
 
@@ -230,7 +330,7 @@ func (i *Investigator) addMissingSkills(skills *[]string) {
 					FormName:     skill.FormName,
 					Default:      skill.Default,
 					Value:        skill.Value,
-					Era:          []Era{Twenties, Modern},
+					Era:          Eras,
 					Base:         0,
 					NeedsFormDef: 1,
 				}
@@ -239,6 +339,7 @@ func (i *Investigator) addMissingSkills(skills *[]string) {
 	}
 
 	for _, occ := range *skills {
+		occ = i.Era.SkillName(occ)
 		_, ok := i.Skills[occ]
 		if ok {
 			continue
@@ -256,7 +357,8 @@ func (i *Investigator) addMissingSkills(skills *[]string) {
 			}
 			// if several matched pick one
 			if len(matches) > 0 {
-				matchPick := rand.Intn(len(matches))
+				slices.SortFunc(matches, func(a, b Skill) int { return strings.Compare(a.Name, b.Name) })
+				matchPick := i.random().Intn(len(matches))
 				skillMatched := matches[matchPick]
 				skill, _ := i.Skills[skillMatched.Name]
 				occ = skill.Name
@@ -289,7 +391,7 @@ func (i *Investigator) addMissingSkills(skills *[]string) {
 						FormName:     "Custom1",
 						Default:      1,
 						Value:        1,
-						Era:          []Era{Twenties, Modern},
+						Era:          Eras,
 						Base:         0,
 						NeedsFormDef: 1,
 					}
@@ -312,7 +414,7 @@ func (i *Investigator) addMissingSkills(skills *[]string) {
 					FormName:     "Custom1",
 					Default:      1,
 					Value:        1,
-					Era:          []Era{Twenties, Modern},
+					Era:          Eras,
 					Base:         0,
 					NeedsFormDef: 1,
 				}
@@ -331,10 +433,42 @@ func (i *Investigator) ToJSON() ([]byte, error) {
 	return bytes, nil
 }
 
+// InvestigatorFromJSON parses investigator JSON and restores fields that are not serialized
+func InvestigatorFromJSON(data []byte) (*Investigator, error) {
+	var investigator Investigator
+	if err := json.Unmarshal(data, &investigator); err != nil {
+		return nil, fmt.Errorf("error unmarshaling investigator: %v", err)
+	}
+
+	// Investigators saved before the game mode and era were stored were all Modern Pulp
+	var stored struct {
+		GameMode *GameMode `json:"GameMode"`
+		Era      *Era      `json:"Era"`
+	}
+	if err := json.Unmarshal(data, &stored); err == nil {
+		if stored.GameMode == nil {
+			investigator.GameMode = Pulp
+		}
+		if stored.Era == nil {
+			investigator.Era = Modern
+		}
+	}
+
+	// Populate SpecialArchetypeRules from the Archetypes map (not serialized with json:"-")
+	if investigator.Archetype != nil && investigator.Archetype.Name != "" {
+		if archetype, exists := Archetypes[investigator.Archetype.Name]; exists {
+			investigator.Archetype.SpecialArchetypeRules = archetype.SpecialArchetypeRules
+		}
+	}
+
+	return &investigator, nil
+}
+
 type Investigator struct {
 	ID                         string               `json:"id"`
-	Era                        Era                  `json:"-"`
-	GameMode                   GameMode             `json:"-"`
+	Seed                       int64                `json:"Seed,omitempty"`
+	Era                        Era                  `json:"Era"`
+	GameMode                   GameMode             `json:"GameMode"`
 	Name                       string               `json:"Investigators_Name"`
 	Residence                  string               `json:"Residence"`
 	Birthplace                 string               `json:"Birthplace"`
@@ -362,16 +496,41 @@ type Investigator struct {
 	UnassignedOccupationPoints int                  `json:"UnassignedOccupationPoints"`
 	UnassignedArchetypePoints  int                  `json:"UnassignedArchetypePoints"`
 	UnassignedFreePoints       int                  `json:"UnassignedFreePoints"`
+	Development                []DevelopmentReport  `json:"Development,omitempty"`
+	SanityDay                  SanityDay            `json:"SanityDay"`
+	Weapons                    []Weapon             `json:"Weapons,omitempty"`
+	Gear                       []Gear               `json:"Gear,omitempty"`
+	Wealth                     Wealth               `json:"Wealth"`
+	Aging                      *AgingReport         `json:"Aging,omitempty"`
+	GenerationMethod           GenerationMethod     `json:"GenerationMethod,omitempty"`
+	GenerationPool             []int                `json:"GenerationPool,omitempty"` // Values rolled to assign with roll and assign
+	StrictRules                bool                 `json:"StrictRules,omitempty"`    // Reject updates breaking the creation rules
+	SkillAllocations           SkillAllocations     `json:"SkillAllocations,omitempty"`
+	Creating                   bool                 `json:"Creating,omitempty"` // Still in the wizard, skills are bought from the pools
+
+	rng *rand.Rand
+}
+
+// RandomInvestigator generates an investigator from a fresh seed
+func RandomInvestigator(mode GameMode, era Era) *Investigator {
+	return SeededInvestigator(mode, era, rand.Int63n(maxSeed)+1)
 }
 
-func RandomInvestigator(mode GameMode) *Investigator {
+// maxSeed keeps generated seeds short enough to read out at the table
+const maxSeed = 1_000_000_000
+
+// SeededInvestigator generates an investigator whose every roll comes from seed,
+// so the same seed, mode and era always give the same investigator. Seeds are
+// positive, zero means the investigator was not generated from one.
+func SeededInvestigator(mode GameMode, era Era, seed int64) *Investigator {
 	inv := Investigator{
-		Era:              1,
+		Seed:             seed,
+		rng:              rand.New(rand.NewSource(seed)),
+		Era:              era,
 		GameMode:         mode,
 		Name:             "John Doe",
 		Residence:        "Boston",
 		Birthplace:       "Dallas TX",
-		Age:              37,
 		ProfilePic:       ProfilePic{"/sample/path/env", "profile"},
 		Insane:           false,
 		TemporaryInsane:  false,
@@ -458,15 +617,18 @@ func RandomInvestigator(mode GameMode) *Investigator {
 		Build:       "Big",
 		DamageBonus: "1D4",
 	}
+	inv.Age = RandomAge(inv.random())
 	// assign archetype
 	if mode == Pulp {
-		inv.Archetype = PickRandomArchetype()
+		inv.Archetype = PickRandomArchetype(inv.random())
 		inv.PickRandomTalents()
 	}
 	// assign occupation
 	inv.AssignOccupation()
 	// Initialize Attributes
 	inv.InitializeAttributes()
+	// Evenly spread deductions always add up, so aging cannot fail here
+	inv.ApplyAging(inv.random(), nil)
 	LCK := inv.Attributes[AttrLuck]
 	SAN := inv.Attributes[AttrSanity]
 	POW := inv.Attributes[AttrPower]
@@ -474,10 +636,10 @@ func RandomInvestigator(mode GameMode) *Investigator {
 	DEX := inv.Attributes[AttrDexterity]
 	EDU := inv.Attributes[AttrEducation]
 	INT := inv.Attributes[AttrIntelligence]
-	LCK.Initialize(false)
+	LCK.Initialize(inv.random(), false)
 	// allow re roll
 	if LCK.Value < 45 {
-		LCK.Initialize(false)
+		LCK.Initialize(inv.random(), false)
 	}
 
 	SAN.Value = POW.Value
@@ -519,34 +681,52 @@ func RandomInvestigator(mode GameMode) *Investigator {
 	inv.UnassignedOccupationPoints = occupationPoints
 	inv.OccupationPoints = occupationPoints
 
-	inv.ArchetypePoints = inv.Archetype.BonusPoints
-	inv.addMissingSkills(&inv.Archetype.Skills)
-	sparePoints := inv.AssignSkillPoints(inv.ArchetypePoints, inv.Archetype.Skills)
-	inv.UnassignedArchetypePoints = sparePoints
+	// Classic investigators have no archetype and so no archetype points
+	if inv.Archetype != nil {
+		inv.ArchetypePoints = inv.Archetype.BonusPoints
+		inv.addMissingSkills(&inv.Archetype.Skills)
+		inv.AssignSkillPoints(PoolArchetype, inv.ArchetypePoints, inv.Archetype.Skills)
+	}
 
 	occupationSkills := inv.GetOccupationSkills()
 	inv.addMissingSkills(occupationSkills)
 
-	sparePoints = inv.AssignSkillPoints(occupationPoints, *occupationSkills)
-	inv.UnassignedOccupationPoints = sparePoints
+	inv.AssignSkillPoints(PoolOccupation, occupationPoints, *occupationSkills)
 	var skillsList []string
 	for s, v := range inv.Skills {
 		if v.Name != "Cthulhu Mythos" && v.Name != "Dodge_Copy" {
 			skillsList = append(skillsList, s)
 		}
 	}
+	slices.Sort(skillsList)
 	inv.FreePoints = INT.Value * 2
-	sparePoints = inv.AssignSkillPoints(inv.FreePoints, skillsList)
-	inv.UnassignedFreePoints = sparePoints
+	inv.AssignSkillPoints(PoolPersonal, inv.FreePoints, skillsList)
+	// Points lost to skills at the cap are left to spend
+	inv.SyncSkillPools()
+	inv.SetWealth()
 	return &inv
 }
 
+// InvestigatorBaseCreate creates an investigator from the wizard's personal information step.
+// Archetypes only exist in Pulp mode, which is the default when no mode is given.
+// The era defaults to Modern.
 func InvestigatorBaseCreate(data map[string]any) *Investigator {
-	archetype := Archetypes[data["archetype"].(string)]
+	mode := Pulp
+	if name, ok := data["mode"].(string); ok && name != "" {
+		if parsed, err := ParseGameMode(name); err == nil {
+			mode = parsed
+		}
+	}
+	era := Modern
+	if name, ok := data["era"].(string); ok && name != "" {
+		if parsed, err := ParseEra(name); err == nil {
+			era = parsed
+		}
+	}
 	occupation := Occupations[data["occupation"].(string)]
 	inv := Investigator{
-		Era:              1,
-		GameMode:         Pulp,
+		Era:              era,
+		GameMode:         mode,
 		Name:             data["name"].(string),
 		Residence:        data["residence"].(string),
 		Birthplace:       data["birthplace"].(string),
@@ -563,21 +743,34 @@ func InvestigatorBaseCreate(data map[string]any) *Investigator {
 		Move:             2,
 		Build:            "Big",
 		DamageBonus:      "1D4",
-		Archetype:        &archetype,
 		Occupation:       &occupation,
+		Creating:         true,
+	}
+	if mode == Pulp {
+		name, _ := data["archetype"].(string)
+		archetype := Archetypes[name]
+		inv.Archetype = &archetype
 	}
 	inv.GetSkills()
 	inv.addMissingSkills(&[]string{})
-	inv.addMissingSkills(&inv.Archetype.Skills)
+	if inv.Archetype != nil {
+		inv.addMissingSkills(&inv.Archetype.Skills)
+		inv.ArchetypePoints = inv.Archetype.BonusPoints
+		inv.UnassignedArchetypePoints = inv.ArchetypePoints
+	}
 	occupationSkills := inv.GetOccupationSkills()
 	inv.addMissingSkills(occupationSkills)
-	inv.ArchetypePoints = inv.Archetype.BonusPoints
-	inv.UnassignedArchetypePoints = inv.ArchetypePoints
+	inv.SetWealth()
 
 	return &inv
 }
 
-func (i *Investigator) InvestigatorUpdateAttributes(data map[string]int) {
+// InvestigatorUpdateAttributes replaces the characteristics with the ones keyed by abbreviation
+// in data once they pass the rules of the investigator's generation method
+func (i *Investigator) InvestigatorUpdateAttributes(data map[string]int) error {
+	if err := i.ValidateCharacteristics(data); err != nil {
+		return err
+	}
 	i.Attributes = map[string]Attribute{
 		AttrStrength: {
 			Name:          "STR",
@@ -691,8 +884,11 @@ func (i *Investigator) InvestigatorUpdateAttributes(data map[string]int) {
 	}
 	occupationPoints := i.CalculateOccupationSkillPoints()
 	i.OccupationPoints = occupationPoints
-	i.ArchetypePoints = i.Archetype.BonusPoints
+	if i.Archetype != nil {
+		i.ArchetypePoints = i.Archetype.BonusPoints
+	}
 	i.FreePoints = INT.Value * 2
+	return nil
 }
 
 func (i *Investigator) GetOccupationSkills() *[]string {
@@ -700,16 +896,16 @@ func (i *Investigator) GetOccupationSkills() *[]string {
 
 	for _, skillReq := range i.Occupation.SkillRequirements {
 		if skillReq.Type == "required" {
-			occupationSkills = append(occupationSkills, skillReq.Skill)
+			occupationSkills = append(occupationSkills, i.Era.SkillName(skillReq.Skill))
 		} else {
 			picked := make([]int, 0)
-			for i := 0; i < skillReq.SkillChoice.NumRequired; i++ {
-				choice := rand.Intn(len(skillReq.SkillChoice.Skills))
+			for n := 0; n < skillReq.SkillChoice.NumRequired; n++ {
+				choice := i.random().Intn(len(skillReq.SkillChoice.Skills))
 				if slices.Contains(picked, choice) {
 					continue
 				} else {
 					picked = append(picked, choice)
-					occupationSkills = append(occupationSkills, skillReq.SkillChoice.Skills[choice])
+					occupationSkills = append(occupationSkills, i.Era.SkillName(skillReq.SkillChoice.Skills[choice]))
 				}
 			}
 		}
@@ -0,0 +1,146 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// LivingStandard describes how an investigator lives at a band of Credit Rating
+type LivingStandard struct {
+	Name        string `json:"Name"`
+	MinCredit   int    `json:"MinCredit"`
+	MaxCredit   int    `json:"MaxCredit"`
+	Description string `json:"Description"`
+}
+
+// livingStandards holds the Credit Rating bands for each era, lowest first
+var livingStandards = map[Era][]LivingStandard{
+	Gaslight: {
+		{"Penniless", 0, 0, "Sleeps in doorways and workhouses, begs or scavenges for the next meal."},
+		{"Poor", 1, 9, "A shared room in a lodging house, walks everywhere and eats at coffee stalls."},
+		{"Average", 10, 49, "Rents respectable rooms, keeps a maid of all work and takes the omnibus."},
+		{"Wealthy", 50, 89, "A town house with servants, a private carriage and a club in the West End."},
+		{"Rich", 90, 98, "A London residence and a country estate, travels first class by rail and steamer."},
+		{"Super Rich", 99, 99, "Money is no object, society and the peerage open their doors."},
+	},
+	Twenties: {
+		{"Penniless", 0, 0, "Lives on the street or in flophouses and relies on charity."},
+		{"Poor", 1, 9, "A rented room or cheap hotel, rides the streetcar and eats at diners."},
+		{"Average", 10, 49, "A modest house or apartment, a second-hand car and the occasional night out."},
+		{"Wealthy", 50, 89, "A large house with a maid and a chauffeur, the latest automobile and fine dining."},
+		{"Rich", 90, 98, "Several homes, a staff of servants and first class passage on ocean liners."},
+		{"Super Rich", 99, 99, "Money is no object, the investigator can buy almost anything."},
+	},
+	Modern: {
+		{"Penniless", 0, 0, "Homeless, sleeps in shelters and gets by on handouts."},
+		{"Poor", 1, 9, "A shared flat or trailer, public transport and a prepaid phone."},
+		{"Average", 10, 49, "A mortgage or a decent rental, a reliable car and holidays once a year."},
+		{"Wealthy", 50, 89, "A large home, new cars, private healthcare and business class flights."},
+		{"Rich", 90, 98, "Several properties, household staff and a private jet on charter."},
+		{"Super Rich", 99, 99, "Money is no object, the investigator can buy almost anything."},
+	},
+}
+
+// LivingStandardFor returns the living standard of a Credit Rating in an era
+func LivingStandardFor(era Era, creditRating int) LivingStandard {
+	standards, ok := livingStandards[era]
+	if !ok {
+		standards = livingStandards[Modern]
+	}
+	for _, standard := range standards {
+		if creditRating <= standard.MaxCredit {
+			return standard
+		}
+	}
+	return standards[len(standards)-1]
+}
+
+// LivingStandard returns the investigator's living standard from their Credit Rating and era
+func (i *Investigator) LivingStandard() LivingStandard {
+	return LivingStandardFor(i.Era, i.Skills["Credit Rating"].Value)
+}
+
+// Wealth is the money an investigator has from their Credit Rating, in dollars
+type Wealth struct {
+	Cash          float64 `json:"Cash"`
+	Assets        float64 `json:"Assets"`
+	SpendingLevel float64 `json:"SpendingLevel"` // What they can spend in a day without it hurting
+}
+
+// wealthBand gives the wealth of a band of Credit Rating. Cash and assets are multiplied by
+// the Credit Rating when perCredit is set.
+type wealthBand struct {
+	maxCredit     int
+	perCredit     bool
+	cash, assets  float64
+	spendingLevel float64
+}
+
+// wealthBands holds the Cash and Assets tables of the Keeper Rulebook, lowest band first. The
+// rulebook only gives 1920s and Modern tables, so Gaslight investigators use the 1920s one.
+var wealthBands = map[Era][]wealthBand{
+	Twenties: {
+		{maxCredit: 0, cash: 0.5, spendingLevel: 0.5},
+		{maxCredit: 9, perCredit: true, cash: 1, assets: 10, spendingLevel: 2},
+		{maxCredit: 49, perCredit: true, cash: 2, assets: 50, spendingLevel: 10},
+		{maxCredit: 89, perCredit: true, cash: 5, assets: 500, spendingLevel: 50},
+		{maxCredit: 98, perCredit: true, cash: 20, assets: 2000, spendingLevel: 250},
+		{maxCredit: 99, cash: 50000, assets: 5000000, spendingLevel: 5000},
+	},
+	Modern: {
+		{maxCredit: 0, cash: 10, spendingLevel: 10},
+		{maxCredit: 9, perCredit: true, cash: 20, assets: 200, spendingLevel: 40},
+		{maxCredit: 49, perCredit: true, cash: 40, assets: 1000, spendingLevel: 200},
+		{maxCredit: 89, perCredit: true, cash: 100, assets: 10000, spendingLevel: 1000},
+		{maxCredit: 98, perCredit: true, cash: 400, assets: 40000, spendingLevel: 5000},
+		{maxCredit: 99, cash: 1000000, assets: 100000000, spendingLevel: 100000},
+	},
+}
+
+// WealthFor returns the cash, assets and spending level of a Credit Rating in an era
+func WealthFor(era Era, creditRating int) Wealth {
+	bands, ok := wealthBands[era]
+	if !ok {
+		bands = wealthBands[Twenties]
+	}
+	band := bands[len(bands)-1]
+	for _, b := range bands {
+		if creditRating <= b.maxCredit {
+			band = b
+			break
+		}
+	}
+
+	wealth := Wealth{Cash: band.cash, Assets: band.assets, SpendingLevel: band.spendingLevel}
+	if band.perCredit {
+		wealth.Cash *= float64(creditRating)
+		wealth.Assets *= float64(creditRating)
+	}
+	return wealth
+}
+
+// SetWealth recalculates the investigator's wealth from their Credit Rating and era
+func (i *Investigator) SetWealth() {
+	i.Wealth = WealthFor(i.Era, i.Skills["Credit Rating"].Value)
+}
+
+// FormatMoney writes an amount of dollars with thousands separators, showing cents only
+// when there are some, e.g. "$1,250" or "$0.50"
+func FormatMoney(amount float64) string {
+	cents := int64(math.Round(amount * 100))
+	whole := strconv.FormatInt(cents/100, 10)
+
+	var grouped strings.Builder
+	for n, digit := range whole {
+		if n > 0 && (len(whole)-n)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	if cents%100 != 0 {
+		return fmt.Sprintf("$%s.%02d", grouped.String(), cents%100)
+	}
+	return "$" + grouped.String()
+}
@@ -0,0 +1,173 @@
+package models
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+
+	"book-of-shadows/internal/dice"
+)
+
+// BoutKind says whether a bout of madness is played out at the table or summarised afterwards
+type BoutKind int
+
+const (
+	RealTimeBout BoutKind = iota
+	SummaryBout
+)
+
+func (k BoutKind) String() string {
+	switch k {
+	case RealTimeBout:
+		return "Real-time"
+	case SummaryBout:
+		return "Summary"
+	default:
+		return fmt.Sprintf("BoutKind(%d)", int(k))
+	}
+}
+
+// ParseBoutKind returns the kind matching a name such as "summary", empty is real-time
+func ParseBoutKind(name string) (BoutKind, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "realtime", "real-time":
+		return RealTimeBout, nil
+	case "summary":
+		return SummaryBout, nil
+	default:
+		return 0, fmt.Errorf("unknown bout of madness %q", name)
+	}
+}
+
+// MarshalText writes the kind by name
+func (k BoutKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText reads a kind by name
+func (k *BoutKind) UnmarshalText(text []byte) error {
+	kind, err := ParseBoutKind(string(text))
+	if err != nil {
+		return err
+	}
+	*k = kind
+	return nil
+}
+
+// boutEffect is what a bout does besides its story, giving the investigator a new condition
+type boutEffect int
+
+const (
+	noEffect boutEffect = iota
+	phobiaEffect
+	maniaEffect
+)
+
+// BoutEntry is one row of a bout of madness table
+type BoutEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	effect      boutEffect
+}
+
+// Bout of madness tables from the Keeper Rulebook, rolled on with 1D10. Real-time bouts last
+// 1D10 rounds, summary bouts 1D10 hours after which the investigator comes to their senses.
+var (
+	RealTimeBouts = []BoutEntry{
+		{Name: "Amnesia", Description: "The investigator has no memory of events that have taken place since they were last in a place of safety. It seems to them that one moment they were eating breakfast and the next they are facing a monster."},
+		{Name: "Psychosomatic disability", Description: "The investigator suffers psychosomatic blindness, deafness, or loss of the use of a limb or limbs."},
+		{Name: "Violence", Description: "A red mist descends on the investigator and they explode in a spree of uncontrolled violence and destruction directed at their surroundings, allies or foes alike."},
+		{Name: "Paranoia", Description: "The investigator suffers severe paranoia; everyone is out to get them, no one can be trusted, they are being spied on, someone has betrayed them, what they are seeing is a trick."},
+		{Name: "Significant person", Description: "The investigator mistakes another person in the scene for someone of significance from their backstory and acts accordingly."},
+		{Name: "Faint", Description: "The investigator faints and recovers when the bout ends."},
+		{Name: "Flee in panic", Description: "The investigator is compelled to get as far away as possible by whatever means are available, even if it means taking the only vehicle and leaving everyone else behind."},
+		{Name: "Physical hysterics or emotional outburst", Description: "The investigator is incapacitated from laughing, crying, screaming, etc."},
+		{Name: "Phobia", Description: "The investigator gains a new phobia. Even if its source is not present, they imagine it is there for the length of the bout.", effect: phobiaEffect},
+		{Name: "Mania", Description: "The investigator gains a new mania and seeks to indulge it for the length of the bout.", effect: maniaEffect},
+	}
+	SummaryBouts = []BoutEntry{
+		{Name: "Amnesia", Description: "The investigator comes to their senses in an unfamiliar place with no memory of who they are. Their memories will slowly return to them over time."},
+		{Name: "Robbed", Description: "The investigator comes to their senses having been robbed. They are unharmed. If they were carrying a treasured possession, make a Luck roll to see if it was stolen. Everything else of value is automatically missing."},
+		{Name: "Battered", Description: "The investigator comes to their senses battered and bruised. Hit points are reduced to half of what they were before going insane, though this does not cause a major wound. They have not been robbed."},
+		{Name: "Violence", Description: "The investigator explodes in a spree of violence and destruction. When they come to their senses, their actions may or may not be apparent or remembered."},
+		{Name: "Ideology/Beliefs", Description: "The investigator takes an ideology or belief from their backstory to the extreme, in a manic and demonstrative manner, such as preaching it loudly in the street."},
+		{Name: "Significant people", Description: "The investigator has gone to great lengths to get close to a significant person from their backstory, and acted on their relationship in some way."},
+		{Name: "Institutionalized", Description: "The investigator comes to their senses in a psychiatric ward or police cell. They may slowly recall the events that led them there."},
+		{Name: "Flee in panic", Description: "When the investigator comes to their senses they are far away, perhaps lost in the wilderness or on a train or long-distance bus."},
+		{Name: "Phobia", Description: "The investigator gains a new phobia. They come to their senses having taken every possible precaution to avoid its source.", effect: phobiaEffect},
+		{Name: "Mania", Description: "The investigator gains a new mania, and comes to their senses having spent the bout indulging it.", effect: maniaEffect},
+	}
+)
+
+var boutDuration = dice.MustParse("1D10")
+
+// Bout is a rolled bout of madness
+type Bout struct {
+	BoutEntry
+	Kind         BoutKind `json:"kind"`
+	Roll         int      `json:"roll"` // The 1D10 rolled on the table
+	Duration     int      `json:"duration"`
+	DurationUnit string   `json:"durationUnit"` // "rounds" for real-time bouts, "hours" for summaries
+	Phobia       *Phobia  `json:"phobia,omitempty"`
+	Mania        *Mania   `json:"mania,omitempty"`
+	Added        bool     `json:"added"` // Whether the phobia or mania was added to the investigator
+}
+
+// RollBout rolls a bout of madness for the investigator. A phobia or mania result picks one
+// the investigator does not have yet, and addCondition appends it to their sheet.
+func (i *Investigator) RollBout(r *rand.Rand, kind BoutKind, addCondition bool) Bout {
+	table, unit := RealTimeBouts, "rounds"
+	if kind == SummaryBout {
+		table, unit = SummaryBouts, "hours"
+	}
+
+	bout := Bout{Kind: kind, Roll: r.Intn(len(table)) + 1, DurationUnit: unit}
+	bout.BoutEntry = table[bout.Roll-1]
+	bout.Duration = boutDuration.Total(r)
+
+	switch bout.effect {
+	case phobiaEffect:
+		held := make(map[string]bool)
+		for _, p := range i.Phobias {
+			held[p.Name] = true
+		}
+		if name := pickUnheld(r, Phobias, held); name != "" {
+			phobia := Phobias[name]
+			bout.Phobia = &phobia
+			if addCondition {
+				i.Phobias = append(i.Phobias, phobia)
+				bout.Added = true
+			}
+		}
+	case maniaEffect:
+		held := make(map[string]bool)
+		for _, m := range i.Manias {
+			held[m.Name] = true
+		}
+		if name := pickUnheld(r, Manias, held); name != "" {
+			mania := Manias[name]
+			bout.Mania = &mania
+			if addCondition {
+				i.Manias = append(i.Manias, mania)
+				bout.Added = true
+			}
+		}
+	}
+	return bout
+}
+
+// pickUnheld picks a random name from a reference list that is not already held
+func pickUnheld[T any](r *rand.Rand, list map[string]T, held map[string]bool) string {
+	names := make([]string, 0, len(list))
+	for name := range list {
+		if !held[name] {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	slices.Sort(names)
+	return names[r.Intn(len(names))]
+}
@@ -37,12 +37,18 @@ type Occupation struct {
 		Min int
 		Max int
 	} `json:"CreditRating"`
+	Eras []Era `json:"-"` // Eras the occupation exists in, empty for every era
 }
 
 func (o *Occupation) String() string {
 	return o.Name
 }
 
+// AvailableIn reports whether the occupation can be taken in the given era
+func (o *Occupation) AvailableIn(era Era) bool {
+	return len(o.Eras) == 0 || slices.Contains(o.Eras, era)
+}
+
 func (o *Occupation) GetDescription() string {
 	// Build description using strings.Builder for efficiency
 	var desc strings.Builder
@@ -98,6 +104,30 @@ func (o *Occupation) GetDescription() string {
 }
 
 var Occupations = map[string]Occupation{
+	"Alienist": {
+		Name: "Alienist",
+		SkillRequirements: []SkillRequirement{
+			{Type: "required", Skill: "Law"},
+			{Type: "required", Skill: "Listen"},
+			{Type: "required", Skill: "Medicine"},
+			{Type: "required", Skill: "Language(Other)"},
+			{Type: "required", Skill: "Psychoanalysis"},
+			{Type: "required", Skill: "Psychology"},
+			{Type: "required", Skill: "Science(Biology)"},
+			{Type: "required", Skill: "Science(Chemistry)"},
+		},
+		SuggestedContacts: "others in the field of mental illness, medical doctors, asylum staff, the courts",
+		SkillPoints: SkillPointFormula{
+			BaseAttributes: []BaseSkillAttribute{
+				{Name: AttrEducation, Multiplier: 4},
+			},
+		},
+		CreditRating: struct {
+			Min int
+			Max int
+		}{10, 60},
+		Eras: []Era{Gaslight},
+	},
 	"Archaeologist": {
 		Name: "Archaeologist",
 		SkillRequirements: []SkillRequirement{
@@ -214,6 +244,7 @@ var Occupations = map[string]Occupation{
 			Min int
 			Max int
 		}{30, 60},
+		Eras: []Era{Twenties, Modern},
 	},
 	"Bank Robber": {
 		Name: "Bank Robber",
@@ -506,6 +537,38 @@ var Occupations = map[string]Occupation{
 			Min int
 			Max int
 		}{10, 40},
+		Eras: []Era{Twenties, Modern},
+	},
+	"Coachman": {
+		Name: "Coachman",
+		SkillRequirements: []SkillRequirement{
+			{Type: "required", Skill: "Drive Carriage"},
+			{Type: "required", Skill: "Listen"},
+			{Type: "required", Skill: "Mechanical Repair"},
+			{Type: "required", Skill: "Natural World"},
+			{Type: "required", Skill: "Navigate"},
+			{Type: "required", Skill: "Ride"},
+			{
+				Type: "choice",
+				SkillChoice: SkillChoice{
+					NumRequired: 1,
+					Skills:      []string{"Charm", "Fast Talk", "Intimidate", "Persuade"},
+				},
+			},
+			{Type: "required", Skill: "Spot Hidden"},
+		},
+		SuggestedContacts: "stablemen, other coachmen, the household and passengers of an employer",
+		SkillPoints: SkillPointFormula{
+			BaseAttributes: []BaseSkillAttribute{
+				{Name: AttrEducation, Multiplier: 2},
+				{Name: AttrDexterity, Multiplier: 2},
+			},
+		},
+		CreditRating: struct {
+			Min int
+			Max int
+		}{9, 20},
+		Eras: []Era{Gaslight},
 	},
 	"Confidence Trickster": {
 		Name: "Confidence Trickster",
@@ -543,6 +606,36 @@ var Occupations = map[string]Occupation{
 		}{10, 65},
 	},
 
+	"Consulting Detective": {
+		Name: "Consulting Detective",
+		SkillRequirements: []SkillRequirement{
+			{Type: "required", Skill: "Disguise"},
+			{Type: "required", Skill: "Law"},
+			{Type: "required", Skill: "Library Use"},
+			{
+				Type: "choice",
+				SkillChoice: SkillChoice{
+					NumRequired: 1,
+					Skills:      []string{"Charm", "Fast Talk", "Intimidate", "Persuade"},
+				},
+			},
+			{Type: "required", Skill: "Psychology"},
+			{Type: "required", Skill: "Science(Chemistry)"},
+			{Type: "required", Skill: "Spot Hidden"},
+			{Type: "required", Skill: "Track"},
+		},
+		SuggestedContacts: "police inspectors, street informants, clients of good standing, the press",
+		SkillPoints: SkillPointFormula{
+			BaseAttributes: []BaseSkillAttribute{
+				{Name: AttrEducation, Multiplier: 4},
+			},
+		},
+		CreditRating: struct {
+			Min int
+			Max int
+		}{20, 60},
+		Eras: []Era{Gaslight},
+	},
 	"Criminal": {
 		Name: "Criminal",
 		SkillRequirements: []SkillRequirement{
@@ -856,6 +949,7 @@ var Occupations = map[string]Occupation{
 			Min int
 			Max int
 		}{20, 40},
+		Eras: []Era{Twenties, Modern},
 	},
 
 	"Gambler": {
@@ -919,6 +1013,7 @@ var Occupations = map[string]Occupation{
 			Min int
 			Max int
 		}{60, 95},
+		Eras: []Era{Twenties, Modern},
 	},
 
 	"Gangster, Underling": {
@@ -950,6 +1045,7 @@ var Occupations = map[string]Occupation{
 			Min int
 			Max int
 		}{9, 20},
+		Eras: []Era{Twenties, Modern},
 	},
 
 	"Laborer": {
@@ -1191,6 +1287,7 @@ var Occupations = map[string]Occupation{
 			Min int
 			Max int
 		}{9, 30},
+		Eras: []Era{Twenties, Modern},
 	},
 
 	"Photographer": {
@@ -1613,6 +1710,7 @@ var Occupations = map[string]Occupation{
 			Min int
 			Max int
 		}{3, 10},
+		Eras: []Era{Twenties, Modern},
 	},
 
 	"Student/Intern": {
@@ -1770,3 +1868,15 @@ var OccupationsList = func() []string {
 	slices.Sort(keys)
 	return keys
 }()
+
+// OccupationsForEra returns the sorted names of the occupations available in an era
+func OccupationsForEra(era Era) []string {
+	names := make([]string, 0, len(OccupationsList))
+	for _, name := range OccupationsList {
+		occupation := Occupations[name]
+		if occupation.AvailableIn(era) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
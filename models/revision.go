@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// RevisionSectionRevert marks a revision created by reverting to an earlier revision
+const RevisionSectionRevert = "revert"
+
+// Revision records a single change applied to an investigator sheet.
+// Snapshot holds the sheet as it was before the change so it can be restored.
+type Revision struct {
+	ID             int64       `json:"id"`
+	InvestigatorID string      `json:"investigator_id"`
+	AuthorID       string      `json:"author_id"`
+	AuthorName     string      `json:"author_name,omitempty"`
+	Section        string      `json:"section"`
+	Field          string      `json:"field"`
+	OldValue       interface{} `json:"old_value"`
+	NewValue       interface{} `json:"new_value"`
+	Snapshot       []byte      `json:"-"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
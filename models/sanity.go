@@ -0,0 +1,124 @@
+package models
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"book-of-shadows/internal/dice"
+)
+
+var temporaryInsanityHours = dice.MustParse("1D10")
+
+// SanityLoss is the Sanity a horror costs, written "success/failure" such as "1/1D6" or "0/1D10"
+type SanityLoss struct {
+	source           string
+	success, failure *dice.Expression
+}
+
+// ParseSanityLoss parses a loss in "success/failure" notation
+func ParseSanityLoss(notation string) (SanityLoss, error) {
+	success, failure, found := strings.Cut(notation, "/")
+	if !found {
+		return SanityLoss{}, fmt.Errorf("sanity loss %q must be written as success/failure, e.g. 1/1D6", notation)
+	}
+	loss := SanityLoss{source: strings.TrimSpace(notation)}
+	var err error
+	if loss.success, err = dice.Parse(success); err != nil {
+		return SanityLoss{}, fmt.Errorf("sanity loss on a success: %v", err)
+	}
+	if loss.failure, err = dice.Parse(failure); err != nil {
+		return SanityLoss{}, fmt.Errorf("sanity loss on a failure: %v", err)
+	}
+	if len(loss.success.Variables())+len(loss.failure.Variables()) > 0 {
+		return SanityLoss{}, fmt.Errorf("sanity loss %q may only use dice and numbers", notation)
+	}
+	return loss, nil
+}
+
+func (l SanityLoss) String() string {
+	return l.source
+}
+
+// SanityDay tracks the Sanity lost over one game day, against which indefinite insanity is judged
+type SanityDay struct {
+	Day            string `json:"day"`
+	StartingSanity int    `json:"startingSanity"`
+	Lost           int    `json:"lost"`
+}
+
+// SanityResult is the outcome of a Sanity roll and the loss that followed
+type SanityResult struct {
+	Loss               string       `json:"loss"`
+	Check              CheckResult  `json:"check"` // The Sanity roll
+	LossRoll           dice.Result  `json:"lossRoll"`
+	Lost               int          `json:"lost"`
+	Sanity             int          `json:"sanity"` // Sanity after the loss
+	Day                SanityDay    `json:"day"`
+	IntelligenceRoll   *CheckResult `json:"intelligenceRoll,omitempty"` // Made after losing 5 or more at once
+	TemporaryInsanity  bool         `json:"temporaryInsanity"`
+	TemporaryHours     int          `json:"temporaryHours,omitempty"` // How long the temporary insanity lasts
+	IndefiniteInsanity bool         `json:"indefiniteInsanity"`
+	PermanentInsanity  bool         `json:"permanentInsanity"` // Sanity reached zero
+}
+
+// LoseSanity makes a Sanity roll and applies the loss for the outcome. A fumble loses the
+// most the failure loss can. Losing 5 or more at once calls for an INT roll, and a success
+// means the investigator grasps the horror and goes temporarily insane. Losing a fifth of
+// the Sanity they started the day with brings on indefinite insanity, and reaching zero
+// permanent insanity. Losses on a new day start a new tally and end any temporary
+// insanity left from the day before, which lasts hours at most.
+func (i *Investigator) LoseSanity(r *rand.Rand, loss SanityLoss, day string) (SanityResult, error) {
+	check, err := i.Check(r, AttrSanity, RegularDifficulty, 0, 0, false)
+	if err != nil {
+		return SanityResult{}, err
+	}
+	result := SanityResult{Loss: loss.String(), Check: check}
+
+	expression := loss.failure
+	if check.Success {
+		expression = loss.success
+	}
+	if check.Level == Fumble {
+		result.LossRoll = dice.Result{Expression: expression.String(), Total: expression.Max(), Rolls: []dice.Roll{}}
+	} else if result.LossRoll, err = expression.Roll(r, nil); err != nil {
+		return SanityResult{}, err
+	}
+
+	san := i.Attributes[AttrSanity]
+	if i.SanityDay.Day != day {
+		i.SanityDay = SanityDay{Day: day, StartingSanity: san.Value}
+		i.TemporaryInsane = false
+	}
+
+	result.Lost = max(0, min(result.LossRoll.Total, san.Value))
+	san.Value -= result.Lost
+	i.Attributes[AttrSanity] = san
+	i.SanityDay.Lost += result.Lost
+	result.Sanity = san.Value
+	result.Day = i.SanityDay
+
+	if result.Lost >= 5 {
+		intelligence, err := i.Check(r, AttrIntelligence, RegularDifficulty, 0, 0, false)
+		if err != nil {
+			return SanityResult{}, err
+		}
+		result.IntelligenceRoll = &intelligence
+		if intelligence.Success {
+			result.TemporaryInsanity = true
+			result.TemporaryHours = temporaryInsanityHours.Total(r)
+			i.TemporaryInsane = true
+		}
+	}
+
+	if result.Lost > 0 && !i.IndefiniteInsane && i.SanityDay.Lost >= max(1, i.SanityDay.StartingSanity/5) {
+		result.IndefiniteInsanity = true
+		i.IndefiniteInsane = true
+	}
+
+	if san.Value == 0 {
+		result.PermanentInsanity = true
+		i.Insane = true
+	}
+	return result, nil
+}
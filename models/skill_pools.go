@@ -0,0 +1,189 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SkillPool is one of the pools of skill points an investigator spends at creation
+type SkillPool string
+
+const (
+	PoolOccupation SkillPool = "occupation"
+	PoolArchetype  SkillPool = "archetype" // Pulp archetype bonus points
+	PoolPersonal   SkillPool = "personal"  // Personal interest points, INT×2
+)
+
+// SkillAllocations records the points each pool put into each skill
+type SkillAllocations map[SkillPool]map[string]int
+
+// SkillPools lists the pools in the order the wizard spends them
+var SkillPools = []SkillPool{PoolArchetype, PoolOccupation, PoolPersonal}
+
+// ParseSkillPool returns the pool matching a name such as "occupation". The wizard's
+// general skills spend the personal interest points.
+func ParseSkillPool(name string) (SkillPool, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "occupation":
+		return PoolOccupation, nil
+	case "archetype":
+		return PoolArchetype, nil
+	case "personal", "general", "free":
+		return PoolPersonal, nil
+	default:
+		return "", fmt.Errorf("unknown skill pool %q", name)
+	}
+}
+
+// SkillCap is the highest a skill can be raised at creation
+func (i *Investigator) SkillCap() int {
+	if i.IsPulp() {
+		return 95
+	}
+	return 90
+}
+
+// PoolTotal returns the points a pool holds
+func (i *Investigator) PoolTotal(pool SkillPool) int {
+	switch pool {
+	case PoolOccupation:
+		return i.OccupationPoints
+	case PoolArchetype:
+		return i.ArchetypePoints
+	case PoolPersonal:
+		return i.FreePoints
+	default:
+		return 0
+	}
+}
+
+// PoolSpent returns the points spent from a pool on every skill
+func (i *Investigator) PoolSpent(pool SkillPool) int {
+	spent := 0
+	for _, points := range i.SkillAllocations[pool] {
+		spent += points
+	}
+	return spent
+}
+
+// PoolRemaining returns the points a pool has left
+func (i *Investigator) PoolRemaining(pool SkillPool) int {
+	return i.PoolTotal(pool) - i.PoolSpent(pool)
+}
+
+// PoolSkills returns the skills a pool can raise, sorted by name. Occupation points go to the
+// skills of the occupation and Credit Rating, archetype points to the skills of the archetype
+// and personal interest points to any skill.
+func (i *Investigator) PoolSkills(pool SkillPool) []string {
+	var listed []string
+	switch pool {
+	case PoolOccupation:
+		listed = append(listed, "Credit Rating")
+		if i.Occupation != nil {
+			for _, req := range i.Occupation.SkillRequirements {
+				if req.Type == "required" {
+					listed = append(listed, req.Skill)
+				} else {
+					listed = append(listed, req.SkillChoice.Skills...)
+				}
+			}
+		}
+	case PoolArchetype:
+		if i.Archetype != nil {
+			listed = i.Archetype.Skills
+		}
+	}
+
+	var names []string
+	for name := range i.Skills {
+		if name == "Dodge_Copy" || name == "Cthulhu Mythos" {
+			continue
+		}
+		if pool == PoolPersonal || slices.ContainsFunc(listed, func(skill string) bool { return i.coversSkill(skill, name) }) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// coversSkill reports whether a skill an occupation or archetype lists covers the skill name of
+// the investigator. Lists name skills the way addMissingSkills fills them in: a skill the
+// investigator has covers only itself, "Firearms" covers every Firearms skill and
+// "Science(Botany)" any Science skill when the investigator has no Botany.
+func (i *Investigator) coversSkill(listed, name string) bool {
+	listed = i.Era.SkillName(listed)
+	if listed == name {
+		return true
+	}
+	if skill, ok := i.Skills[listed]; ok && skill.Base == 0 {
+		return false
+	}
+	if prefix, _, specialised := strings.Cut(listed, "("); specialised {
+		return i.Skills[name].Category == prefix || strings.HasPrefix(name, prefix+"(")
+	}
+	return strings.HasPrefix(name, listed)
+}
+
+// SyncSkillPools sets the unassigned points of every pool from what was allocated
+func (i *Investigator) SyncSkillPools() {
+	i.UnassignedOccupationPoints = i.PoolRemaining(PoolOccupation)
+	i.UnassignedArchetypePoints = i.PoolRemaining(PoolArchetype)
+	i.UnassignedFreePoints = i.PoolRemaining(PoolPersonal)
+}
+
+// AllocateSkillPoints raises a skill with points from a pool, or refunds them to it when
+// points is negative. A pool only raises the skills of PoolSkills, gets back what it put into
+// the skill, and never spends more than it has left or raises a skill above the creation cap.
+func (i *Investigator) AllocateSkillPoints(pool SkillPool, name string, points int) error {
+	if pool == PoolArchetype && i.Archetype == nil {
+		return fmt.Errorf("classic investigators have no archetype points")
+	}
+	skill, ok := i.Skills[name]
+	if !ok || name == "Dodge_Copy" {
+		return fmt.Errorf("unknown skill %q", name)
+	}
+	if name == "Cthulhu Mythos" {
+		return fmt.Errorf("%s cannot be raised with skill points", name)
+	}
+
+	allocated := i.SkillAllocations[pool][name]
+	switch {
+	case points == 0:
+		return nil
+	case points > 0 && !slices.Contains(i.PoolSkills(pool), name):
+		return fmt.Errorf("%s is not one of the skills %s points can raise", name, pool)
+	case points < 0 && -points > allocated:
+		return fmt.Errorf("only %d %s points were put into %s", allocated, pool, name)
+	case points > i.PoolRemaining(pool):
+		return fmt.Errorf("%s points left %d, %d asked for", pool, i.PoolRemaining(pool), points)
+	case points > 0 && skill.Value+points > i.SkillCap():
+		return fmt.Errorf("%s cannot be raised above %d", name, i.SkillCap())
+	}
+
+	i.recordAllocation(pool, name, points)
+	skill.Value += points
+	i.Skills[name] = skill
+	i.SyncSkillPools()
+	if name == "Credit Rating" {
+		i.SetWealth()
+	}
+	return nil
+}
+
+// recordAllocation adds points to what a pool put into a skill, forgetting the skill once the
+// pool has nothing left in it
+func (i *Investigator) recordAllocation(pool SkillPool, name string, points int) {
+	if i.SkillAllocations == nil {
+		i.SkillAllocations = make(SkillAllocations)
+	}
+	if i.SkillAllocations[pool] == nil {
+		i.SkillAllocations[pool] = make(map[string]int)
+	}
+	if allocated := i.SkillAllocations[pool][name] + points; allocated == 0 {
+		delete(i.SkillAllocations[pool], name)
+	} else {
+		i.SkillAllocations[pool][name] = allocated
+	}
+}
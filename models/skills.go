@@ -18,6 +18,28 @@ type Skill struct {
 	IsPriority   bool   `json:"IsPriority"`
 }
 
+// eraSkillSubstitutes names the skill that stands in for one an era does not have
+var eraSkillSubstitutes = map[Era]map[string]string{
+	Gaslight: {
+		"Drive Auto":   "Drive Carriage",
+		"Computer Use": "Library Use",
+		"Electronics":  "Electrical Repair",
+	},
+	Twenties: {
+		"Computer Use": "Library Use",
+		"Electronics":  "Electrical Repair",
+	},
+}
+
+// SkillName returns the skill used in place of name in this era, or name itself
+// when the era has no substitute for it
+func (e Era) SkillName(name string) string {
+	if substitute, ok := eraSkillSubstitutes[e][name]; ok {
+		return substitute
+	}
+	return name
+}
+
 func (skill *Skill) String() string {
 	return fmt.Sprintf("%s (%d)", skill.Abbreviation, skill.Value)
 }
@@ -29,7 +51,7 @@ var Skills = map[string]Skill{
 		FormName:     "Accounting",
 		Default:      5,
 		Value:        5,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -39,7 +61,7 @@ var Skills = map[string]Skill{
 		FormName:     "Anthropology",
 		Default:      1,
 		Value:        1,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -49,7 +71,7 @@ var Skills = map[string]Skill{
 		FormName:     "Appraise",
 		Default:      5,
 		Value:        5,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -59,7 +81,7 @@ var Skills = map[string]Skill{
 		FormName:     "Archaeology",
 		Default:      1,
 		Value:        1,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -69,7 +91,7 @@ var Skills = map[string]Skill{
 		FormName:     "ArtCraft1", // supports 1 more ArtCraft2
 		Default:      5,
 		Value:        5,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         1,
 		NeedsFormDef: 1,
 		IsSelected:   false,
@@ -80,7 +102,7 @@ var Skills = map[string]Skill{
 		FormName:     "Charm",
 		Default:      15,
 		Value:        15,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -90,7 +112,7 @@ var Skills = map[string]Skill{
 		FormName:     "Climb",
 		Default:      20,
 		Value:        20,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -110,7 +132,7 @@ var Skills = map[string]Skill{
 		FormName:     "Credit",
 		Default:      0,
 		Value:        0,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -120,7 +142,7 @@ var Skills = map[string]Skill{
 		FormName:     "Cthulhu",
 		Default:      0,
 		Value:        0,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -130,7 +152,7 @@ var Skills = map[string]Skill{
 		FormName:     "Disguise",
 		Default:      5,
 		Value:        5,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -144,13 +166,23 @@ var Skills = map[string]Skill{
 		Base:         0,
 		IsSelected:   false,
 	},
+	"Drive Carriage": {
+		Name:         "Drive Carriage",
+		Abbreviation: "Drive Carriage",
+		FormName:     "Drive",
+		Default:      20,
+		Value:        20,
+		Era:          []Era{Gaslight},
+		Base:         0,
+		IsSelected:   false,
+	},
 	"Electrical Repair": {
 		Name:         "Electrical Repair",
 		Abbreviation: "Elec. Repair",
 		FormName:     "ElecRepair",
 		Default:      10,
 		Value:        10,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -170,7 +202,7 @@ var Skills = map[string]Skill{
 		FormName:     "FastTalk ",
 		Default:      5,
 		Value:        5,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -180,7 +212,7 @@ var Skills = map[string]Skill{
 		FormName:     "Fighting",
 		Default:      25,
 		Value:        25,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		Category:     "Fighting",
 		IsSelected:   false,
@@ -191,7 +223,7 @@ var Skills = map[string]Skill{
 		FormName:     "Fighting1",
 		Default:      1,
 		Value:        1,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         1,
 		NeedsFormDef: 1,
 		IsSelected:   false,
@@ -202,7 +234,7 @@ var Skills = map[string]Skill{
 		FormName:     "Firearms",
 		Default:      1,
 		Value:        1,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         1,
 		NeedsFormDef: 1,
 		IsSelected:   false,
@@ -213,7 +245,7 @@ var Skills = map[string]Skill{
 		FormName:     "FirearmsHandguns",
 		Default:      20,
 		Value:        20,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		Category:     "Firearms",
 		IsSelected:   false,
@@ -224,7 +256,7 @@ var Skills = map[string]Skill{
 		FormName:     "FirearmsRifles",
 		Default:      25,
 		Value:        25,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		Category:     "Firearms",
 		IsSelected:   false,
@@ -235,7 +267,7 @@ var Skills = map[string]Skill{
 		FormName:     "FirstAid",
 		Default:      30,
 		Value:        30,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -245,7 +277,7 @@ var Skills = map[string]Skill{
 		FormName:     "History",
 		Default:      5,
 		Value:        5,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -255,7 +287,7 @@ var Skills = map[string]Skill{
 		FormName:     "Intimidate",
 		Default:      15,
 		Value:        15,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -265,7 +297,7 @@ var Skills = map[string]Skill{
 		FormName:     "Jump",
 		Default:      20,
 		Value:        20,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -275,7 +307,7 @@ var Skills = map[string]Skill{
 		FormName:     "OtherLanguage", // holds up to 3
 		Default:      5,
 		Value:        5,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         1,
 		NeedsFormDef: 1,
 		IsSelected:   false,
@@ -286,7 +318,7 @@ var Skills = map[string]Skill{
 		FormName:     "Law",
 		Default:      5,
 		Value:        5,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -296,7 +328,7 @@ var Skills = map[string]Skill{
 		FormName:     "Library",
 		Default:      20,
 		Value:        20,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -306,7 +338,7 @@ var Skills = map[string]Skill{
 		FormName:     "Listen",
 		Default:      20,
 		Value:        20,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -316,7 +348,7 @@ var Skills = map[string]Skill{
 		FormName:     "Locksmith",
 		Default:      1,
 		Value:        1,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -326,7 +358,7 @@ var Skills = map[string]Skill{
 		FormName:     "MechRepair",
 		Default:      10,
 		Value:        10,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -336,7 +368,7 @@ var Skills = map[string]Skill{
 		FormName:     "Medicine",
 		Default:      1,
 		Value:        1,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -346,7 +378,7 @@ var Skills = map[string]Skill{
 		FormName:     "NaturalWorld",
 		Default:      10,
 		Value:        10,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -356,7 +388,7 @@ var Skills = map[string]Skill{
 		FormName:     "Occult",
 		Default:      5,
 		Value:        5,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -366,7 +398,7 @@ var Skills = map[string]Skill{
 		FormName:     "Persuade",
 		Default:      10,
 		Value:        10,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -376,7 +408,7 @@ var Skills = map[string]Skill{
 		FormName:     "Pilot",
 		Default:      1,
 		Value:        1,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         1,
 		NeedsFormDef: 1,
 		IsSelected:   false,
@@ -387,7 +419,7 @@ var Skills = map[string]Skill{
 		FormName:     "Psychoanalysis",
 		Default:      1,
 		Value:        1,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -397,7 +429,7 @@ var Skills = map[string]Skill{
 		FormName:     "Psyschology", // Note: Original key has a typo
 		Default:      10,
 		Value:        10,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -407,7 +439,7 @@ var Skills = map[string]Skill{
 		FormName:     "Ride",
 		Default:      5,
 		Value:        5,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -417,7 +449,7 @@ var Skills = map[string]Skill{
 		FormName:     "Science1",
 		Default:      1,
 		Value:        1,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         1,
 		NeedsFormDef: 1, // Can hold up to 3  Science, Science1, ...2, IsSelected: false,
 	},
@@ -427,7 +459,7 @@ var Skills = map[string]Skill{
 		FormName:     "Sleight",
 		Default:      10,
 		Value:        10,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -437,7 +469,7 @@ var Skills = map[string]Skill{
 		FormName:     "SpotHidden",
 		Default:      25,
 		Value:        25,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -447,7 +479,7 @@ var Skills = map[string]Skill{
 		FormName:     "Stealth",
 		Default:      20,
 		Value:        20,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -457,7 +489,7 @@ var Skills = map[string]Skill{
 		FormName:     "Survival",
 		Default:      10,
 		Value:        10,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         1,
 		NeedsFormDef: 1,
 		IsSelected:   false,
@@ -468,7 +500,7 @@ var Skills = map[string]Skill{
 		FormName:     "Swim",
 		Default:      20,
 		Value:        20,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -478,7 +510,7 @@ var Skills = map[string]Skill{
 		FormName:     "Throw",
 		Default:      20,
 		Value:        20,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -488,7 +520,7 @@ var Skills = map[string]Skill{
 		FormName:     "Track",
 		Default:      10,
 		Value:        10,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
@@ -498,7 +530,7 @@ var Skills = map[string]Skill{
 		FormName:     "Navigate",
 		Default:      10,
 		Value:        10,
-		Era:          []Era{Twenties, Modern},
+		Era:          []Era{Gaslight, Twenties, Modern},
 		Base:         0,
 		IsSelected:   false,
 	},
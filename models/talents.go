@@ -1,5 +1,7 @@
 package models
 
+import "slices"
+
 type TalentType int
 
 const (
@@ -231,5 +233,6 @@ var TalentsList = func() []string {
 	for k := range Talents {
 		keys = append(keys, k)
 	}
+	slices.Sort(keys)
 	return keys
 }()
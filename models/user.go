@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// User is a registered player account that owns investigators
+type User struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
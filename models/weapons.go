@@ -0,0 +1,160 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"book-of-shadows/internal/dice"
+)
+
+// Weapon is a weapon from the catalogue or one an investigator carries. Damage is a dice
+// expression that may add the wielder's damage bonus as DB.
+type Weapon struct {
+	ID          string `json:"id,omitempty"` // Set once the weapon is in an investigator's inventory
+	Name        string `json:"name"`
+	Skill       string `json:"skill"`
+	Damage      string `json:"damage"`
+	Range       string `json:"range"`
+	Attacks     string `json:"attacks"`               // Attacks per round
+	Ammo        string `json:"ammo"`                  // Rounds in the gun, "-" for melee weapons
+	Malfunction int    `json:"malfunction,omitempty"` // Rolls of this or more jam the weapon, 0 if it never does
+	Eras        []Era  `json:"-"`
+}
+
+// AvailableIn reports whether the weapon can be had in an era
+func (w Weapon) AvailableIn(era Era) bool {
+	if len(w.Eras) == 0 {
+		return true
+	}
+	for _, e := range w.Eras {
+		if e == era {
+			return true
+		}
+	}
+	return false
+}
+
+// Gear is a possession an investigator carries other than a weapon
+type Gear struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+	Notes    string `json:"notes,omitempty"`
+}
+
+var allEras = []Era{Gaslight, Twenties, Modern}
+
+// Weapons is the catalogue of weapons from the Investigator Handbook weapons table
+var Weapons = []Weapon{
+	// Melee
+	{Name: "Brass knuckles", Skill: "Fighting(Brawl)", Damage: "1D3+1+DB", Range: "Touch", Attacks: "1", Ammo: "-", Eras: allEras},
+	{Name: "Knife, small", Skill: "Fighting(Brawl)", Damage: "1D4+DB", Range: "Touch", Attacks: "1", Ammo: "-", Eras: allEras},
+	{Name: "Knife, medium", Skill: "Fighting(Brawl)", Damage: "1D4+2+DB", Range: "Touch", Attacks: "1", Ammo: "-", Eras: allEras},
+	{Name: "Club, small (cosh)", Skill: "Fighting(Brawl)", Damage: "1D6+DB", Range: "Touch", Attacks: "1", Ammo: "-", Eras: allEras},
+	{Name: "Club, large (cricket bat)", Skill: "Fighting(Brawl)", Damage: "1D8+DB", Range: "Touch", Attacks: "1", Ammo: "-", Eras: allEras},
+	{Name: "Hatchet", Skill: "Fighting(Axe)", Damage: "1D6+1+DB", Range: "Touch", Attacks: "1", Ammo: "-", Eras: allEras},
+	{Name: "Sword, heavy (cavalry sabre)", Skill: "Fighting(Sword)", Damage: "1D8+1+DB", Range: "Touch", Attacks: "1", Ammo: "-", Eras: allEras},
+	{Name: "Chainsaw", Skill: "Fighting(Chainsaw)", Damage: "2D8", Range: "Touch", Attacks: "1", Ammo: "-", Malfunction: 95, Eras: []Era{Modern}},
+	// Thrown
+	{Name: "Throwing knife", Skill: "Throw", Damage: "1D4+DB/2", Range: "STR feet", Attacks: "1", Ammo: "-", Eras: allEras},
+	{Name: "Dynamite stick", Skill: "Throw", Damage: "4D10", Range: "STR/5 yards", Attacks: "1/2", Ammo: "One use", Malfunction: 99, Eras: allEras},
+	{Name: "Hand grenade", Skill: "Throw", Damage: "4D10", Range: "STR/5 yards", Attacks: "1/2", Ammo: "One use", Malfunction: 99, Eras: []Era{Twenties, Modern}},
+	{Name: "Molotov cocktail", Skill: "Throw", Damage: "2D6", Range: "STR/5 yards", Attacks: "1/2", Ammo: "One use", Malfunction: 95, Eras: []Era{Modern}},
+	// Handguns
+	{Name: "Derringer (.41)", Skill: "Firearms(Handgun)", Damage: "1D8", Range: "3 yards", Attacks: "1", Ammo: "1", Malfunction: 100, Eras: []Era{Gaslight, Twenties}},
+	{Name: ".32 revolver", Skill: "Firearms(Handgun)", Damage: "1D8", Range: "15 yards", Attacks: "1 (3)", Ammo: "6", Malfunction: 100, Eras: allEras},
+	{Name: ".38 revolver", Skill: "Firearms(Handgun)", Damage: "1D10", Range: "15 yards", Attacks: "1 (3)", Ammo: "6", Malfunction: 100, Eras: allEras},
+	{Name: ".22 short automatic", Skill: "Firearms(Handgun)", Damage: "1D6", Range: "10 yards", Attacks: "1 (3)", Ammo: "6", Malfunction: 100, Eras: []Era{Twenties, Modern}},
+	{Name: ".45 automatic", Skill: "Firearms(Handgun)", Damage: "1D10+2", Range: "15 yards", Attacks: "1 (3)", Ammo: "7", Malfunction: 100, Eras: []Era{Twenties, Modern}},
+	{Name: "9mm automatic (Glock 17)", Skill: "Firearms(Handgun)", Damage: "1D10", Range: "15 yards", Attacks: "1 (3)", Ammo: "17", Malfunction: 98, Eras: []Era{Modern}},
+	// Rifles and shotguns
+	{Name: ".30 lever-action carbine", Skill: "Firearms(Rifle/Shotgun)", Damage: "2D6", Range: "50 yards", Attacks: "1", Ammo: "6", Malfunction: 98, Eras: allEras},
+	{Name: ".303 Lee-Enfield", Skill: "Firearms(Rifle/Shotgun)", Damage: "2D6+4", Range: "110 yards", Attacks: "1", Ammo: "10", Malfunction: 100, Eras: allEras},
+	{Name: ".30-06 bolt-action rifle", Skill: "Firearms(Rifle/Shotgun)", Damage: "2D6+4", Range: "110 yards", Attacks: "1/2", Ammo: "5", Malfunction: 100, Eras: []Era{Twenties, Modern}},
+	{Name: "12-gauge shotgun (2B)", Skill: "Firearms(Rifle/Shotgun)", Damage: "4D6", Range: "10 yards (2D6 at 20, 1D6 at 50)", Attacks: "1 or 2", Ammo: "2", Malfunction: 100, Eras: allEras},
+	{Name: "AK-47", Skill: "Firearms(Rifle/Shotgun)", Damage: "2D6+1", Range: "100 yards", Attacks: "1 (2) or full auto", Ammo: "30", Malfunction: 100, Eras: []Era{Modern}},
+	// Submachine guns
+	{Name: "Thompson submachine gun", Skill: "Firearms(Submachine Gun)", Damage: "1D10+2", Range: "20 yards", Attacks: "1 or full auto", Ammo: "20/30/50", Malfunction: 96, Eras: []Era{Twenties, Modern}},
+}
+
+// weaponSkillBases are the base chances of weapon skills investigators do not start with
+var weaponSkillBases = map[string]int{
+	"Fighting(Axe)":            15,
+	"Fighting(Sword)":          20,
+	"Fighting(Chainsaw)":       10,
+	"Firearms(Submachine Gun)": 15,
+}
+
+// WeaponsForEra returns the catalogue weapons that can be had in an era
+func WeaponsForEra(era Era) []Weapon {
+	weapons := make([]Weapon, 0, len(Weapons))
+	for _, w := range Weapons {
+		if w.AvailableIn(era) {
+			weapons = append(weapons, w)
+		}
+	}
+	return weapons
+}
+
+// FindWeapon returns the catalogue weapon with the given name, ignoring case
+func FindWeapon(name string) (Weapon, bool) {
+	for _, w := range Weapons {
+		if strings.EqualFold(w.Name, strings.TrimSpace(name)) {
+			return w, true
+		}
+	}
+	return Weapon{}, false
+}
+
+// WeaponSkill returns the investigator's chance with a weapon, the base chance of its skill
+// when they have not learned it
+func (i *Investigator) WeaponSkill(w Weapon) int {
+	if skill, ok := i.Skills[w.Skill]; ok {
+		return skill.Value
+	}
+	if base, ok := weaponSkillBases[w.Skill]; ok {
+		return base
+	}
+	return 0
+}
+
+// FindWeapon returns the index of the weapon with the given ID in the investigator's inventory
+func (i *Investigator) FindWeapon(id string) (int, error) {
+	for n, w := range i.Weapons {
+		if w.ID == id {
+			return n, nil
+		}
+	}
+	return -1, fmt.Errorf("no weapon %q in the inventory", id)
+}
+
+// FindGear returns the index of the gear with the given ID in the investigator's inventory
+func (i *Investigator) FindGear(id string) (int, error) {
+	for n, g := range i.Gear {
+		if g.ID == id {
+			return n, nil
+		}
+	}
+	return -1, fmt.Errorf("no gear %q in the inventory", id)
+}
+
+// Validate checks that a weapon has a name and a damage expression the dice roller can roll
+// with the wielder's damage bonus
+func (w Weapon) Validate() error {
+	if strings.TrimSpace(w.Name) == "" {
+		return fmt.Errorf("weapon needs a name")
+	}
+	damage, err := dice.Parse(w.Damage)
+	if err != nil {
+		return fmt.Errorf("weapon damage: %v", err)
+	}
+	for _, name := range damage.Variables() {
+		if name != "DB" {
+			return fmt.Errorf("weapon damage may only name the damage bonus DB, not %s", name)
+		}
+	}
+	if w.Malfunction < 0 || w.Malfunction > 100 {
+		return fmt.Errorf("weapon malfunction must be between 1 and 100, or 0 for none")
+	}
+	return nil
+}
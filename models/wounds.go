@@ -0,0 +1,136 @@
+package models
+
+import (
+	"fmt"
+	"math/rand"
+
+	"book-of-shadows/internal/dice"
+)
+
+// pulpMajorWoundLuck is the Luck a Pulp hero spends to shrug off a major wound
+const pulpMajorWoundLuck = 5
+
+var medicineRoll = dice.MustParse("1D3")
+
+// DamageResult is the outcome of one hit taken by an investigator
+type DamageResult struct {
+	Damage           int          `json:"damage"`
+	HP               int          `json:"hp"` // Hit points after the hit
+	MaxHP            int          `json:"maxHp"`
+	MajorWound       bool         `json:"majorWound"`                 // The hit dealt half maximum HP or more
+	LuckSpent        int          `json:"luckSpent,omitempty"`        // Luck a Pulp hero spent to ignore the major wound
+	ConstitutionRoll *CheckResult `json:"constitutionRoll,omitempty"` // Made to stay conscious after a major wound
+	Unconscious      bool         `json:"unconscious"`
+	Dying            bool         `json:"dying"`
+	Killed           bool         `json:"killed"` // The hit dealt more than maximum HP
+}
+
+// TakeDamage applies one hit. A hit of half maximum HP or more is a major wound, and the
+// investigator must make a CON roll or fall unconscious. Pulp heroes with spendLuck and
+// enough Luck spend 5 to ignore the major wound. At zero HP the investigator falls
+// unconscious, and is dying if they have a major wound. A hit of more than maximum HP kills.
+func (i *Investigator) TakeDamage(r *rand.Rand, damage int, spendLuck bool) (DamageResult, error) {
+	hp := i.Attributes[AttrHitPoints]
+	result := DamageResult{Damage: max(0, damage), MaxHP: hp.MaxValue}
+
+	hp.Value = max(0, hp.Value-result.Damage)
+	i.Attributes[AttrHitPoints] = hp
+	result.HP = hp.Value
+
+	if result.Damage > hp.MaxValue {
+		result.Killed = true
+		i.Dying, i.Unconscious = true, true
+		result.Dying, result.Unconscious = true, true
+		return result, nil
+	}
+
+	if result.Damage > 0 && result.Damage*2 >= hp.MaxValue {
+		luck := i.Attributes[AttrLuck]
+		if spendLuck && i.IsPulp() && luck.Value >= pulpMajorWoundLuck {
+			luck.Value -= pulpMajorWoundLuck
+			i.Attributes[AttrLuck] = luck
+			result.LuckSpent = pulpMajorWoundLuck
+		} else {
+			result.MajorWound = true
+			i.MajorWound = true
+			if hp.Value > 0 {
+				con, err := i.Check(r, AttrConstitution, RegularDifficulty, 0, 0, false)
+				if err != nil {
+					return DamageResult{}, err
+				}
+				result.ConstitutionRoll = &con
+				if !con.Success {
+					i.Unconscious = true
+				}
+			}
+		}
+	}
+
+	if hp.Value == 0 {
+		i.Unconscious = true
+		if i.MajorWound {
+			i.Dying = true
+		}
+	}
+	result.Unconscious, result.Dying = i.Unconscious, i.Dying
+	return result, nil
+}
+
+// HealResult is the outcome of a First Aid or Medicine roll on an investigator
+type HealResult struct {
+	Skill      string      `json:"skill"`
+	Check      CheckResult `json:"check"` // The healer's roll
+	Healed     int         `json:"healed"`
+	HP         int         `json:"hp"` // Hit points after healing
+	MaxHP      int         `json:"maxHp"`
+	Stabilized bool        `json:"stabilized"` // A dying investigator was stabilized
+	Woken      bool        `json:"woken"`      // An unconscious investigator came round
+	MajorWound bool        `json:"majorWound"` // Whether the investigator still has a major wound
+}
+
+// Heal has healer treat the investigator with First Aid or Medicine. A successful First Aid
+// roll restores 1 HP and stabilizes a dying investigator or wakes an unconscious one. A
+// successful Medicine roll restores 1D3 HP, but a dying investigator needs First Aid first.
+// Healing back to half maximum HP or more ends a major wound. The healer's skill is ticked
+// for improvement on a success.
+func (i *Investigator) Heal(r *rand.Rand, skill string, healer *Investigator) (HealResult, error) {
+	if skill != "First Aid" && skill != "Medicine" {
+		return HealResult{}, fmt.Errorf("%q is not a healing skill, use First Aid or Medicine", skill)
+	}
+	if skill == "Medicine" && i.Dying {
+		return HealResult{}, fmt.Errorf("a dying investigator must be stabilized with First Aid before Medicine")
+	}
+
+	check, err := healer.Check(r, skill, RegularDifficulty, 0, 0, true)
+	if err != nil {
+		return HealResult{}, err
+	}
+	hp := i.Attributes[AttrHitPoints]
+	result := HealResult{Skill: skill, Check: check, HP: hp.Value, MaxHP: hp.MaxValue}
+
+	if check.Success {
+		healing := 1
+		if skill == "Medicine" {
+			healing = medicineRoll.Total(r)
+		}
+		result.Healed = min(healing, hp.MaxValue-hp.Value)
+		hp.Value += result.Healed
+		i.Attributes[AttrHitPoints] = hp
+		result.HP = hp.Value
+
+		if skill == "First Aid" {
+			if i.Dying {
+				i.Dying = false
+				result.Stabilized = true
+			} else if i.Unconscious {
+				i.Unconscious = false
+				result.Woken = true
+			}
+		}
+		if i.MajorWound && hp.Value*2 >= hp.MaxValue {
+			i.MajorWound = false
+		}
+	}
+	result.MajorWound = i.MajorWound
+	return result, nil
+}
@@ -1,2 +0,0 @@
-"""Index interaction code
-"""
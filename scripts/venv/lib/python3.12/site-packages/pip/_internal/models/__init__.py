@@ -1,2 +0,0 @@
-"""A package that contains models that represent entities.
-"""
@@ -1,2 +0,0 @@
-"""Contains purely network-related utilities.
-"""
@@ -1,2 +0,0 @@
-"""For modules related to installing packages.
-"""
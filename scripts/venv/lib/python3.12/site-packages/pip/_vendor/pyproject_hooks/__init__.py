@@ -1,23 +0,0 @@
-"""Wrappers to call pyproject.toml-based build backend hooks.
-"""
-
-from ._impl import (
-    BackendInvalid,
-    BackendUnavailable,
-    BuildBackendHookCaller,
-    HookMissing,
-    UnsupportedOperation,
-    default_subprocess_runner,
-    quiet_subprocess_runner,
-)
-
-__version__ = '1.0.0'
-__all__ = [
-    'BackendUnavailable',
-    'BackendInvalid',
-    'HookMissing',
-    'UnsupportedOperation',
-    'default_subprocess_runner',
-    'quiet_subprocess_runner',
-    'BuildBackendHookCaller',
-]
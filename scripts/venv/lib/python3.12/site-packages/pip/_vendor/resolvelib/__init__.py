@@ -1,26 +0,0 @@
-__all__ = [
-    "__version__",
-    "AbstractProvider",
-    "AbstractResolver",
-    "BaseReporter",
-    "InconsistentCandidate",
-    "Resolver",
-    "RequirementsConflicted",
-    "ResolutionError",
-    "ResolutionImpossible",
-    "ResolutionTooDeep",
-]
-
-__version__ = "1.0.1"
-
-
-from .providers import AbstractProvider, AbstractResolver
-from .reporters import BaseReporter
-from .resolvers import (
-    InconsistentCandidate,
-    RequirementsConflicted,
-    ResolutionError,
-    ResolutionImpossible,
-    ResolutionTooDeep,
-    Resolver,
-)
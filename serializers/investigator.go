@@ -61,20 +61,40 @@ type InvestigatorSerializer struct {
 	CurrentMagic    string `json:"CurrentMagic"`
 	CurrentSanity   string `json:"CurrentSanity"`
 	CurrentLuck     string `json:"CurrentLuck"`
+	StartingHP      string `json:"StartingHP"`
+	StartingMagic   string `json:"StartingMagic"`
+	StartingLuck    string `json:"StartingLuck"`
+	StartingSanity  string `json:"StartingSanity"`
+	MaxSanity       string `json:"MaxSanity"`
+	PhobiasManias   string `json:"Phobias/Manias"`
 
 	// All skills will be handled dynamically in the conversion methods
 	// using map[string]string to store all Skill_* fields
 	Skills map[string]string `json:"-"`
+	// SkillDefs holds the specialisations written next to the open skill slots (SkillDef_*)
+	SkillDefs map[string]string `json:"-"`
 }
 
-// Helper method to convert string to boolean
+// NewInvestigatorSerializer creates a serializer from character sheet field values,
+// such as the form fields of a filled PDF
+func NewInvestigatorSerializer(values map[string]string) *InvestigatorSerializer {
+	s := &InvestigatorSerializer{}
+	s.setFields(values)
+	return s
+}
+
+// Helper method to convert string to boolean, accepting ticked PDF check boxes
 func strToBool(s string) bool {
-	return strings.ToLower(s) == "true"
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "1", "yes", "on":
+		return true
+	}
+	return false
 }
 
 // Helper method to convert string to int with fallback
 func strToInt(s string) int {
-	val, err := strconv.Atoi(s)
+	val, err := strconv.Atoi(strings.TrimSpace(s))
 	if err != nil {
 		return 0
 	}
@@ -90,15 +110,24 @@ func (s *InvestigatorSerializer) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	// Initialize the skills map
+	s.setFields(rawData)
+	return nil
+}
+
+// setFields assigns the character sheet fields to the serializer
+func (s *InvestigatorSerializer) setFields(rawData map[string]string) {
+	// Initialize the skills maps
 	s.Skills = make(map[string]string)
+	s.SkillDefs = make(map[string]string)
 
 	// Process each field
 	for key, value := range rawData {
 		switch {
-		case strings.HasPrefix(key, "Skill_"):
-			// Store all skill-related fields
+		case strings.HasPrefix(key, "Skill_"), key == "Dodge_Copy":
+			// Store all skill-related fields, including the Dodge copy in the combat section
 			s.Skills[key] = value
+		case strings.HasPrefix(key, "SkillDef_"):
+			s.SkillDefs[strings.TrimPrefix(key, "SkillDef_")] = value
 		default:
 			// Handle other fields using reflection or manual assignment
 			switch key {
@@ -124,6 +153,20 @@ func (s *InvestigatorSerializer) UnmarshalJSON(data []byte) error {
 				s.PulpTalents = value
 			case "Pulp Talents Descriptions":
 				s.PulpTalentsDescriptions = value
+			case "Phobias/Manias":
+				s.PhobiasManias = value
+			case "insane":
+				s.Insane = value
+			case "TempInsanity_Chk", "TempInsanity_Chk Off":
+				s.TemporaryInsane = value
+			case "IndefInsanity_Chk":
+				s.IndefiniteInsane = value
+			case "MajorWound_Chk":
+				s.MajorWound = value
+			case "Unconscious_Chk":
+				s.Unconscious = value
+			case "Dying_Chk":
+				s.Dying = value
 			case "STR":
 				s.STR = value
 			case "DEX":
@@ -148,17 +191,28 @@ func (s *InvestigatorSerializer) UnmarshalJSON(data []byte) error {
 				s.CurrentMagic = value
 			case "CurrentSanity":
 				s.CurrentSanity = value
+			case "StartingHP":
+				s.StartingHP = value
+			case "StartingMagic":
+				s.StartingMagic = value
+			case "StartingLuck":
+				s.StartingLuck = value
+			case "StartingSanity":
+				s.StartingSanity = value
+			case "MaxSanity":
+				s.MaxSanity = value
 				// Add other fields as needed
 			}
 		}
 	}
-
-	return nil
 }
 
-// ToInvestigator converts the serializer to an Investigator domain model
+// ToInvestigator converts the serializer to an Investigator domain model.
+// Values that are missing from the sheet fall back to the catalog defaults.
 func (s *InvestigatorSerializer) ToInvestigator() *models.Investigator {
 	inv := &models.Investigator{
+		Era:              models.Modern,
+		GameMode:         models.Pulp,
 		Name:             s.Name,
 		Residence:        s.Residence,
 		Birthplace:       s.Birthplace,
@@ -166,8 +220,6 @@ func (s *InvestigatorSerializer) ToInvestigator() *models.Investigator {
 		Move:             strToInt(s.MOV),
 		Build:            s.Build,
 		DamageBonus:      s.DamageBonus,
-		Occupation:       &models.Occupation{Name: s.Occupation},
-		Archetype:        &models.Archetype{Name: s.Archetype},
 		Insane:           strToBool(s.Insane),
 		TemporaryInsane:  strToBool(s.TemporaryInsane),
 		IndefiniteInsane: strToBool(s.IndefiniteInsane),
@@ -180,47 +232,70 @@ func (s *InvestigatorSerializer) ToInvestigator() *models.Investigator {
 		Skills:     make(map[string]models.Skill),
 	}
 
-	// Convert attributes
-	attributeMap := map[string]string{
-		"STR":                s.STR,
-		"DEX":                s.DEX,
-		"POW":                s.POW,
-		"CON":                s.CON,
-		"APP":                s.APP,
-		"EDU":                s.EDU,
-		"SIZ":                s.SIZ,
-		"INT":                s.INT,
-		"CurrentHitPoints":   s.CurrentHP,
-		"CurrentMagicPoints": s.CurrentMagic,
-		"CurrentSanity":      s.CurrentSanity,
-		"CurrentLuck":        s.CurrentLuck,
+	// Occupation and archetype come from the catalogs when the sheet uses a known name
+	occupation, ok := models.Occupations[s.Occupation]
+	if !ok {
+		occupation = models.Occupation{Name: s.Occupation}
 	}
-
-	for key, value := range attributeMap {
-		if strings.HasPrefix(key, "Current") {
-			inv.Attributes[strings.Replace(key, "Current", "", 1)] = models.Attribute{Name: key, Value: strToInt(value)}
-		} else {
-			inv.Attributes[key] = models.Attribute{Name: key, Value: strToInt(value)}
+	inv.Occupation = &occupation
+	// Only Pulp sheets fill in the archetype and talents
+	if s.Archetype == "" && s.PulpTalents == "" {
+		inv.GameMode = models.Classic
+	} else {
+		archetype, ok := models.Archetypes[s.Archetype]
+		if !ok {
+			archetype = models.Archetype{Name: s.Archetype}
 		}
+		inv.Archetype = &archetype
 	}
 
-	// Convert skills
-	for key, value := range s.Skills {
-		if strings.HasPrefix(key, "Skill_") && !strings.HasSuffix(key, "_half") && !strings.HasSuffix(key, "_fifth") {
-			skillName := strings.TrimPrefix(key, "Skill_")
-			inv.Skills[skillName] = models.Skill{
-				Name:  skillName,
-				Value: strToInt(value),
-			}
-		}
+	s.setAttributes(inv)
+	s.setSkills(inv)
+
+	// Derived values are recalculated when the sheet leaves them empty
+	if inv.Move == 0 {
+		inv.SetMovement()
+	}
+	if inv.Build == "" && inv.DamageBonus == "" {
+		inv.SetBuildAndDMG()
 	}
+	inv.SetWealth()
+
 	// Convert Pulp Talents
 	if s.PulpTalents != "" {
 		talents := strings.Split(strings.TrimSuffix(s.PulpTalents, ", "), ", ")
-		description := strings.Split(strings.TrimSuffix(s.PulpTalentsDescriptions, "~ "), "~ ")
-		for i, t := range talents {
-			if t != "" {
-				inv.Talents = append(inv.Talents, models.Talent{Name: t, Description: description[i]})
+		descriptions := strings.Split(strings.TrimSuffix(s.PulpTalentsDescriptions, "~ "), "~ ")
+		for i, name := range talents {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			talent, ok := models.Talents[name]
+			if !ok {
+				talent = models.Talent{Name: name}
+				if i < len(descriptions) {
+					talent.Description = descriptions[i]
+				}
+			}
+			inv.Talents = append(inv.Talents, talent)
+		}
+	}
+
+	// Phobias and manias share a field written as "phobias | manias"
+	for i, part := range strings.Split(s.PhobiasManias, "|") {
+		for _, name := range strings.Split(part, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if phobia, ok := models.Phobias[name]; ok {
+				inv.Phobias = append(inv.Phobias, phobia)
+			} else if mania, ok := models.Manias[name]; ok {
+				inv.Manias = append(inv.Manias, mania)
+			} else if i == 0 {
+				inv.Phobias = append(inv.Phobias, models.Phobia{Name: name})
+			} else {
+				inv.Manias = append(inv.Manias, models.Mania{Name: name})
 			}
 		}
 	}
@@ -228,6 +303,136 @@ func (s *InvestigatorSerializer) ToInvestigator() *models.Investigator {
 	return inv
 }
 
+// setAttributes converts the characteristics and the HP, MP, Luck and Sanity boxes
+func (s *InvestigatorSerializer) setAttributes(inv *models.Investigator) {
+	characteristics := []struct {
+		key   string
+		name  string
+		value string
+	}{
+		{models.AttrStrength, "STR", s.STR},
+		{models.AttrConstitution, "CON", s.CON},
+		{models.AttrDexterity, "DEX", s.DEX},
+		{models.AttrIntelligence, "INT", s.INT},
+		{models.AttrSize, "SIZ", s.SIZ},
+		{models.AttrPower, "POW", s.POW},
+		{models.AttrAppearance, "APP", s.APP},
+		{models.AttrEducation, "EDU", s.EDU},
+	}
+	for _, c := range characteristics {
+		value := strToInt(c.value)
+		inv.Attributes[c.key] = models.Attribute{Name: c.name, StartingValue: value, Value: value}
+	}
+
+	maxSanity := strToInt(s.MaxSanity)
+	if maxSanity == 0 {
+		maxSanity = 99 // Max sanity is 99 in Call of Cthulhu
+	}
+	inv.Attributes[models.AttrHitPoints] = models.Attribute{
+		Name:          "CurrentHP",
+		StartingValue: strToInt(s.StartingHP),
+		Value:         strToInt(s.CurrentHP),
+		MaxValue:      strToInt(s.StartingHP),
+	}
+	inv.Attributes[models.AttrMagicPoints] = models.Attribute{
+		Name:          "CurrentMagic",
+		StartingValue: strToInt(s.StartingMagic),
+		Value:         strToInt(s.CurrentMagic),
+		MaxValue:      strToInt(s.StartingMagic),
+	}
+	inv.Attributes[models.AttrLuck] = models.Attribute{
+		Name:          "CurrentLuck",
+		StartingValue: strToInt(s.StartingLuck),
+		Value:         strToInt(s.CurrentLuck),
+	}
+	inv.Attributes[models.AttrSanity] = models.Attribute{
+		Name:          "CurrentSanity",
+		StartingValue: strToInt(s.StartingSanity),
+		Value:         strToInt(s.CurrentSanity),
+		MaxValue:      maxSanity,
+	}
+}
+
+// setSkills fills the era's skill list with the values written on the sheet
+func (s *InvestigatorSerializer) setSkills(inv *models.Investigator) {
+	inv.GetSkills()
+
+	dex := inv.Attributes[models.AttrDexterity].Value
+	edu := inv.Attributes[models.AttrEducation].Value
+	inv.Skills["Dodge_Copy"] = models.Skill{
+		Name:         "Dodge_Copy",
+		Abbreviation: "Dodge",
+		FormName:     "Dodge_Copy",
+		Default:      dex / 2,
+		Value:        dex / 2,
+	}
+	inv.Skills["Dodge"] = models.Skill{
+		Name:         "Dodge",
+		Abbreviation: "Dodge",
+		FormName:     "Dodge",
+		Default:      dex / 2,
+		Value:        dex / 2,
+	}
+	inv.Skills["Language(Own)"] = models.Skill{
+		Name:         "Language(Own)",
+		Abbreviation: "Language(Own)",
+		FormName:     "OwnLanguage",
+		Default:      edu,
+		Value:        edu,
+	}
+
+	for name, skill := range inv.Skills {
+		// Base skills only name the slots their specialisations are written in
+		if skill.Base == 1 {
+			continue
+		}
+		field := "Skill_" + strings.TrimSpace(skill.FormName)
+		if skill.Name == "Dodge_Copy" {
+			field = skill.FormName
+		}
+		if value, ok := s.Skills[field]; ok && value != "" {
+			skill.Value = strToInt(value)
+		}
+		skill.IsSelected = strToBool(s.Skills[field+"_Chk"])
+		inv.Skills[name] = skill
+	}
+
+	// Specialisations are written next to the open skill slots, except
+	// for slots such as OwnLanguage that label a skill already in the list
+	usedSlots := make(map[string]bool)
+	for _, skill := range inv.Skills {
+		if skill.Base == 0 {
+			usedSlots[strings.TrimSpace(skill.FormName)] = true
+		}
+	}
+	for slot, name := range s.SkillDefs {
+		name = strings.TrimSpace(name)
+		if name == "" || usedSlots[slot] {
+			continue
+		}
+		category, _, _ := strings.Cut(name, "(")
+		skill := models.Skill{
+			Name:         name,
+			Abbreviation: name,
+			FormName:     slot,
+			Default:      1,
+			Era:          models.Eras,
+			NeedsFormDef: 1,
+		}
+		if base, ok := inv.Skills[category]; ok && base.Base == 1 {
+			skill.Category = category
+			skill.Default = base.Default
+			skill.Era = base.Era
+		}
+		skill.Value = skill.Default
+		if value, ok := s.Skills["Skill_"+slot]; ok && value != "" {
+			skill.Value = strToInt(value)
+		}
+		skill.IsSelected = strToBool(s.Skills["Skill_"+slot+"_Chk"])
+		inv.Skills[name] = skill
+	}
+}
+
 // FromJSON creates an Investigator from JSON data
 func FromJSON(data []byte) (*models.Investigator, error) {
 	var serializer InvestigatorSerializer
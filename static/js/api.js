@@ -172,6 +172,233 @@ const API = {
         });
     },
 
+    /**
+     * Import an investigator from a filled character sheet PDF
+     * @param {File} file - Character sheet
+     * @returns {Promise<object>}
+     */
+    async importPDF(file) {
+        const body = new FormData();
+        body.append('sheet', file);
+
+        // Let the browser set the multipart boundary instead of the JSON default
+        const response = await fetch('/api/investigator/import/pdf', {
+            method: 'POST',
+            body,
+        });
+        if (!response.ok) {
+            throw new Error(`HTTP ${response.status}: ${response.statusText}`);
+        }
+        return response.json();
+    },
+
+    // =========================================================================
+    // Dice API
+    // =========================================================================
+
+    /**
+     * Roll a dice expression such as "3D6*5", "1D10+1D4+DB" or "D100B1"
+     * @param {string} expression - Dice expression
+     * @param {string} [investigatorId] - Investigator whose values (DB) the expression may use
+     * @returns {Promise<{expression: string, total: number, rolls: Array}>}
+     */
+    async roll(expression, investigatorId = '') {
+        return this.postJSON('/api/roll', { expression, investigatorId });
+    },
+
+    /**
+     * Roll a check against an investigator's skill or characteristic
+     * @param {string} id - Investigator ID
+     * @param {string} target - Skill name or characteristic such as "POW"
+     * @param {object} [options] - difficulty, bonus, penalty and markImprovement
+     * @returns {Promise<{target: string, value: number, roll: object, level: string, success: boolean, improvement: boolean}>}
+     */
+    async checkSkill(id, target, options = {}) {
+        return this.postJSON(`/api/investigator/${id}/check`, { target, ...options });
+    },
+
+    /**
+     * Run the end of session development phase, improving every ticked skill
+     * @param {string} id - Investigator ID
+     * @returns {Promise<{date: string, improvements: Array, sanityGained: number}>}
+     */
+    async develop(id) {
+        return this.postJSON(`/api/investigator/${id}/develop`, {});
+    },
+
+    /**
+     * Make a Sanity roll and apply the loss, with any insanity it brings
+     * @param {string} id - Investigator ID
+     * @param {string} loss - Loss in success/failure notation, e.g. "1/1D6"
+     * @param {string} [day] - Game day of the loss, today when empty
+     * @returns {Promise<object>} The Sanity roll, loss and insanity
+     */
+    async loseSanity(id, loss, day = '') {
+        return this.postJSON(`/api/investigator/${id}/sanity`, { loss, day });
+    },
+
+    /**
+     * Roll a bout of madness for an investigator
+     * @param {string} id - Investigator ID
+     * @param {string} kind - "realtime" or "summary"
+     * @param {boolean} addCondition - Append a resulting phobia or mania to the sheet
+     * @returns {Promise<object>} The bout with its narrative and duration
+     */
+    async rollBout(id, kind, addCondition) {
+        return this.postJSON(`/api/investigator/${id}/madness`, { kind, addCondition });
+    },
+
+    /**
+     * Roll a bout of madness for an investigator in one of the keeper's campaigns
+     * @param {string} campaignId - Campaign ID
+     * @param {string} id - Investigator ID of the member
+     * @param {string} kind - "realtime" or "summary"
+     * @param {boolean} addCondition - Append a resulting phobia or mania to the player's sheet
+     * @returns {Promise<object>} The bout with its narrative and duration
+     */
+    async rollMemberBout(campaignId, id, kind, addCondition) {
+        return this.postJSON(`/api/campaign/${campaignId}/member/${id}/madness`, { kind, addCondition });
+    },
+
+    /**
+     * Apply a single hit to an investigator
+     * @param {string} id - Investigator ID
+     * @param {string} damage - Damage as a number or dice expression
+     * @param {boolean} [spendLuck] - Pulp only, spend 5 Luck to ignore a major wound
+     * @returns {Promise<object>} The damage roll and the state it left the investigator in
+     */
+    async takeDamage(id, damage, spendLuck = false) {
+        return this.postJSON(`/api/investigator/${id}/damage`, { damage, spendLuck });
+    },
+
+    /**
+     * Treat an investigator with First Aid or Medicine
+     * @param {string} id - Investigator ID
+     * @param {string} skill - "First Aid" or "Medicine"
+     * @param {string} [healerId] - Investigator making the roll, the patient when empty
+     * @returns {Promise<object>} The healer's roll and the hit points restored
+     */
+    async heal(id, skill, healerId = '') {
+        return this.postJSON(`/api/investigator/${id}/heal`, { skill, healerId });
+    },
+
+    /**
+     * Apply the modifiers of an investigator's age to their characteristics
+     * @param {string} id - Investigator ID
+     * @param {object} [deductions] - Points to take from each characteristic, spread evenly when empty
+     * @returns {Promise<object>} The aging applied with a summary line
+     */
+    async applyAging(id, deductions = {}) {
+        return this.postJSON(`/api/investigator/${id}/aging`, { deductions });
+    },
+
+    /**
+     * Pick how an investigator's characteristics are generated
+     * @param {string} id - Investigator ID
+     * @param {string} method - rolled, point-buy, quick-fire or roll-and-assign
+     * @returns {Promise<object>} The method with the values or points to assign
+     */
+    async setGenerationMethod(id, method) {
+        return this.postJSON(`/api/investigator/${id}/generation`, { method });
+    },
+
+    /**
+     * Set every characteristic at once, checked against the generation method
+     * @param {string} id - Investigator ID
+     * @param {object} characteristics - Values keyed by abbreviation, such as STR and LCK
+     * @returns {Promise<object>} The investigator's attributes
+     */
+    async assignCharacteristics(id, characteristics) {
+        return this.postJSON(`/api/investigator/${id}/characteristics`, characteristics);
+    },
+
+    /**
+     * Check an investigator against the creation rules
+     * @param {string} id - Investigator ID
+     * @returns {Promise<object>} Whether they are legal, in strict mode, and the rules they break
+     */
+    async checkRules(id) {
+        return this.getJSON(`/api/investigator/${id}/rules`);
+    },
+
+    /**
+     * Spend points from a skill pool on a skill, or refund them with negative points
+     * @param {string} id - Investigator ID
+     * @param {string} pool - occupation, archetype or personal (general)
+     * @param {string} skill - Skill name
+     * @param {number} points - Points to spend, negative to refund
+     * @returns {Promise<object>} Every pool, the allocations and the skill's new value
+     */
+    async allocateSkillPoints(id, pool, skill, points) {
+        return this.postJSON(`/api/investigator/${id}/skill-points`, { pool, skill, points });
+    },
+
+    /**
+     * End creation, after which skills are edited directly on the sheet
+     * @param {string} id - Investigator ID
+     * @returns {Promise<Response>}
+     */
+    async completeCreation(id) {
+        return this.request(`/api/investigator/${id}/complete`, { method: 'POST' });
+    },
+
+    // =========================================================================
+    // Inventory API
+    // =========================================================================
+
+    /**
+     * Get the weapons catalogue of an era
+     * @param {string} era - Era name
+     * @returns {Promise<Array>}
+     */
+    async listWeapons(era = 'modern') {
+        return this.getJSON(`/api/weapons/?era=${encodeURIComponent(era)}`);
+    },
+
+    /**
+     * Add a weapon to an investigator's inventory
+     * @param {string} id - Investigator ID
+     * @param {object} weapon - Weapon, only a name to take it from the catalogue
+     * @returns {Promise<{weapons: Array, gear: Array}>}
+     */
+    async addWeapon(id, weapon) {
+        return this.postJSON(`/api/investigator/${id}/weapons`, weapon);
+    },
+
+    /**
+     * Remove a weapon from an investigator's inventory
+     * @param {string} id - Investigator ID
+     * @param {string} weaponId - Weapon ID
+     * @returns {Promise<Response>}
+     */
+    async removeWeapon(id, weaponId) {
+        return this.request(`/api/investigator/${id}/weapons/${weaponId}`, {
+            method: 'DELETE',
+        });
+    },
+
+    /**
+     * Add gear to an investigator's inventory
+     * @param {string} id - Investigator ID
+     * @param {object} gear - name, quantity and notes
+     * @returns {Promise<{weapons: Array, gear: Array}>}
+     */
+    async addGear(id, gear) {
+        return this.postJSON(`/api/investigator/${id}/gear`, gear);
+    },
+
+    /**
+     * Remove gear from an investigator's inventory
+     * @param {string} id - Investigator ID
+     * @param {string} gearId - Gear ID
+     * @returns {Promise<Response>}
+     */
+    async removeGear(id, gearId) {
+        return this.request(`/api/investigator/${id}/gear/${gearId}`, {
+            method: 'DELETE',
+        });
+    },
+
     // =========================================================================
     // Archetype API
     // =========================================================================
@@ -179,10 +406,20 @@ const API = {
     /**
      * Get occupations for an archetype
      * @param {string} archetypeName - Archetype name
+     * @param {string} era - Era the occupations must exist in
+     * @returns {Promise<{suggested: Array, others: Array}>}
+     */
+    async getArchetypeOccupations(archetypeName, era = 'modern') {
+        return this.getJSON(`/api/archetype/${encodeURIComponent(archetypeName)}/occupations?era=${encodeURIComponent(era)}`);
+    },
+
+    /**
+     * Get every occupation of an era
+     * @param {string} era - Era name
      * @returns {Promise<{suggested: Array, others: Array}>}
      */
-    async getArchetypeOccupations(archetypeName) {
-        return this.getJSON(`/api/archetype/${encodeURIComponent(archetypeName)}/occupations`);
+    async getOccupations(era = 'modern') {
+        return this.getJSON(`/api/occupations/?era=${encodeURIComponent(era)}`);
     },
 
     // =========================================================================
@@ -39,8 +39,15 @@ const App = {
                 htmx.ajax('GET', '/wizard/base/new', { target: '#character-sheet' });
                 break;
             case 'random':
-                htmx.ajax('GET', '/api/generate/?mode=pulp', { target: '#character-sheet' });
+            case 'random-classic': {
+                const mode = view === 'random-classic' ? 'classic' : 'pulp';
+                const era = params.get('era') || 'modern';
+                const seed = params.get('seed');
+                let url = `/api/generate/?mode=${mode}&era=${encodeURIComponent(era)}`;
+                if (seed) url += `&seed=${encodeURIComponent(seed)}`;
+                htmx.ajax('GET', url, { target: '#character-sheet' });
                 break;
+            }
         }
     },
 
@@ -226,12 +233,16 @@ const App = {
  */
 const characterUtils = {
     // Wizard functions
+    handleModeSelection: (el) => Wizard.handleModeSelection(el),
+    handleEraSelection: (el) => Wizard.handleEraSelection(el),
     handleArchetypeSelection: (el) => Wizard.handleArchetypeSelection(el),
     handleOccupationSelection: (el) => Wizard.handleOccupationSelection(el),
     checkFormCompletion: () => Wizard.checkFormCompletion(),
     handlePersonalInfoChange: (input) => Wizard.handlePersonalInfoChange(input),
     initAttributeForm: () => Wizard.initAttributeForm(),
     rollAllAttributes: () => Wizard.rollAllAttributes(),
+    setGenerationMethod: (select) => Wizard.setGenerationMethod(select),
+    rollLuck: () => Wizard.rollLuck(),
     rollAttribute: (input) => Wizard.rollSingleAttribute(input),
     updateAttributeValue: (input) => Wizard.updateAttributeValue(input),
     checkAttributesComplete: () => Wizard.checkAttributesComplete(),
@@ -249,16 +260,22 @@ const characterUtils = {
     recalculateSheetValues: (input, type) => CharacterSheet.recalculateValues(input, type),
     recalculateValues: (input, type) => CharacterSheet.recalculateValues(input, type),
     toggleLock: (checkbox) => CharacterSheet.toggleLock(checkbox),
+    toggleStrictRules: (checkbox) => CharacterSheet.toggleStrictRules(checkbox),
     togglePinSkill: (btn) => CharacterSheet.togglePinSkill(btn),
     handleSkillToggleCheck: (input) => CharacterSheet.handleSkillToggleCheck(input),
     handleSkillNameChange: (input) => CharacterSheet.handleSkillNameChange(input),
     updatePersonalInfo: (input) => CharacterSheet.updatePersonalInfo(input),
     updateHeaderName: (input) => CharacterSheet.updateHeaderName(input),
     exportPDF: (evt, key) => CharacterSheet.exportPDF(evt, key),
+    developInvestigator: (id) => CharacterSheet.developInvestigator(id),
     importInvestigators: () => CharacterSheet.importInvestigators(),
     addCondition: (type) => CharacterSheet.addCondition(type),
     removeCondition: (button) => CharacterSheet.removeCondition(button),
     previewCondition: (select, type) => CharacterSheet.previewCondition(select, type),
+    addWeapon: () => CharacterSheet.addWeapon(),
+    removeWeapon: (button) => CharacterSheet.removeWeapon(button),
+    addGear: () => CharacterSheet.addGear(),
+    removeGear: (button) => CharacterSheet.removeGear(button),
 
     // Utility functions
     getCurrentCharacter: () => Utils.getCurrentCharacter(),
@@ -0,0 +1,56 @@
+/**
+ * Auth Module - Handles login, registration and logout
+ * @module auth
+ */
+
+const Auth = {
+    /**
+     * Submit the login or register form as JSON
+     * @param {HTMLFormElement} form - Form with username and password fields
+     * @returns {boolean} Always false to prevent the native submit
+     */
+    submit(form) {
+        const errorBox = document.getElementById('auth-error');
+        errorBox.classList.add('d-none');
+
+        fetch(form.dataset.action, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({
+                username: form.elements.username.value,
+                password: form.elements.password.value,
+            }),
+        })
+            .then(async (response) => {
+                if (!response.ok) {
+                    const data = await response.json().catch(() => ({}));
+                    throw new Error(data.error || `HTTP ${response.status}`);
+                }
+                window.location.href = '/?view=archive';
+            })
+            .catch((error) => {
+                errorBox.textContent = error.message;
+                errorBox.classList.remove('d-none');
+            });
+
+        return false;
+    },
+
+    /**
+     * End the current session and return to the home page
+     * @returns {boolean} Always false to prevent link navigation
+     */
+    logout() {
+        fetch('/api/auth/logout', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+        }).finally(() => {
+            window.location.href = '/';
+        });
+
+        return false;
+    },
+};
+
+// Make available globally
+window.Auth = Auth;
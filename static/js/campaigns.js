@@ -0,0 +1,112 @@
+/**
+ * Campaigns Module - Keeper campaigns and joining them with a code
+ * @module campaigns
+ */
+
+const Campaigns = {
+    /**
+     * Send a JSON request and throw the server's error message on failure
+     * @param {string} url - API endpoint
+     * @param {string} method - HTTP method
+     * @param {object} data - Optional request body
+     * @returns {Promise<Response>}
+     */
+    async send(url, method, data) {
+        const response = await fetch(url, {
+            method,
+            headers: { 'Content-Type': 'application/json' },
+            body: data ? JSON.stringify(data) : undefined,
+        });
+
+        if (!response.ok) {
+            const body = await response.json().catch(() => ({}));
+            throw new Error(body.error || `HTTP ${response.status}`);
+        }
+
+        return response;
+    },
+
+    /**
+     * Create a campaign from the new campaign form
+     * @param {HTMLFormElement} form - Form with name, era and mode fields
+     * @returns {boolean} Always false to prevent the native submit
+     */
+    create(form) {
+        const errorBox = document.getElementById('campaign-error');
+        errorBox.classList.add('d-none');
+
+        this.send('/api/campaign', 'POST', {
+            name: form.elements.name.value,
+            era: form.elements.era.value,
+            mode: form.elements.mode.value,
+        })
+            .then(() => window.location.reload())
+            .catch((error) => {
+                errorBox.textContent = error.message;
+                errorBox.classList.remove('d-none');
+            });
+
+        return false;
+    },
+
+    /**
+     * Delete a campaign after confirmation
+     * @param {string} campaignId - Campaign ID
+     */
+    remove(campaignId) {
+        if (!confirm('Delete this campaign? Investigators are not affected.')) {
+            return;
+        }
+
+        this.send(`/api/campaign/${campaignId}`, 'DELETE')
+            .then(() => window.location.reload())
+            .catch((error) => alert(error.message));
+    },
+
+    /**
+     * Remove an investigator from a campaign after confirmation
+     * @param {string} campaignId - Campaign ID
+     * @param {string} investigatorId - Investigator ID
+     */
+    removeMember(campaignId, investigatorId) {
+        if (!confirm('Remove this investigator from the campaign?')) {
+            return;
+        }
+
+        this.send(`/api/campaign/${campaignId}/member/${investigatorId}`, 'DELETE')
+            .then(() => window.location.reload())
+            .catch((error) => alert(error.message));
+    },
+
+    /**
+     * Turn strict rules on or off for a campaign member, reverting the switch on failure
+     * @param {HTMLInputElement} checkbox - Switch carrying the campaign and investigator IDs
+     */
+    setStrictRules(checkbox) {
+        const { campaign, investigator } = checkbox.dataset;
+        this.send(`/api/campaign/${campaign}/member/${investigator}/strict`, 'POST', { strict: checkbox.checked })
+            .catch((error) => {
+                checkbox.checked = !checkbox.checked;
+                alert(error.message);
+            });
+    },
+
+    /**
+     * Ask for a join code and add an investigator to that campaign
+     * @param {string} investigatorId - Investigator ID
+     */
+    join(investigatorId) {
+        const code = prompt('Enter the join code from your Keeper');
+        if (!code) {
+            return;
+        }
+
+        this.send('/api/campaign/join', 'POST', { code, investigatorId })
+            .then((response) => response.json())
+            .then((data) => alert(`Joined ${data.campaign}`))
+            .catch((error) => alert(error.message));
+    },
+};
+
+// Make available globally
+window.Campaigns = Campaigns;
@@ -228,6 +228,51 @@ const CharacterSheet = {
         }
     },
 
+    /**
+     * Refresh the rules badge after a change
+     * @param {string} investigatorId - The investigator ID
+     */
+    async refreshRules(investigatorId) {
+        const badge = Utils.$('rules-badge');
+        const list = Utils.$('rules-violations');
+        if (!badge || !list) return;
+
+        try {
+            const result = await API.checkRules(investigatorId);
+            badge.classList.toggle('bg-success', result.legal);
+            badge.classList.toggle('bg-warning', !result.legal);
+            badge.classList.toggle('text-dark', !result.legal);
+            badge.innerHTML = result.legal
+                ? '<i class="bi bi-check-circle me-1"></i>Rules legal'
+                : `<i class="bi bi-exclamation-triangle me-1"></i>${result.violations.length} rule issue(s)`;
+            list.replaceChildren(...result.violations.map(violation => {
+                const item = document.createElement('li');
+                item.textContent = violation.message;
+                return item;
+            }));
+        } catch (error) {
+            console.error('Error checking rules:', error);
+        }
+    },
+
+    /**
+     * Turn strict rules on or off, refusing updates that break a creation rule
+     * @param {HTMLInputElement} checkbox - Strict rules checkbox
+     */
+    async toggleStrictRules(checkbox) {
+        try {
+            await API.updateInvestigator(Utils.getCurrentCharacterId(), 'stats', 'StrictRules', checkbox.checked);
+            Utils.showToast(
+                'Rules',
+                checkbox.checked ? 'Updates breaking the rules are now refused.' : 'Updates are no longer checked.',
+                '\uD83D\uDCDC'
+            );
+        } catch (error) {
+            console.error('Error toggling strict rules:', error);
+            checkbox.checked = !checkbox.checked;
+        }
+    },
+
     // =========================================================================
     // Lock/Unlock Functionality
     // =========================================================================
@@ -356,6 +401,13 @@ const CharacterSheet = {
         );
 
         input.dataset.skillvalue = value.toString();
+
+        // Living standard and wealth follow Credit Rating
+        if (skillName === 'Credit Rating') {
+            await this.refreshCombatStats(Utils.getCurrentCharacterId());
+        } else {
+            await this.refreshRules(Utils.getCurrentCharacterId());
+        }
     },
 
     /**
@@ -391,6 +443,10 @@ const CharacterSheet = {
         try {
             await API.updateInvestigator(investigatorId, 'personalInfo', field, value);
             Utils.showSuccess(input);
+            // MOV slows with age
+            if (field === 'Age') {
+                await this.refreshCombatStats(investigatorId);
+            }
         } catch (error) {
             console.error('Error updating personal info:', error);
             Utils.showError(input);
@@ -441,6 +497,28 @@ const CharacterSheet = {
      * Toggle skill check mark
      * @param {HTMLInputElement} input - Checkbox input
      */
+    /**
+     * Lock or unlock a skill's value input to match its improvement checkbox
+     * @param {HTMLElement} skillItem - The skill item element
+     * @param {boolean} isChecked - Whether the skill is ticked for improvement
+     */
+    setSkillImprovable(skillItem, isChecked) {
+        if (!skillItem) return;
+        const checkbox = skillItem.querySelector('input[type="checkbox"][data-skill]');
+        if (checkbox) {
+            checkbox.checked = isChecked;
+        }
+        const valueInput = skillItem.querySelector('.skill-value-field');
+        if (valueInput) {
+            valueInput.disabled = !isChecked;
+            valueInput.classList.toggle('skill-locked', !isChecked);
+            valueInput.classList.toggle('editable', isChecked);
+            valueInput.title = isChecked
+                ? 'Click to modify skill value'
+                : 'Check the box to enable skill improvement';
+        }
+    },
+
     async handleSkillToggleCheck(input) {
         const skillName = input.dataset.skill;
         const isChecked = input.checked;
@@ -455,17 +533,7 @@ const CharacterSheet = {
             );
 
             // Enable/disable the skill value input based on checkbox state
-            if (skillItem) {
-                const valueInput = skillItem.querySelector('.skill-value-field');
-                if (valueInput) {
-                    valueInput.disabled = !isChecked;
-                    valueInput.classList.toggle('skill-locked', !isChecked);
-                    valueInput.classList.toggle('editable', isChecked);
-                    valueInput.title = isChecked
-                        ? 'Click to modify skill value'
-                        : 'Check the box to enable skill improvement';
-                }
-            }
+            this.setSkillImprovable(skillItem, isChecked);
         } catch (error) {
             console.error('Error toggling skill check:', error);
             // Revert checkbox on error
@@ -584,18 +652,140 @@ const CharacterSheet = {
     },
 
     /**
-     * Import investigators from code
+     * Run the development phase for the investigator and show what improved
+     * @param {string} id - Investigator ID
+     */
+    async developInvestigator(id) {
+        if (!confirm('Roll improvement for every ticked skill? The ticks will be cleared.')) {
+            return;
+        }
+
+        try {
+            const report = await API.develop(id);
+            const improved = report.improvements.filter(imp => imp.improved);
+            let message = report.improvements.length === 0
+                ? 'No skills were ticked for improvement.'
+                : improved.map(imp => `${imp.skill} ${imp.from}% \u2192 ${imp.to}%`).join(', ') || 'No skill improved this time.';
+            if (report.sanityGained > 0) {
+                message += ` Sanity +${report.sanityGained}.`;
+            }
+            Utils.showToast('Development', message, '\uD83D\uDCC8');
+            await this.refreshCombatStats(id);
+        } catch (error) {
+            console.error('Error running development phase:', error);
+            Utils.showToast('Error', 'Failed to run the development phase.', '\u274C');
+        }
+    },
+
+    /**
+     * Make a Sanity roll for the loss typed on the sheet and show what it cost
+     * @param {string} id - Investigator ID
+     */
+    async applySanityLoss(id) {
+        const loss = Utils.getValue('san-loss').trim();
+        if (!loss) {
+            Utils.showToast('Error', 'Enter a Sanity loss such as 1/1D6.', '\u274C');
+            return;
+        }
+
+        try {
+            const result = await API.loseSanity(id, loss);
+            let message = `${result.check.level} (${result.check.roll.total}): lost ${result.lost}, Sanity ${result.sanity}.`;
+            if (result.permanentInsanity) {
+                message += ' Permanently insane!';
+            } else if (result.indefiniteInsanity) {
+                message += ' Indefinitely insane!';
+            }
+            if (result.temporaryInsanity) {
+                message += ` Temporarily insane for ${result.temporaryHours} hours!`;
+            }
+            Utils.showToast('Sanity', message, '\uD83E\uDDE0');
+            await this.refreshCombatStats(id);
+        } catch (error) {
+            console.error('Error applying sanity loss:', error);
+            Utils.showToast('Error', 'Failed to apply the Sanity loss. Check the notation.', '\u274C');
+        }
+    },
+
+    /**
+     * Apply the hit typed on the sheet and show what it did
+     * @param {string} id - Investigator ID
+     */
+    async applyDamage(id) {
+        const damage = Utils.getValue('damage-taken').trim();
+        if (!damage) {
+            Utils.showToast('Error', 'Enter the damage of the hit, such as 1D6+2.', '\u274C');
+            return;
+        }
+        const spendLuck = Utils.$('damage-spend-luck')?.checked || false;
+
+        try {
+            const result = await API.takeDamage(id, damage, spendLuck);
+            let message = `Took ${result.damage} damage, ${result.hp}/${result.maxHp} HP left.`;
+            if (result.killed) {
+                message += ' The hit is fatal!';
+            } else if (result.dying) {
+                message += ' Dying!';
+            } else if (result.unconscious) {
+                message += ' Unconscious!';
+            }
+            if (result.majorWound) {
+                message += ' Major wound!';
+            }
+            if (result.luckSpent) {
+                message += ` Spent ${result.luckSpent} Luck to ignore a major wound.`;
+            }
+            Utils.showToast('Damage', message, '\uD83E\uDE78');
+            await this.refreshCombatStats(id);
+        } catch (error) {
+            console.error('Error applying damage:', error);
+            Utils.showToast('Error', 'Failed to apply the damage. Check the dice.', '\u274C');
+        }
+    },
+
+    /**
+     * Treat the investigator with First Aid or Medicine
+     * @param {string} id - Investigator ID
+     * @param {string} skill - "First Aid" or "Medicine"
+     */
+    async heal(id, skill) {
+        try {
+            const result = await API.heal(id, skill);
+            let message = `${result.check.level} (${result.check.roll.total}): healed ${result.healed}, ${result.hp}/${result.maxHp} HP.`;
+            if (result.stabilized) {
+                message += ' Stabilized.';
+            } else if (result.woken) {
+                message += ' Back on their feet.';
+            }
+            Utils.showToast(skill, message, '\uD83E\uDE79');
+            await this.refreshCombatStats(id);
+        } catch (error) {
+            console.error('Error healing:', error);
+            const hint = skill === 'Medicine' ? ' A dying investigator needs First Aid first.' : '';
+            Utils.showToast('Error', `Failed to use ${skill}.${hint}`, '\u274C');
+        }
+    },
+
+    /**
+     * Import investigators from code or from a filled character sheet
      */
     async importInvestigators() {
         const importCode = Utils.getValue('importCode');
+        const sheetInput = Utils.$('importSheet');
+        const sheet = sheetInput && sheetInput.files.length > 0 ? sheetInput.files[0] : null;
 
-        if (!importCode) {
-            Utils.showToast('Error', 'Please enter an import code.', '\u274C');
+        if (!importCode && !sheet) {
+            Utils.showToast('Error', 'Please enter an import code or choose a character sheet.', '\u274C');
             return;
         }
 
         try {
-            await API.importInvestigators(importCode);
+            if (sheet) {
+                await API.importPDF(sheet);
+                sheetInput.value = '';
+            } else {
+                await API.importInvestigators(importCode);
+            }
 
             // Close modal and trigger HTMX refresh
             const modal = Utils.$('importModal');
@@ -723,6 +913,89 @@ const CharacterSheet = {
         }
     },
 
+    // =========================================================================
+    // Weapons & Gear
+    // =========================================================================
+
+    /**
+     * Add the weapon selected from the catalogue to the investigator
+     */
+    async addWeapon() {
+        const name = Utils.getValue('weapon-select');
+        const investigatorId = Utils.getCurrentCharacterId();
+        if (!name || !investigatorId) {
+            Utils.showToast('Error', 'Please select a weapon to add.', '\u274C');
+            return;
+        }
+
+        try {
+            await API.addWeapon(investigatorId, { name });
+            await this.refreshCombatStats(investigatorId);
+            Utils.showToast('Added', `${name} has been added.`, '\u2705');
+        } catch (error) {
+            console.error('Error adding weapon:', error);
+            Utils.showToast('Error', 'Failed to add the weapon.', '\u274C');
+        }
+    },
+
+    /**
+     * Remove a weapon from the investigator
+     * @param {HTMLButtonElement} button - The remove button clicked
+     */
+    async removeWeapon(button) {
+        const investigatorId = Utils.getCurrentCharacterId();
+        if (!investigatorId) return;
+
+        try {
+            await API.removeWeapon(investigatorId, button.dataset.weapon);
+            await this.refreshCombatStats(investigatorId);
+            Utils.showToast('Removed', `${button.dataset.name} has been removed.`, '\u2705');
+        } catch (error) {
+            console.error('Error removing weapon:', error);
+            Utils.showToast('Error', 'Failed to remove the weapon.', '\u274C');
+        }
+    },
+
+    /**
+     * Add the gear typed on the sheet to the investigator
+     */
+    async addGear() {
+        const name = Utils.getValue('gear-name').trim();
+        const quantity = Utils.parseInt(Utils.getValue('gear-quantity')) || 1;
+        const investigatorId = Utils.getCurrentCharacterId();
+        if (!name || !investigatorId) {
+            Utils.showToast('Error', 'Enter the name of the gear to add.', '\u274C');
+            return;
+        }
+
+        try {
+            await API.addGear(investigatorId, { name, quantity });
+            await this.refreshCombatStats(investigatorId);
+            Utils.showToast('Added', `${name} has been added.`, '\u2705');
+        } catch (error) {
+            console.error('Error adding gear:', error);
+            Utils.showToast('Error', 'Failed to add the gear.', '\u274C');
+        }
+    },
+
+    /**
+     * Remove gear from the investigator
+     * @param {HTMLButtonElement} button - The remove button clicked
+     */
+    async removeGear(button) {
+        const investigatorId = Utils.getCurrentCharacterId();
+        if (!investigatorId) return;
+
+        try {
+            await API.removeGear(investigatorId, button.dataset.gear);
+            await this.refreshCombatStats(investigatorId);
+            Utils.showToast('Removed', `${button.dataset.name} has been removed.`, '\u2705');
+        } catch (error) {
+            console.error('Error removing gear:', error);
+            Utils.showToast('Error', 'Failed to remove the gear.', '\u274C');
+        }
+    },
+
     // =========================================================================
     // Talent Management
     // =========================================================================
@@ -173,16 +173,22 @@ const ChaseTracker = {
     /**
      * Roll movement for all active participants
      */
-    rollMovement() {
-        this.participants.forEach(p => {
-            if (p.status === 'active') {
-                // Roll 1d6 + speed modifier
-                const roll = Math.floor(Math.random() * 6) + 1;
-                const speedMod = Math.floor((p.speed - 5) / 2);
-                const movement = Math.max(1, roll + speedMod);
+    async rollMovement() {
+        const active = this.participants.filter(p => p.status === 'active');
+        let rolls;
+        try {
+            rolls = await Promise.all(active.map(() => API.roll('1D6')));
+        } catch (error) {
+            console.error('Roll failed:', error);
+            this.showToast('Could not roll movement', 'error');
+            return;
+        }
+        active.forEach((p, i) => {
+            // Roll 1d6 + speed modifier
+            const speedMod = Math.floor((p.speed - 5) / 2);
+            const movement = Math.max(1, rolls[i].total + speedMod);
 
-                this.moveParticipant(p.id, movement);
-            }
+            this.moveParticipant(p.id, movement);
         });
     },
 
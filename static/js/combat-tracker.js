@@ -83,11 +83,12 @@ const CombatTracker = {
     /**
      * Roll initiative for a single combatant
      */
-    rollInitiative(id) {
+    async rollInitiative(id) {
         const combatant = this.combatants.find(c => c.id === id);
         if (combatant) {
             // Roll d100 against DEX
-            const roll = Math.floor(Math.random() * 100) + 1;
+            const roll = await this.rollD100();
+            if (roll === null) return;
             combatant.initiative = combatant.dex + (roll <= combatant.dex ? 50 : 0);
             this.sortByInitiative();
             this.saveState();
@@ -99,10 +100,11 @@ const CombatTracker = {
     /**
      * Roll initiative for all combatants
      */
-    rollAllInitiative() {
-        this.combatants.forEach(c => {
-            const roll = Math.floor(Math.random() * 100) + 1;
-            c.initiative = c.dex + (roll <= c.dex ? 50 : 0);
+    async rollAllInitiative() {
+        const rolls = await Promise.all(this.combatants.map(() => this.rollD100()));
+        if (rolls.includes(null)) return;
+        this.combatants.forEach((c, i) => {
+            c.initiative = c.dex + (rolls[i] <= c.dex ? 50 : 0);
         });
         this.sortByInitiative();
         this.saveState();
@@ -110,6 +112,21 @@ const CombatTracker = {
         this.addLog('Initiative rolled for all combatants', 'round');
     },
 
+    /**
+     * Roll 1D100 on the server
+     * @returns {Promise<number|null>} The roll, or null when it failed
+     */
+    async rollD100() {
+        try {
+            const result = await API.roll('D100');
+            return result.total;
+        } catch (error) {
+            console.error('Roll failed:', error);
+            this.showToast('Could not roll initiative', 'error');
+            return null;
+        }
+    },
+
     /**
      * Sort combatants by initiative
      */
@@ -120,7 +137,7 @@ const CombatTracker = {
     /**
      * Start combat
      */
-    startCombat() {
+    async startCombat() {
         if (this.combatants.length < 1) {
             this.showToast('Need at least 1 combatant', 'warning');
             return;
@@ -129,7 +146,7 @@ const CombatTracker = {
         // Auto-roll initiative if not set
         const noInitiative = this.combatants.every(c => c.initiative === 0);
         if (noInitiative) {
-            this.rollAllInitiative();
+            await this.rollAllInitiative();
         }
 
         this.status = 'active';
@@ -86,39 +86,26 @@ const HelperPanel = {
      * @param {string} skillName - Name of the skill
      * @param {number} skillValue - Current skill value
      */
-    quickRollSkill(skillName, skillValue) {
-        const roll = Math.floor(Math.random() * 100) + 1;
-        const half = Math.floor(skillValue / 2);
-        const fifth = Math.floor(skillValue / 5);
-
-        let outcome = '';
-        let outcomeClass = '';
-        let icon = '';
-
-        if (roll === 1) {
-            outcome = 'CRITICAL!';
-            outcomeClass = 'critical-success';
-            icon = '\u2728'; // sparkles
-        } else if (roll === 100 || (skillValue < 50 && roll >= 96)) {
-            outcome = 'FUMBLE!';
-            outcomeClass = 'fumble';
-            icon = '\uD83D\uDCA5'; // explosion
-        } else if (roll <= fifth) {
-            outcome = 'Extreme Success';
-            outcomeClass = 'extreme-success';
-            icon = '\uD83C\uDF1F'; // star
-        } else if (roll <= half) {
-            outcome = 'Hard Success';
-            outcomeClass = 'hard-success';
-            icon = '\u2705'; // check
-        } else if (roll <= skillValue) {
-            outcome = 'Success';
-            outcomeClass = 'success';
-            icon = '\u2714\uFE0F'; // check mark
-        } else {
-            outcome = 'Failure';
-            outcomeClass = 'failure';
-            icon = '\u274C'; // X
+    async quickRollSkill(skillName, skillValue) {
+        const investigatorId = Utils.getCurrentCharacterId();
+        if (!investigatorId) return;
+
+        let result;
+        try {
+            result = await API.checkSkill(investigatorId, skillName, { markImprovement: true });
+        } catch (error) {
+            console.error('Check failed:', error);
+            Utils.showToast('Error', `Could not roll ${skillName}`, '\u274C');
+            return;
+        }
+        const roll = result.roll.total;
+        skillValue = result.value;
+        const { outcome, outcomeClass, icon } = this.checkOutcomes[result.level] || this.checkOutcomes.Failure;
+
+        // A success ticks the skill for improvement on the server, mirror it on the sheet
+        if (result.improvement && window.CharacterSheet) {
+            const skillItem = Utils.qs(`.skill-item[data-skill-name="${CSS.escape(result.target)}"]`);
+            CharacterSheet.setSkillImprovable(skillItem, true);
         }
 
         // Show toast with result
@@ -131,6 +118,18 @@ const HelperPanel = {
         this.displayResult(roll, `d100 vs ${skillValue}`, outcome, outcomeClass);
     },
 
+    /**
+     * How each success level of a check is shown
+     */
+    checkOutcomes: {
+        'Critical Success': { outcome: 'CRITICAL!', outcomeClass: 'critical-success', icon: '\u2728' }, // sparkles
+        'Fumble': { outcome: 'FUMBLE!', outcomeClass: 'fumble', icon: '\uD83D\uDCA5' }, // explosion
+        'Extreme Success': { outcome: 'Extreme Success', outcomeClass: 'extreme-success', icon: '\uD83C\uDF1F' }, // star
+        'Hard Success': { outcome: 'Hard Success', outcomeClass: 'hard-success', icon: '\u2705' }, // check
+        'Regular Success': { outcome: 'Success', outcomeClass: 'success', icon: '\u2714\uFE0F' }, // check mark
+        'Failure': { outcome: 'Failure', outcomeClass: 'failure', icon: '\u274C' } // X
+    },
+
     /**
      * Show a toast notification for quick roll result
      */
@@ -195,25 +194,43 @@ const HelperPanel = {
     /** Toast timeout reference */
     _toastTimeout: null,
 
+    /**
+     * Roll a dice expression on the server
+     * @param {string} expression - Dice expression such as "1D6" or "D100B1"
+     * @returns {Promise<object|null>} The roll with its breakdown, or null when it failed
+     */
+    async rollExpression(expression) {
+        try {
+            return await API.roll(expression);
+        } catch (error) {
+            console.error('Roll failed:', error);
+            Utils.showToast('Error', `Could not roll ${expression}`, '\u274C');
+            return null;
+        }
+    },
+
     /**
      * Roll a die with specified sides
      * @param {number} sides - Number of sides on the die
-     * @returns {number} The roll result
+     * @returns {Promise<number|null>} The roll result
      */
-    roll(sides) {
-        const result = Math.floor(Math.random() * sides) + 1;
-        this.displayResult(result, `d${sides}`);
-        this.addToHistory(`d${sides}`, result);
-        return result;
+    async roll(sides) {
+        const result = await this.rollExpression(`1D${sides}`);
+        if (!result) return null;
+        this.displayResult(result.total, `d${sides}`);
+        this.addToHistory(`d${sides}`, result.total);
+        return result.total;
     },
 
     /**
      * Roll a skill check against a target number
      */
-    skillCheck() {
+    async skillCheck() {
         const targetInput = document.getElementById('skill-target');
         const target = parseInt(targetInput?.value || 50);
-        const roll = Math.floor(Math.random() * 100) + 1;
+        const result = await this.rollExpression('D100');
+        if (!result) return;
+        const roll = result.total;
 
         let outcome = '';
         let outcomeClass = '';
@@ -245,45 +262,43 @@ const HelperPanel = {
     /**
      * Roll with a bonus die
      */
-    rollWithBonus() {
-        const units = Math.floor(Math.random() * 10);
-        const tens1 = Math.floor(Math.random() * 10) * 10;
-        const tens2 = Math.floor(Math.random() * 10) * 10;
-        const result = Math.min(tens1, tens2) + units || 100;
+    async rollWithBonus() {
+        const result = await this.rollExpression('D100B1');
+        if (!result) return;
+        const [roll] = result.rolls;
 
         const resultEl = document.getElementById('bonus-penalty-result');
         if (resultEl) {
             resultEl.innerHTML = `
                 <div class="result-breakdown">
-                    <span class="text-muted">Tens: ${tens1}, ${tens2} (keep lower)</span>
-                    <span class="text-muted">Units: ${units}</span>
+                    <span class="text-muted">Tens: ${roll.tens.join(', ')} (keep lower)</span>
+                    <span class="text-muted">Units: ${roll.dice[0]}</span>
                 </div>
-                <div class="result-final text-success fw-bold">Result: ${result === 0 ? 100 : result}</div>
+                <div class="result-final text-success fw-bold">Result: ${result.total}</div>
             `;
         }
-        this.addToHistory('Bonus Die', result === 0 ? 100 : result);
+        this.addToHistory('Bonus Die', result.total);
     },
 
     /**
      * Roll with a penalty die
      */
-    rollWithPenalty() {
-        const units = Math.floor(Math.random() * 10);
-        const tens1 = Math.floor(Math.random() * 10) * 10;
-        const tens2 = Math.floor(Math.random() * 10) * 10;
-        const result = Math.max(tens1, tens2) + units || 100;
+    async rollWithPenalty() {
+        const result = await this.rollExpression('D100P1');
+        if (!result) return;
+        const [roll] = result.rolls;
 
         const resultEl = document.getElementById('bonus-penalty-result');
         if (resultEl) {
             resultEl.innerHTML = `
                 <div class="result-breakdown">
-                    <span class="text-muted">Tens: ${tens1}, ${tens2} (keep higher)</span>
-                    <span class="text-muted">Units: ${units}</span>
+                    <span class="text-muted">Tens: ${roll.tens.join(', ')} (keep higher)</span>
+                    <span class="text-muted">Units: ${roll.dice[0]}</span>
                 </div>
-                <div class="result-final text-danger fw-bold">Result: ${result === 0 ? 100 : result}</div>
+                <div class="result-final text-danger fw-bold">Result: ${result.total}</div>
             `;
         }
-        this.addToHistory('Penalty Die', result === 0 ? 100 : result);
+        this.addToHistory('Penalty Die', result.total);
     },
 
     /**
@@ -400,32 +415,30 @@ const HelperPanel = {
     /**
      * Calculate Sanity recovery
      */
-    calculateSanityRecovery() {
-        const amount = parseInt(document.getElementById('san-rest-amount')?.value || 1);
+    async calculateSanityRecovery() {
+        const amount = Math.max(1, parseInt(document.getElementById('san-rest-amount')?.value || 1));
         const unit = document.getElementById('san-rest-unit')?.value || 'months';
 
         let sanRecovered = 0;
         let explanation = '';
 
         switch (unit) {
-            case 'sessions':
+            case 'sessions': {
                 // Successful therapy session: 1d6 Sanity
-                const sessionRolls = [];
-                for (let i = 0; i < amount; i++) {
-                    sessionRolls.push(Math.floor(Math.random() * 6) + 1);
-                }
-                sanRecovered = sessionRolls.reduce((a, b) => a + b, 0);
-                explanation = `${amount} therapy session(s): ${sessionRolls.join(' + ')} = ${sanRecovered}`;
+                const result = await this.rollExpression(`${amount}D6`);
+                if (!result) return;
+                sanRecovered = result.total;
+                explanation = `${amount} therapy session(s): ${result.rolls[0].dice.join(' + ')} = ${sanRecovered}`;
                 break;
-            case 'months':
+            }
+            case 'months': {
                 // 1d6 per month of private care / 1d3 per month of institutional care
-                const monthRolls = [];
-                for (let i = 0; i < amount; i++) {
-                    monthRolls.push(Math.floor(Math.random() * 6) + 1);
-                }
-                sanRecovered = monthRolls.reduce((a, b) => a + b, 0);
-                explanation = `${amount} month(s) of care: ${monthRolls.join(' + ')} = ${sanRecovered}`;
+                const result = await this.rollExpression(`${amount}D6`);
+                if (!result) return;
+                sanRecovered = result.total;
+                explanation = `${amount} month(s) of care: ${result.rolls[0].dice.join(' + ')} = ${sanRecovered}`;
                 break;
+            }
         }
 
         const resultEl = document.getElementById('san-recovery-result');
@@ -440,9 +453,11 @@ const HelperPanel = {
     /**
      * Roll First Aid recovery (Pulp)
      */
-    rollFirstAid() {
-        const roll = Math.floor(Math.random() * 6) + 1;
-        const total = roll + 1;
+    async rollFirstAid() {
+        const result = await this.rollExpression('1D6+1');
+        if (!result) return;
+        const roll = result.rolls[0].total;
+        const total = result.total;
 
         const resultEl = document.getElementById('first-aid-result');
         if (resultEl) {
@@ -457,10 +472,11 @@ const HelperPanel = {
     /**
      * Roll Luck recovery (Pulp)
      */
-    rollLuckRecovery() {
-        const d1 = Math.floor(Math.random() * 6) + 1;
-        const d2 = Math.floor(Math.random() * 6) + 1;
-        const total = d1 + d2 + 10;
+    async rollLuckRecovery() {
+        const result = await this.rollExpression('2D6+10');
+        if (!result) return;
+        const [d1, d2] = result.rolls[0].dice;
+        const total = result.total;
 
         const resultEl = document.getElementById('luck-recovery-result');
         if (resultEl) {
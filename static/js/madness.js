@@ -0,0 +1,69 @@
+/**
+ * Madness Module - Rolls bouts of madness from the keeper dashboard
+ * @module madness
+ */
+
+const Madness = {
+    /**
+     * Roll a bout of madness for the investigator chosen in the form
+     * @param {HTMLFormElement} form - Form with investigator, kind and addCondition fields
+     * @returns {boolean} Always false to prevent the native submit
+     */
+    roll(form) {
+        const select = form.elements.investigator;
+        const id = select.value;
+        // Campaign members are rolled through the campaign so the condition reaches their player
+        const campaign = select.selectedOptions[0]?.dataset.campaign;
+        const kind = form.elements.kind.value;
+        const addCondition = form.elements.addCondition.checked;
+        const request = campaign
+            ? API.rollMemberBout(campaign, id, kind, addCondition)
+            : API.rollBout(id, kind, addCondition);
+        request
+            .then((bout) => this.render(bout))
+            .catch((error) => {
+                console.error('Bout of madness failed:', error);
+                const message = error.message.startsWith('HTTP 409')
+                    ? 'This player keeps their investigator in their browser, add the condition from their sheet.'
+                    : 'Could not roll a bout of madness.';
+                Utils.showToast('Error', message, '\u274C');
+            });
+        return false;
+    },
+
+    /**
+     * Show a rolled bout under the form
+     * @param {object} bout - The bout returned by the server
+     */
+    render(bout) {
+        const box = document.getElementById('bout-result');
+        box.replaceChildren();
+
+        const title = document.createElement('h5');
+        title.textContent = `${bout.roll}. ${bout.name}`;
+        const duration = document.createElement('span');
+        duration.className = 'badge bg-secondary ms-2';
+        duration.textContent = `${bout.duration} ${bout.durationUnit}`;
+        title.appendChild(duration);
+
+        const description = document.createElement('p');
+        description.className = 'mb-1';
+        description.textContent = bout.description;
+        box.append(title, description);
+
+        const condition = bout.phobia || bout.mania;
+        if (condition) {
+            const gained = document.createElement('p');
+            gained.className = 'mb-0 small';
+            const name = document.createElement('strong');
+            name.textContent = condition.Name;
+            gained.append(name, ` — ${condition.Description}`);
+            gained.append(bout.added ? ' (added to the sheet)' : '');
+            box.appendChild(gained);
+        }
+
+        box.classList.remove('d-none');
+    },
+};
+
+window.Madness = Madness;
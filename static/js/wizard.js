@@ -141,6 +141,52 @@ const Wizard = {
         this.checkFormCompletion();
     },
 
+    /**
+     * Check whether the base form is set up for Classic rules, which have no archetypes
+     * @returns {boolean}
+     */
+    isClassicMode() {
+        return Utils.$('mode-select')?.value === 'classic';
+    },
+
+    /**
+     * Handle game mode selection change
+     * @param {HTMLSelectElement} selectElement - Game mode select element
+     */
+    handleModeSelection(selectElement) {
+        const isClassic = selectElement.value === 'classic';
+        const archetypeContainer = Utils.$('archetype-container');
+        const archetypeSelect = Utils.$('archetype-select');
+        const occupationContainer = Utils.$('occupation-container');
+        const nextLabel = Utils.$('next-step-label');
+
+        if (archetypeContainer) {
+            archetypeContainer.style.display = isClassic ? 'none' : 'block';
+        }
+        if (isClassic && archetypeSelect) {
+            archetypeSelect.value = '';
+            Utils.$('archetype-description').style.display = 'none';
+        }
+        if (nextLabel) {
+            nextLabel.textContent = isClassic ? 'Continue to Attributes' : 'Continue to Talents';
+        }
+
+        // Without an archetype the occupations are offered straight away.
+        // The list always holds every occupation, the archetype only reorders it.
+        occupationContainer.style.display = isClassic || archetypeSelect?.value ? 'block' : 'none';
+
+        this.checkFormCompletion();
+    },
+
+    /**
+     * Handle era selection change
+     * The era decides which occupations exist, so the list is reloaded
+     */
+    async handleEraSelection() {
+        await this.updateOccupationOptions(Utils.$('archetype-select')?.value || '');
+        this.checkFormCompletion();
+    },
+
     /**
      * Handle archetype selection change
      * @param {HTMLSelectElement} selectElement - Archetype select element
@@ -171,8 +217,8 @@ const Wizard = {
     },
 
     /**
-     * Update occupation dropdown based on selected archetype
-     * @param {string} archetypeName - Selected archetype name
+     * Update occupation dropdown based on selected archetype and era
+     * @param {string} archetypeName - Selected archetype name, empty for none
      */
     async updateOccupationOptions(archetypeName) {
         const occupationSelect = Utils.$('occupation-select');
@@ -187,7 +233,10 @@ const Wizard = {
         }
 
         try {
-            const data = await API.getArchetypeOccupations(archetypeName);
+            const era = Utils.$('era-select')?.value || 'modern';
+            const data = archetypeName
+                ? await API.getArchetypeOccupations(archetypeName, era)
+                : await API.getOccupations(era);
 
             // Clear existing options (keep first "Select Occupation" option)
             while (occupationSelect.options.length > 1) {
@@ -294,7 +343,7 @@ const Wizard = {
             ageInput?.value !== '' &&
             residenceInput?.value.trim() !== '' &&
             birthplaceInput?.value.trim() !== '' &&
-            archetypeSelect?.value !== '' &&
+            (this.isClassicMode() || archetypeSelect?.value !== '') &&
             occupationSelect?.value !== ''
         );
 
@@ -315,10 +364,13 @@ const Wizard = {
 
         Utils.setButtonLoading(button, true, 'Processing...');
 
+        // Classic investigators have no talents to pick
+        const nextStep = this.isClassicMode() ? 'attributes' : 'talents';
+
         try {
             if (existingId?.value) {
-                // Investigator exists, go to talent selection
-                const html = await API.getWizardStep('talents', existingId.value);
+                // Investigator exists, go to the next step
+                const html = await API.getWizardStep(nextStep, existingId.value);
                 Utils.setHTML('character-sheet', html);
             } else {
                 // Create new investigator
@@ -327,7 +379,7 @@ const Wizard = {
 
                 const data = await API.createInvestigator(jsonData);
                 if (data.Key) {
-                    const html = await API.getWizardStep('talents', data.Key);
+                    const html = await API.getWizardStep(nextStep, data.Key);
                     Utils.setHTML('character-sheet', html);
                 }
             }
@@ -361,9 +413,70 @@ const Wizard = {
             this.updateDerivedValues(input);
         });
 
+        this.updateGenerationTally();
         this.checkAttributesComplete();
     },
 
+    /**
+     * Get the generation method of the attributes step
+     * @returns {string} rolled, point-buy, quick-fire or roll-and-assign
+     */
+    generationMethod() {
+        const panel = Utils.$('generation-panel');
+        return panel ? panel.dataset.method : 'rolled';
+    },
+
+    /**
+     * Switch the generation method and reload the attributes step
+     * @param {HTMLSelectElement} select - Generation method select
+     */
+    async setGenerationMethod(select) {
+        const investigatorId = Utils.getCurrentCharacterId();
+        try {
+            await API.setGenerationMethod(investigatorId, select.value);
+            const html = await API.getWizardStep('attributes', investigatorId);
+            Utils.setHTML('character-sheet', html);
+        } catch (error) {
+            console.error('Error setting generation method:', error);
+            Utils.showToast('Error', 'Failed to change the generation method.', '\u274C');
+        }
+    },
+
+    /**
+     * Roll Luck on its own, for methods that assign the other characteristics
+     */
+    rollLuck() {
+        const input = Utils.qs('.attribute-input[name="LCK"]');
+        if (input) {
+            this.rollSingleAttribute(input);
+        }
+    },
+
+    /**
+     * Show the points spent with point-buy, or which values are still free to assign
+     */
+    updateGenerationTally() {
+        const panel = Utils.$('generation-panel');
+        if (!panel) return;
+
+        const inputs = Array.from(Utils.qsa('.attribute-input')).filter(input => input.name !== 'LCK');
+        const values = inputs.map(input => Utils.parseInt(input.value)).filter(value => value > 0);
+
+        const spent = Utils.$('generation-spent');
+        if (spent) {
+            const total = values.reduce((sum, value) => sum + value, 0);
+            spent.textContent = total;
+            spent.classList.toggle('text-danger', total > Utils.parseInt(panel.dataset.points));
+        }
+
+        const remaining = [...values];
+        Utils.qsa('.generation-value').forEach(badge => {
+            const index = remaining.indexOf(Utils.parseInt(badge.dataset.value));
+            if (index !== -1) remaining.splice(index, 1);
+            badge.classList.toggle('opacity-25', index !== -1);
+        });
+    },
+
     /**
      * Update derived values for an attribute input
      * @param {HTMLInputElement} input - Attribute input element
@@ -441,6 +554,13 @@ const Wizard = {
 
         this.updateDerivedValues(input);
 
+        // Assigned characteristics are checked and saved together when proceeding
+        if (this.generationMethod() !== 'rolled') {
+            this.updateGenerationTally();
+            this.checkAttributesComplete();
+            return;
+        }
+
         try {
             await API.updateInvestigator(
                 Utils.getCurrentCharacterId(),
@@ -472,11 +592,68 @@ const Wizard = {
         Utils.updateButtonState(proceedButton, allFilled);
     },
 
+    /**
+     * Apply the age modifiers to the rolled characteristics, once per investigator
+     * @param {string} investigatorId - Investigator ID
+     * @returns {Promise<boolean>} Whether the wizard can move on
+     */
+    async applyAging(investigatorId) {
+        const panel = Utils.$('aging-panel');
+        if (!panel || panel.dataset.pending !== 'true') {
+            return true;
+        }
+
+        const deductions = {};
+        Utils.qsa('.aging-deduction').forEach(input => {
+            if (input.value !== '') {
+                deductions[input.dataset.attr] = Utils.parseInt(input.value);
+            }
+        });
+
+        try {
+            const result = await API.applyAging(investigatorId, deductions);
+            Utils.showToast('Aging', result.summary, '\u231B');
+            return true;
+        } catch (error) {
+            console.error('Error applying aging:', error);
+            Utils.showToast('Error', 'Failed to apply aging. Check the deductions add up.', '\u274C');
+            return false;
+        }
+    },
+
+    /**
+     * Save the characteristics together when the generation method assigns them
+     * @param {string} investigatorId - Investigator ID
+     * @returns {Promise<boolean>} Whether the wizard can move on
+     */
+    async assignCharacteristics(investigatorId) {
+        const panel = Utils.$('aging-panel');
+        if (this.generationMethod() === 'rolled' || (panel && panel.dataset.pending !== 'true')) {
+            return true;
+        }
+
+        const characteristics = {};
+        Utils.qsa('.attribute-input').forEach(input => {
+            characteristics[input.name] = Utils.parseInt(input.value);
+        });
+
+        try {
+            await API.assignCharacteristics(investigatorId, characteristics);
+            return true;
+        } catch (error) {
+            console.error('Error assigning characteristics:', error);
+            Utils.showToast('Error', 'The characteristics do not follow the generation method.', '\u274C');
+            return false;
+        }
+    },
+
     /**
      * Navigate to skills step
      * @param {string} investigatorId - Investigator ID
      */
     async proceedToSkills(investigatorId) {
+        if (!await this.assignCharacteristics(investigatorId)) return;
+        if (!await this.applyAging(investigatorId)) return;
         try {
             const html = await API.getWizardStep('skills', investigatorId);
             Utils.setHTML('character-sheet', html);
@@ -546,68 +723,59 @@ const Wizard = {
         const { points: pointsId, confirm: confirmId } = pointsMap[type] || pointsMap.archetype;
         const pointsElement = Utils.$(pointsId);
 
-        if (pointsElement) {
-            const currentPoints = Utils.parseInt(pointsElement.textContent);
-            const newPoints = currentPoints - difference;
-
-            // Don't allow negative points
-            if (newPoints < 0) {
-                input.value = prevValue;
-                Utils.showInvalid(input);
-                return;
-            }
+        // The server debits or refunds the pool of the active tab. Track the value right away
+        // so quick +/- clicks each send their own difference.
+        input.dataset.skillvalue = value;
+        let result;
+        try {
+            result = await API.allocateSkillPoints(Utils.getCurrentCharacterId(), type, skillName, difference);
+        } catch (error) {
+            console.error('Error allocating skill points:', error);
+            input.value = prevValue;
+            input.dataset.skillvalue = prevValue;
+            Utils.showInvalid(input);
+            return;
+        }
+        const pool = type === 'general' ? 'personal' : type;
+        const newPoints = result.pools[pool].remaining;
 
+        if (pointsElement) {
             // Update points display
             pointsElement.textContent = newPoints;
             pointsElement.style.color = newPoints < 10 ? '#e84a5f' : '#63c74d';
+        }
 
-            // Flash highlight the skill box
-            const skillBox = input.closest('.skill-box');
-            if (skillBox) Utils.flashHighlight(skillBox);
-
-            // Update tracking
-            input.dataset.skillvalue = value;
-
-            // Update derived values
-            const container = input.closest('.skill-values');
-            if (container) Utils.updateDerivedValues(container, value);
-
-            // IMPORTANT: Update ALL inputs with the same skill name across all tabs
-            // This ensures skill values carry through between archetype, occupation, and general tabs
-            Utils.qsa(`input[data-skill="${skillName}"]`).forEach(otherInput => {
-                if (otherInput !== input) {
-                    otherInput.value = value;
-                    otherInput.dataset.skillvalue = value;
-                    // Update derived values for this input too
-                    const otherContainer = otherInput.closest('.skill-values');
-                    if (otherContainer) Utils.updateDerivedValues(otherContainer, value);
-                }
-            });
+        // Flash highlight the skill box
+        const skillBox = input.closest('.skill-box');
+        if (skillBox) Utils.flashHighlight(skillBox);
+
+        // Update derived values
+        const container = input.closest('.skill-values');
+        if (container) Utils.updateDerivedValues(container, result.value);
+
+        // IMPORTANT: Update ALL inputs with the same skill name across all tabs
+        // This ensures skill values carry through between archetype, occupation, and general tabs
+        Utils.qsa(`input[data-skill="${skillName}"]`).forEach(otherInput => {
+            if (otherInput !== input) {
+                otherInput.value = result.value;
+                otherInput.dataset.skillvalue = result.value;
+                // Update derived values for this input too
+                const otherContainer = otherInput.closest('.skill-values');
+                if (otherContainer) Utils.updateDerivedValues(otherContainer, result.value);
+            }
+        });
 
-            // Show continue button if all points used
-            const confirmContainer = Utils.$(confirmId);
-            if (confirmContainer && newPoints === 0) {
-                confirmContainer.style.opacity = '1';
-                confirmContainer.style.pointerEvents = 'auto';
+        // Show continue button if all points used
+        const confirmContainer = Utils.$(confirmId);
+        if (confirmContainer && newPoints === 0) {
+            confirmContainer.style.opacity = '1';
+            confirmContainer.style.pointerEvents = 'auto';
 
-                // If general skills tab and points hit 0, show ready to play popup
-                if (type === 'general') {
-                    this.showReadyToPlayPopup();
-                }
+            // If general skills tab and points hit 0, show ready to play popup
+            if (type === 'general') {
+                this.showReadyToPlayPopup();
             }
         }
-
-        // Update server
-        try {
-            await API.updateInvestigator(
-                Utils.getCurrentCharacterId(),
-                'skills',
-                skillName,
-                value
-            );
-        } catch (error) {
-            console.error('Error updating skill:', error);
-        }
     },
 
     /**
@@ -720,6 +888,7 @@ const Wizard = {
         }
 
         try {
+            await API.completeCreation(investigatorId);
             const html = await API.getInvestigator(investigatorId);
             Utils.setHTML('character-sheet', html);
             Utils.showToast('Success', 'Character creation complete!', '\u2705');
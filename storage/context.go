@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"net/http"
+
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/models"
+)
+
+type contextKey string
+
+const (
+	ownerContextKey         contextKey = "owner"
+	userContextKey          contextKey = "user"
+	cookieSessionContextKey contextKey = "cookieSession"
+)
+
+// cookieSession carries the HTTP exchange a cookie-backed store reads from and writes to
+type cookieSession struct {
+	w http.ResponseWriter
+	r *http.Request
+}
+
+// WithOwner returns a copy of ctx carrying the owner ID of the current caller
+func WithOwner(ctx context.Context, ownerID string) context.Context {
+	return context.WithValue(ctx, ownerContextKey, ownerID)
+}
+
+// OwnerFromContext returns the owner ID stored in ctx, or an empty string
+func OwnerFromContext(ctx context.Context) string {
+	ownerID, _ := ctx.Value(ownerContextKey).(string)
+	return ownerID
+}
+
+// WithUser returns a copy of ctx carrying the logged in user
+func WithUser(ctx context.Context, user *models.User) context.Context {
+	return context.WithValue(ctx, userContextKey, user)
+}
+
+// UserFromContext returns the logged in user stored in ctx, or nil for anonymous callers
+func UserFromContext(ctx context.Context) *models.User {
+	user, _ := ctx.Value(userContextKey).(*models.User)
+	return user
+}
+
+// WithCookieSession returns a copy of ctx carrying the request and response
+// used by CookieStore to persist investigators in the browser
+func WithCookieSession(ctx context.Context, w http.ResponseWriter, r *http.Request) context.Context {
+	return context.WithValue(ctx, cookieSessionContextKey, &cookieSession{w: w, r: r})
+}
+
+// cookieSessionFromContext returns the cookie session stored in ctx
+func cookieSessionFromContext(ctx context.Context) (*cookieSession, error) {
+	session, ok := ctx.Value(cookieSessionContextKey).(*cookieSession)
+	if !ok || session == nil {
+		return nil, errors.ErrNoCookieSession
+	}
+	return session, nil
+}
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"book-of-shadows/internal/errors"
+)
+
+const (
+	// maxCookieValueSize is the largest value stored in a single investigator cookie
+	maxCookieValueSize = 4096
+	// cookieChunkSize leaves room for the cookie name and attributes within the 4KB per-cookie limit
+	cookieChunkSize = 3800
+	// chunkManifestPrefix marks a manifest cookie whose value holds the number of chunks
+	chunkManifestPrefix = "chunks:"
+)
+
+// chunkCookieName returns the name of the n-th chunk cookie of an investigator
+func chunkCookieName(id string, n int) string {
+	return fmt.Sprintf("%s.%d", id, n)
+}
+
+// parseChunkManifest returns the chunk count held by a manifest cookie value
+func parseChunkManifest(value string) (int, bool) {
+	if !strings.HasPrefix(value, chunkManifestPrefix) {
+		return 0, false
+	}
+	count, err := strconv.Atoi(strings.TrimPrefix(value, chunkManifestPrefix))
+	if err != nil || count <= 0 {
+		return 0, false
+	}
+	return count, true
+}
+
+// splitIntoChunks splits an encoded value into cookie sized chunks
+func splitIntoChunks(value string) []string {
+	chunks := make([]string, 0, len(value)/cookieChunkSize+1)
+	for len(value) > cookieChunkSize {
+		chunks = append(chunks, value[:cookieChunkSize])
+		value = value[cookieChunkSize:]
+	}
+	return append(chunks, value)
+}
+
+// storedChunkCount returns how many chunk cookies the request holds for an investigator
+func storedChunkCount(r *http.Request, id string) int {
+	cookie, err := r.Cookie(id)
+	if err != nil {
+		return 0
+	}
+	count, _ := parseChunkManifest(cookie.Value)
+	return count
+}
+
+// isChunkCookie reports whether a cookie is a chunk of a manifest present in the request
+func isChunkCookie(r *http.Request, name string) bool {
+	dot := strings.LastIndex(name, ".")
+	if dot <= 0 {
+		return false
+	}
+	n, err := strconv.Atoi(name[dot+1:])
+	if err != nil || n < 0 {
+		return false
+	}
+	return n < storedChunkCount(r, name[:dot])
+}
+
+// readInvestigatorValue returns the encoded investigator stored under id, reassembling
+// it from its chunk cookies when the cookie value is a manifest
+func readInvestigatorValue(r *http.Request, id, value string) (string, error) {
+	count, ok := parseChunkManifest(value)
+	if !ok {
+		return value, nil
+	}
+
+	var builder strings.Builder
+	for n := 0; n < count; n++ {
+		chunk, err := r.Cookie(chunkCookieName(id, n))
+		if err != nil {
+			// A manifest without all of its chunks cannot be decoded
+			return "", errors.ErrInvalidCookie
+		}
+		builder.WriteString(chunk.Value)
+	}
+
+	return builder.String(), nil
+}
+
+// writeInvestigatorValue seals an encoded investigator and stores it under id, splitting
+// it into chunk cookies behind a manifest when it does not fit in a single cookie
+func (cs *CookieStore) writeInvestigatorValue(session *cookieSession, id, encodedValue string) error {
+	previousChunks := storedChunkCount(session.r, id)
+
+	sealedValue, err := cs.sealer.seal(id, encodedValue)
+	if err != nil {
+		return fmt.Errorf("failed to seal investigator: %w", err)
+	}
+
+	var cookies []*http.Cookie
+	if len(sealedValue) <= maxCookieValueSize {
+		cookies = append(cookies, cs.createCookie(id, sealedValue))
+	} else {
+		chunks := splitIntoChunks(sealedValue)
+		for n, chunk := range chunks {
+			cookies = append(cookies, cs.createCookie(chunkCookieName(id, n), chunk))
+		}
+		cookies = append(cookies, cs.createCookie(id, fmt.Sprintf("%s%d", chunkManifestPrefix, len(chunks))))
+	}
+
+	if err := cs.checkCookieBudget(session.r, id, cookies); err != nil {
+		return err
+	}
+
+	for _, cookie := range cookies {
+		http.SetCookie(session.w, cookie)
+	}
+
+	// Expire chunks left over from a previous, larger version of the investigator
+	newChunks := len(cookies) - 1
+	for n := newChunks; n < previousChunks; n++ {
+		http.SetCookie(session.w, cs.expiredCookie(chunkCookieName(id, n)))
+	}
+
+	return nil
+}
+
+// checkCookieBudget ensures that replacing the cookies of an investigator keeps the
+// total size of all cookies sent to the domain within the configured budget
+func (cs *CookieStore) checkCookieBudget(r *http.Request, id string, cookies []*http.Cookie) error {
+	if cs.config.MaxTotalSize <= 0 {
+		return nil
+	}
+
+	total := 0
+	for _, cookie := range r.Cookies() {
+		if cookie.Name == id || strings.HasPrefix(cookie.Name, id+".") && isChunkCookie(r, cookie.Name) {
+			continue
+		}
+		total += len(cookie.Name) + len(cookie.Value)
+	}
+	for _, cookie := range cookies {
+		total += len(cookie.Name) + len(cookie.Value)
+	}
+
+	if total > cs.config.MaxTotalSize {
+		return errors.ErrCookieTooLarge
+	}
+
+	return nil
+}
+
+// expiredCookie creates a cookie that removes name from the browser
+func (cs *CookieStore) expiredCookie(name string) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		Expires:  time.Now().Add(-24 * time.Hour),
+		HttpOnly: cs.config.HttpOnly,
+		Secure:   cs.config.Secure,
+		SameSite: http.SameSite(cs.config.SameSite),
+	}
+}
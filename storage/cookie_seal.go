@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"book-of-shadows/internal/config"
+	"book-of-shadows/internal/errors"
+)
+
+const (
+	// sealSigned marks a payload that is authenticated but readable
+	sealSigned = "s"
+	// sealEncrypted marks a payload that is encrypted and authenticated
+	sealEncrypted = "e"
+)
+
+// cookieSealer authenticates and optionally encrypts cookie payloads.
+// The first key of each list is used for new cookies; every key is accepted
+// when reading so keys can be rotated without invalidating existing cookies.
+type cookieSealer struct {
+	signingKeys   [][]byte
+	aeads         []cipher.AEAD
+	allowUnsigned bool
+}
+
+// newCookieSealer builds a sealer from the configured keys. Without signing keys an
+// ephemeral key is generated, so cookies will not survive a server restart; production
+// refuses to start without them (see config.Validate).
+func newCookieSealer(cfg *config.CookieConfig, logger *log.Logger) *cookieSealer {
+	sealer := &cookieSealer{allowUnsigned: cfg.AllowUnsigned}
+
+	for _, key := range cfg.SigningKeys {
+		sealer.signingKeys = append(sealer.signingKeys, []byte(key))
+	}
+	if len(sealer.signingKeys) == 0 {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			panic(fmt.Sprintf("failed to generate cookie signing key: %v", err))
+		}
+		// Only say the key is ephemeral, logging it would let anyone reading the logs forge cookies
+		logger.Println("COOKIE_SIGNING_KEYS is not set, using an ephemeral signing key: investigators will not survive a restart")
+		sealer.signingKeys = append(sealer.signingKeys, key)
+	}
+
+	for _, key := range cfg.EncryptionKeys {
+		derived := sha256.Sum256([]byte(key))
+		// A 32 byte key always yields a valid AES-256 block and GCM mode
+		block, _ := aes.NewCipher(derived[:])
+		aead, _ := cipher.NewGCM(block)
+		sealer.aeads = append(sealer.aeads, aead)
+	}
+
+	return sealer
+}
+
+// seal returns value bound to the cookie name, encrypted when encryption keys are configured
+func (s *cookieSealer) seal(name, value string) (string, error) {
+	mode := sealSigned
+	payload := value
+
+	if len(s.aeads) > 0 {
+		aead := s.aeads[0]
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return "", fmt.Errorf("failed to generate nonce: %w", err)
+		}
+		ciphertext := aead.Seal(nonce, nonce, []byte(value), []byte(name))
+		mode = sealEncrypted
+		payload = base64.RawURLEncoding.EncodeToString(ciphertext)
+	}
+
+	mac := s.sign(s.signingKeys[0], name, mode, payload)
+	return mode + "." + payload + "." + mac, nil
+}
+
+// open verifies a sealed value for the cookie name and returns the original value
+func (s *cookieSealer) open(name, sealed string) (string, error) {
+	parts := strings.Split(sealed, ".")
+	if len(parts) != 3 {
+		if s.allowUnsigned && !strings.Contains(sealed, ".") {
+			// Legacy cookie written before signing was introduced
+			return sealed, nil
+		}
+		return "", errors.ErrInvalidCookie
+	}
+	mode, payload, mac := parts[0], parts[1], parts[2]
+
+	if !s.verify(name, mode, payload, mac) {
+		return "", errors.ErrInvalidCookie
+	}
+
+	switch mode {
+	case sealSigned:
+		return payload, nil
+	case sealEncrypted:
+		return s.decrypt(name, payload)
+	default:
+		return "", errors.ErrInvalidCookie
+	}
+}
+
+// sign computes the MAC of a payload bound to the cookie name and seal mode
+func (s *cookieSealer) sign(key []byte, name, mode, payload string) string {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(name + "|" + mode + "|" + payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// verify checks the MAC against every configured signing key
+func (s *cookieSealer) verify(name, mode, payload, mac string) bool {
+	for _, key := range s.signingKeys {
+		if hmac.Equal([]byte(s.sign(key, name, mode, payload)), []byte(mac)) {
+			return true
+		}
+	}
+	return false
+}
+
+// decrypt opens an encrypted payload with any configured encryption key
+func (s *cookieSealer) decrypt(name, payload string) (string, error) {
+	ciphertext, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", errors.ErrInvalidCookie
+	}
+
+	for _, aead := range s.aeads {
+		if len(ciphertext) < aead.NonceSize() {
+			continue
+		}
+		nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+		if plaintext, err := aead.Open(nil, nonce, sealed, []byte(name)); err == nil {
+			return string(plaintext), nil
+		}
+	}
+
+	return "", errors.ErrInvalidCookie
+}
@@ -3,11 +3,13 @@ package storage
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/base32"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -21,51 +23,60 @@ import (
 type CookieStore struct {
 	config      *config.CookieConfig
 	exportStore ExportStore
+	sealer      *cookieSealer
 }
 
 // NewCookieStore creates a new CookieStore instance
-func NewCookieStore(cfg *config.CookieConfig, exportStore ExportStore) *CookieStore {
+func NewCookieStore(cfg *config.CookieConfig, exportStore ExportStore, logger *log.Logger) *CookieStore {
 	return &CookieStore{
 		config:      cfg,
 		exportStore: exportStore,
+		sealer:      newCookieSealer(cfg, logger),
 	}
 }
 
-// SaveInvestigator saves an investigator to a cookie
-func (cs *CookieStore) SaveInvestigator(w http.ResponseWriter, inv *models.Investigator) (string, error) {
+// SaveInvestigator saves an investigator to a cookie.
+// The browser holding the cookies is the owner, so ownerID is not used.
+func (cs *CookieStore) SaveInvestigator(ctx context.Context, ownerID string, inv *models.Investigator) (string, error) {
 	if inv == nil {
 		return "", errors.ErrInvalidData
 	}
 
+	session, err := cookieSessionFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
 	// Generate ID
 	id := cs.generateInvestigatorID(inv.Name)
 	inv.ID = id
 
 	// Encode investigator data
-	encodedValue, err := cs.encodeInvestigator(inv)
+	encodedValue, err := encodeInvestigator(inv)
 	if err != nil {
 		return "", fmt.Errorf("failed to encode investigator: %w", err)
 	}
 
-	// Check cookie size limit (4KB)
-	if len(encodedValue) > 4096 {
-		return "", errors.ErrCookieTooLarge
+	// Set cookie, chunked when above the 4KB limit
+	if err := cs.writeInvestigatorValue(session, id, encodedValue); err != nil {
+		return "", err
 	}
 
-	// Create and set cookie
-	cookie := cs.createCookie(id, encodedValue)
-	http.SetCookie(w, cookie)
-
 	return id, nil
 }
 
 // GetInvestigator retrieves an investigator from a cookie
-func (cs *CookieStore) GetInvestigator(r *http.Request, id string) (*models.Investigator, error) {
+func (cs *CookieStore) GetInvestigator(ctx context.Context, ownerID, id string) (*models.Investigator, error) {
 	if id == "" {
 		return nil, errors.ErrInvalidData
 	}
 
-	cookie, err := r.Cookie(id)
+	session, err := cookieSessionFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cookie, err := session.r.Cookie(id)
 	if err != nil {
 		if err == http.ErrNoCookie {
 			return nil, errors.ErrCookieNotFound
@@ -73,68 +84,65 @@ func (cs *CookieStore) GetInvestigator(r *http.Request, id string) (*models.Inve
 		return nil, fmt.Errorf("failed to get cookie: %w", err)
 	}
 
-	investigator, err := cs.decodeInvestigator(cookie.Value)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode investigator: %w", err)
-	}
-
-	return investigator, nil
+	return cs.readInvestigator(session.r, id, cookie.Value)
 }
 
 // UpdateInvestigator updates an existing investigator cookie
-func (cs *CookieStore) UpdateInvestigator(w http.ResponseWriter, id string, inv *models.Investigator) error {
+func (cs *CookieStore) UpdateInvestigator(ctx context.Context, ownerID, id string, inv *models.Investigator) error {
 	if id == "" || inv == nil {
 		return errors.ErrInvalidData
 	}
 
-	encodedValue, err := cs.encodeInvestigator(inv)
+	session, err := cookieSessionFromContext(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to encode investigator: %w", err)
+		return err
 	}
 
-	if len(encodedValue) > 4096 {
-		return errors.ErrCookieTooLarge
+	encodedValue, err := encodeInvestigator(inv)
+	if err != nil {
+		return fmt.Errorf("failed to encode investigator: %w", err)
 	}
 
-	cookie := cs.createCookie(id, encodedValue)
-	http.SetCookie(w, cookie)
-
-	return nil
+	return cs.writeInvestigatorValue(session, id, encodedValue)
 }
 
 // DeleteInvestigator removes an investigator cookie
-func (cs *CookieStore) DeleteInvestigator(w http.ResponseWriter, id string) error {
+func (cs *CookieStore) DeleteInvestigator(ctx context.Context, ownerID, id string) error {
 	if id == "" {
 		return errors.ErrInvalidData
 	}
 
-	cookie := &http.Cookie{
-		Name:     id,
-		Value:    "",
-		Path:     "/",
-		MaxAge:   -1,
-		Expires:  time.Now().Add(-24 * time.Hour),
-		HttpOnly: cs.config.HttpOnly,
-		Secure:   cs.config.Secure,
-		SameSite: http.SameSite(cs.config.SameSite),
+	session, err := cookieSessionFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Expire chunk cookies along with the manifest
+	for n := 0; n < storedChunkCount(session.r, id); n++ {
+		http.SetCookie(session.w, cs.expiredCookie(chunkCookieName(id, n)))
 	}
 
-	http.SetCookie(w, cookie)
+	http.SetCookie(session.w, cs.expiredCookie(id))
 	return nil
 }
 
 // ListInvestigators returns all investigators stored in cookies
-func (cs *CookieStore) ListInvestigators(r *http.Request) (map[string]*models.Investigator, error) {
+func (cs *CookieStore) ListInvestigators(ctx context.Context, ownerID string) (map[string]*models.Investigator, error) {
+	session, err := cookieSessionFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	investigators := make(map[string]*models.Investigator)
 
-	for _, cookie := range r.Cookies() {
-		if !strings.HasPrefix(cookie.Name, cs.config.Prefix) {
+	for _, cookie := range session.r.Cookies() {
+		if !strings.HasPrefix(cookie.Name, cs.config.Prefix) || isChunkCookie(session.r, cookie.Name) {
 			continue
 		}
 
-		investigator, err := cs.decodeInvestigator(cookie.Value)
+		investigator, err := cs.readInvestigator(session.r, cookie.Name, cookie.Value)
 		if err != nil {
-			// Skip invalid cookies instead of failing completely
+			// Skip invalid or tampered cookies instead of failing completely
 			continue
 		}
 
@@ -144,23 +152,30 @@ func (cs *CookieStore) ListInvestigators(r *http.Request) (map[string]*models.In
 	return investigators, nil
 }
 
-// ExportInvestigatorsList exports all investigators for sharing
-func (cs *CookieStore) ExportInvestigatorsList(r *http.Request) (string, error) {
-	cookies := make(map[string]string)
-
-	for _, cookie := range r.Cookies() {
-		if strings.HasPrefix(cookie.Name, cs.config.Prefix) {
-			cookies[cookie.Name] = cookie.Value
-		}
+// ExportInvestigatorsList exports all investigators for sharing.
+// Only investigators with valid cookies are exported, one entry per investigator.
+func (cs *CookieStore) ExportInvestigatorsList(ctx context.Context, ownerID string) (string, error) {
+	investigators, err := cs.ListInvestigators(ctx, ownerID)
+	if err != nil {
+		return "", err
 	}
 
-	if len(cookies) == 0 {
+	if len(investigators) == 0 {
 		return "", errors.ErrNotFound
 	}
 
-	data, err := json.Marshal(cookies)
+	entries := make(map[string]string, len(investigators))
+	for id, inv := range investigators {
+		encoded, err := encodeInvestigator(inv)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode investigator: %w", err)
+		}
+		entries[id] = encoded
+	}
+
+	data, err := json.Marshal(entries)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal cookies: %w", err)
+		return "", fmt.Errorf("failed to marshal investigators: %w", err)
 	}
 
 	encodedValue := base32.StdEncoding.EncodeToString(data)
@@ -175,11 +190,16 @@ func (cs *CookieStore) ExportInvestigatorsList(r *http.Request) (string, error)
 }
 
 // ImportInvestigatorsList imports investigators from a shared export
-func (cs *CookieStore) ImportInvestigatorsList(w http.ResponseWriter, uuid string) error {
+func (cs *CookieStore) ImportInvestigatorsList(ctx context.Context, ownerID, uuid string) error {
 	if uuid == "" {
 		return errors.ErrInvalidData
 	}
 
+	session, err := cookieSessionFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
 	encodedData, err := cs.exportStore.GetExport(uuid)
 	if err != nil {
 		return fmt.Errorf("failed to get export: %w", err)
@@ -190,14 +210,27 @@ func (cs *CookieStore) ImportInvestigatorsList(w http.ResponseWriter, uuid strin
 		return fmt.Errorf("failed to decode data: %w", err)
 	}
 
-	var cookies map[string]string
-	if err := json.Unmarshal(data, &cookies); err != nil {
-		return fmt.Errorf("failed to unmarshal cookies: %w", err)
+	var entries map[string]string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to unmarshal investigators: %w", err)
 	}
 
-	for name, value := range cookies {
-		cookie := cs.createCookie(name, value)
-		http.SetCookie(w, cookie)
+	for id, encoded := range entries {
+		inv, err := decodeInvestigator(encoded)
+		if err != nil {
+			// Skip invalid entries instead of failing completely
+			continue
+		}
+
+		// Exports from other backends don't use cookie names as IDs
+		if !strings.HasPrefix(id, cs.config.Prefix) {
+			id = cs.generateInvestigatorID(inv.Name)
+		}
+		inv.ID = id
+
+		if err := cs.writeInvestigatorValue(session, id, encoded); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -212,6 +245,26 @@ func (cs *CookieStore) generateInvestigatorID(name string) string {
 	return fmt.Sprintf("%s%d_%s", cs.config.Prefix, timestamp, safeName)
 }
 
+// readInvestigator reassembles, verifies and decodes the investigator stored under id
+func (cs *CookieStore) readInvestigator(r *http.Request, id, value string) (*models.Investigator, error) {
+	sealedValue, err := readInvestigatorValue(r, id, value)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedValue, err := cs.sealer.open(id, sealedValue)
+	if err != nil {
+		return nil, err
+	}
+
+	investigator, err := decodeInvestigator(encodedValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode investigator: %w", err)
+	}
+
+	return investigator, nil
+}
+
 // createCookie creates a properly configured HTTP cookie
 func (cs *CookieStore) createCookie(name, value string) *http.Cookie {
 	return &http.Cookie{
@@ -226,7 +279,7 @@ func (cs *CookieStore) createCookie(name, value string) *http.Cookie {
 }
 
 // encodeInvestigator compresses and encodes investigator data
-func (cs *CookieStore) encodeInvestigator(inv *models.Investigator) (string, error) {
+func encodeInvestigator(inv *models.Investigator) (string, error) {
 	// Marshal to JSON
 	data, err := inv.ToJSON()
 	if err != nil {
@@ -250,7 +303,7 @@ func (cs *CookieStore) encodeInvestigator(inv *models.Investigator) (string, err
 }
 
 // decodeInvestigator decodes and decompresses investigator data
-func (cs *CookieStore) decodeInvestigator(encodedData string) (*models.Investigator, error) {
+func decodeInvestigator(encodedData string) (*models.Investigator, error) {
 	// Decode from base64
 	data, err := base64.URLEncoding.DecodeString(encodedData)
 	if err != nil {
@@ -269,18 +322,14 @@ func (cs *CookieStore) decodeInvestigator(encodedData string) (*models.Investiga
 		return nil, fmt.Errorf("failed to decompress data: %w", err)
 	}
 
-	// Unmarshal from JSON
-	var investigator models.Investigator
-	if err := json.Unmarshal(decompressed, &investigator); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal investigator: %w", err)
-	}
+	return unmarshalInvestigator(decompressed)
+}
 
-	// Populate SpecialArchetypeRules from the Archetypes map (not serialized with json:"-")
-	if investigator.Archetype != nil && investigator.Archetype.Name != "" {
-		if archetype, exists := models.Archetypes[investigator.Archetype.Name]; exists {
-			investigator.Archetype.SpecialArchetypeRules = archetype.SpecialArchetypeRules
-		}
+// unmarshalInvestigator parses investigator JSON and restores fields that are not serialized
+func unmarshalInvestigator(data []byte) (*models.Investigator, error) {
+	investigator, err := models.InvestigatorFromJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal investigator: %w", err)
 	}
-
-	return &investigator, nil
-}
\ No newline at end of file
+	return investigator, nil
+}
@@ -0,0 +1,390 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"book-of-shadows/internal/config"
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/models"
+
+	"github.com/google/uuid"
+)
+
+// testLogger discards what the stores log during tests
+var testLogger = log.New(io.Discard, "", 0)
+
+// testCookieConfig returns a cookie config for testing
+func testCookieConfig() *config.CookieConfig {
+	return &config.CookieConfig{
+		Prefix:    "investigator",
+		OwnerName:   "owner",
+		MaxAge:      3600,
+		HttpOnly:    true,
+		SigningKeys: []string{"test-signing-key"},
+	}
+}
+
+// sessionContext returns a context carrying a cookie session for the request and recorder
+func sessionContext(w http.ResponseWriter, r *http.Request) context.Context {
+	return WithCookieSession(context.Background(), w, r)
+}
+
+// requestWithCookies builds a request carrying every live cookie set on the recorder
+func requestWithCookies(w *httptest.ResponseRecorder) *http.Request {
+	req := httptest.NewRequest("GET", "/", nil)
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.MaxAge < 0 {
+			continue
+		}
+		req.AddCookie(cookie)
+	}
+	return req
+}
+
+func TestCookieStoreSaveAndGet(t *testing.T) {
+	store := NewCookieStore(testCookieConfig(), nil, testLogger)
+
+	t.Run("saves and retrieves investigator", func(t *testing.T) {
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		inv.Name = "Harvey Walters"
+
+		w := httptest.NewRecorder()
+		id, err := store.SaveInvestigator(sessionContext(w, httptest.NewRequest("GET", "/", nil)), "", inv)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		retrieved, err := store.GetInvestigator(sessionContext(httptest.NewRecorder(), requestWithCookies(w)), "", id)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if retrieved.Name != "Harvey Walters" {
+			t.Errorf("expected name Harvey Walters, got %s", retrieved.Name)
+		}
+	})
+
+	t.Run("returns error for missing cookie", func(t *testing.T) {
+		ctx := sessionContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+		_, err := store.GetInvestigator(ctx, "", "investigator_missing")
+		if err != errors.ErrCookieNotFound {
+			t.Errorf("expected ErrCookieNotFound, got %v", err)
+		}
+	})
+
+	t.Run("returns error without cookie session", func(t *testing.T) {
+		_, err := store.SaveInvestigator(context.Background(), "", models.RandomInvestigator(models.Pulp, models.Modern))
+		if err != errors.ErrNoCookieSession {
+			t.Errorf("expected ErrNoCookieSession, got %v", err)
+		}
+	})
+}
+
+func TestCookieStoreDeleteAndList(t *testing.T) {
+	store := NewCookieStore(testCookieConfig(), nil, testLogger)
+
+	w := httptest.NewRecorder()
+	ctx := sessionContext(w, httptest.NewRequest("GET", "/", nil))
+	first := models.SeededInvestigator(models.Pulp, models.Modern, 1) // fits in a single cookie
+	first.Name = "First"
+	second := models.SeededInvestigator(models.Pulp, models.Modern, 2)
+	second.Name = "Second"
+	id, _ := store.SaveInvestigator(ctx, "", first)
+	store.SaveInvestigator(ctx, "", second)
+
+	t.Run("lists investigators from cookies", func(t *testing.T) {
+		investigators, err := store.ListInvestigators(sessionContext(httptest.NewRecorder(), requestWithCookies(w)), "")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(investigators) != 2 {
+			t.Errorf("expected 2 investigators, got %d", len(investigators))
+		}
+	})
+
+	t.Run("expires cookie on delete", func(t *testing.T) {
+		dw := httptest.NewRecorder()
+		if err := store.DeleteInvestigator(sessionContext(dw, requestWithCookies(w)), "", id); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		cookies := dw.Result().Cookies()
+		if len(cookies) != 1 || cookies[0].Name != id || cookies[0].MaxAge >= 0 {
+			t.Error("expected an expired investigator cookie")
+		}
+	})
+}
+
+// largeInvestigator returns an investigator whose encoded cookie exceeds a single cookie
+func largeInvestigator(skills int) *models.Investigator {
+	inv := models.RandomInvestigator(models.Pulp, models.Modern)
+	for i := 0; i < skills; i++ {
+		name := uuid.New().String()
+		inv.Skills[name] = models.Skill{Name: name, Value: i % 90}
+	}
+	return inv
+}
+
+func TestCookieStoreChunking(t *testing.T) {
+	store := NewCookieStore(testCookieConfig(), nil, testLogger)
+
+	inv := largeInvestigator(400)
+	w := httptest.NewRecorder()
+	id, err := store.SaveInvestigator(sessionContext(w, httptest.NewRequest("GET", "/", nil)), "", inv)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	req := requestWithCookies(w)
+
+	t.Run("splits oversized investigator behind a manifest", func(t *testing.T) {
+		manifest, err := req.Cookie(id)
+		if err != nil {
+			t.Fatalf("expected manifest cookie, got %v", err)
+		}
+		count, ok := parseChunkManifest(manifest.Value)
+		if !ok || count < 2 {
+			t.Fatalf("expected a manifest with several chunks, got %q", manifest.Value)
+		}
+		for _, cookie := range w.Result().Cookies() {
+			if len(cookie.Value) > maxCookieValueSize {
+				t.Errorf("cookie %s exceeds the single cookie limit", cookie.Name)
+			}
+		}
+	})
+
+	t.Run("reassembles chunks on get and list", func(t *testing.T) {
+		ctx := sessionContext(httptest.NewRecorder(), req)
+		retrieved, err := store.GetInvestigator(ctx, "", id)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(retrieved.Skills) != len(inv.Skills) {
+			t.Errorf("expected %d skills, got %d", len(inv.Skills), len(retrieved.Skills))
+		}
+
+		investigators, err := store.ListInvestigators(ctx, "")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, ok := investigators[id]; !ok || len(investigators) != 1 {
+			t.Errorf("expected only %s to be listed, got %d investigators", id, len(investigators))
+		}
+	})
+
+	t.Run("reports missing chunks as invalid cookie", func(t *testing.T) {
+		partial := httptest.NewRequest("GET", "/", nil)
+		for _, cookie := range req.Cookies() {
+			if cookie.Name != chunkCookieName(id, 0) {
+				partial.AddCookie(cookie)
+			}
+		}
+		_, err := store.GetInvestigator(sessionContext(httptest.NewRecorder(), partial), "", id)
+		if err != errors.ErrInvalidCookie {
+			t.Errorf("expected ErrInvalidCookie, got %v", err)
+		}
+	})
+
+	t.Run("expires leftover chunks when shrinking", func(t *testing.T) {
+		count := storedChunkCount(req, id)
+		uw := httptest.NewRecorder()
+		small := models.SeededInvestigator(models.Pulp, models.Modern, 1) // fits in a single cookie
+		if err := store.UpdateInvestigator(sessionContext(uw, req), "", id, small); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		expired := 0
+		for _, cookie := range uw.Result().Cookies() {
+			if cookie.MaxAge < 0 {
+				expired++
+			}
+		}
+		if expired != count {
+			t.Errorf("expected %d expired chunks, got %d", count, expired)
+		}
+	})
+
+	t.Run("expires chunks on delete", func(t *testing.T) {
+		dw := httptest.NewRecorder()
+		if err := store.DeleteInvestigator(sessionContext(dw, req), "", id); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		cookies := dw.Result().Cookies()
+		if len(cookies) != storedChunkCount(req, id)+1 {
+			t.Errorf("expected manifest and %d chunks expired, got %d cookies", storedChunkCount(req, id), len(cookies))
+		}
+	})
+}
+
+func TestCookieStoreBudget(t *testing.T) {
+	cfg := testCookieConfig()
+	cfg.MaxTotalSize = 8 * 1024
+	store := NewCookieStore(cfg, nil, testLogger)
+
+	t.Run("rejects investigators above the total budget", func(t *testing.T) {
+		ctx := sessionContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+		_, err := store.SaveInvestigator(ctx, "", largeInvestigator(600))
+		if err != errors.ErrCookieTooLarge {
+			t.Errorf("expected ErrCookieTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("counts cookies already held by the browser", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.AddCookie(&http.Cookie{Name: "investigator_other", Value: strings.Repeat("a", 7*1024)})
+
+		ctx := sessionContext(httptest.NewRecorder(), req)
+		_, err := store.SaveInvestigator(ctx, "", models.RandomInvestigator(models.Pulp, models.Modern))
+		if err != errors.ErrCookieTooLarge {
+			t.Errorf("expected ErrCookieTooLarge, got %v", err)
+		}
+	})
+}
+
+func TestCookieStoreSigning(t *testing.T) {
+	save := func(t *testing.T, store *CookieStore) (string, *http.Request) {
+		t.Helper()
+		inv := models.SeededInvestigator(models.Pulp, models.Modern, 1) // fits in a single cookie
+		inv.Name = "Signed"
+		w := httptest.NewRecorder()
+		id, err := store.SaveInvestigator(sessionContext(w, httptest.NewRequest("GET", "/", nil)), "", inv)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		return id, requestWithCookies(w)
+	}
+
+	t.Run("rejects tampered cookies", func(t *testing.T) {
+		store := NewCookieStore(testCookieConfig(), nil, testLogger)
+		id, req := save(t, store)
+
+		cookie, _ := req.Cookie(id)
+		unsigned, _ := encodeInvestigator(&models.Investigator{Name: "Tampered"})
+		parts := strings.Split(cookie.Value, ".")
+		tampered := httptest.NewRequest("GET", "/", nil)
+		tampered.AddCookie(&http.Cookie{Name: id, Value: parts[0] + "." + unsigned + "." + parts[2]})
+
+		_, err := store.GetInvestigator(sessionContext(httptest.NewRecorder(), tampered), "", id)
+		if err != errors.ErrInvalidCookie {
+			t.Errorf("expected ErrInvalidCookie, got %v", err)
+		}
+	})
+
+	t.Run("rejects unsigned cookies", func(t *testing.T) {
+		store := NewCookieStore(testCookieConfig(), nil, testLogger)
+		unsigned, _ := encodeInvestigator(&models.Investigator{Name: "Unsigned"})
+		req := httptest.NewRequest("GET", "/", nil)
+		req.AddCookie(&http.Cookie{Name: "investigator_unsigned", Value: unsigned})
+
+		_, err := store.GetInvestigator(sessionContext(httptest.NewRecorder(), req), "", "investigator_unsigned")
+		if err != errors.ErrInvalidCookie {
+			t.Errorf("expected ErrInvalidCookie, got %v", err)
+		}
+	})
+
+	t.Run("accepts unsigned cookies when allowed", func(t *testing.T) {
+		cfg := testCookieConfig()
+		cfg.AllowUnsigned = true
+		store := NewCookieStore(cfg, nil, testLogger)
+		unsigned, _ := encodeInvestigator(&models.Investigator{Name: "Legacy"})
+		req := httptest.NewRequest("GET", "/", nil)
+		req.AddCookie(&http.Cookie{Name: "investigator_legacy", Value: unsigned})
+
+		inv, err := store.GetInvestigator(sessionContext(httptest.NewRecorder(), req), "", "investigator_legacy")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if inv.Name != "Legacy" {
+			t.Errorf("expected name Legacy, got %s", inv.Name)
+		}
+
+		// The next write seals the legacy cookie
+		w := httptest.NewRecorder()
+		if err := store.UpdateInvestigator(sessionContext(w, req), "", "investigator_legacy", inv); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		cookie, err := requestWithCookies(w).Cookie("investigator_legacy")
+		if err != nil || !strings.HasPrefix(cookie.Value, sealSigned+".") {
+			t.Errorf("expected the rewritten cookie to be signed, got %v", cookie)
+		}
+	})
+
+	t.Run("keeps the ephemeral key out of the log", func(t *testing.T) {
+		var logs strings.Builder
+		cfg := testCookieConfig()
+		cfg.SigningKeys = nil
+		sealer := newCookieSealer(cfg, log.New(&logs, "", 0))
+
+		key := sealer.signingKeys[0]
+		for _, encoded := range []string{string(key), hex.EncodeToString(key), base64.StdEncoding.EncodeToString(key), base64.RawURLEncoding.EncodeToString(key)} {
+			if strings.Contains(logs.String(), encoded) {
+				t.Fatalf("expected the key to stay out of the log, got %q", logs.String())
+			}
+		}
+		if !strings.Contains(logs.String(), "ephemeral signing key") {
+			t.Errorf("expected a warning about the ephemeral key, got %q", logs.String())
+		}
+	})
+
+	t.Run("verifies cookies signed with rotated keys", func(t *testing.T) {
+		id, req := save(t, NewCookieStore(testCookieConfig(), nil, testLogger))
+
+		cfg := testCookieConfig()
+		cfg.SigningKeys = []string{"new-signing-key", "test-signing-key"}
+		rotated := NewCookieStore(cfg, nil, testLogger)
+
+		inv, err := rotated.GetInvestigator(sessionContext(httptest.NewRecorder(), req), "", id)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if inv.Name != "Signed" {
+			t.Errorf("expected name Signed, got %s", inv.Name)
+		}
+	})
+
+	t.Run("encrypts cookies when keys are configured", func(t *testing.T) {
+		cfg := testCookieConfig()
+		cfg.EncryptionKeys = []string{"old-encryption-key"}
+		id, req := save(t, NewCookieStore(cfg, nil, testLogger))
+
+		cookie, _ := req.Cookie(id)
+		sealed, err := readInvestigatorValue(req, id, cookie.Value)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !strings.HasPrefix(sealed, sealEncrypted+".") {
+			t.Error("expected an encrypted cookie")
+		}
+
+		cfg = testCookieConfig()
+		cfg.EncryptionKeys = []string{"new-encryption-key", "old-encryption-key"}
+		inv, err := NewCookieStore(cfg, nil, testLogger).GetInvestigator(sessionContext(httptest.NewRecorder(), req), "", id)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if inv.Name != "Signed" {
+			t.Errorf("expected name Signed, got %s", inv.Name)
+		}
+	})
+
+	t.Run("rejects cookies moved to another name", func(t *testing.T) {
+		store := NewCookieStore(testCookieConfig(), nil, testLogger)
+		id, req := save(t, store)
+
+		cookie, _ := req.Cookie(id)
+		moved := httptest.NewRequest("GET", "/", nil)
+		moved.AddCookie(&http.Cookie{Name: "investigator_moved", Value: cookie.Value})
+
+		_, err := store.GetInvestigator(sessionContext(httptest.NewRecorder(), moved), "", "investigator_moved")
+		if err != errors.ErrInvalidCookie {
+			t.Errorf("expected ErrInvalidCookie, got %v", err)
+		}
+	})
+}
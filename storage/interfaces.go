@@ -1,14 +1,26 @@
 package storage
 
 import (
+	"context"
+
 	"book-of-shadows/models"
-	"net/http"
 )
 
 // Store defines the interface for data storage operations
 type Store interface {
 	ExportStore
 	InvestigatorStore
+	UserStore
+	RevisionStore
+	CampaignStore
+
+	// ClaimInvestigators moves every investigator of an anonymous owner to a user account
+	ClaimInvestigators(ctx context.Context, fromOwnerID, toUserID string) error
+
+	// GetMemberInvestigator loads the sheet of a campaign member from its owner's store
+	GetMemberInvestigator(ctx context.Context, member *models.CampaignMember) (*models.Investigator, error)
+	// UpdateMemberInvestigator saves a keeper's change to the sheet of a campaign member
+	UpdateMemberInvestigator(ctx context.Context, member *models.CampaignMember, inv *models.Investigator) error
 }
 
 // ExportStore handles export/import operations
@@ -18,13 +30,45 @@ type ExportStore interface {
 	DeleteExpiredExports() error
 }
 
-// InvestigatorStore handles investigator CRUD operations
+// InvestigatorStore handles investigator CRUD operations scoped to an owner
 type InvestigatorStore interface {
-	SaveInvestigator(w http.ResponseWriter, inv *models.Investigator) (string, error)
-	GetInvestigator(r *http.Request, id string) (*models.Investigator, error)
-	UpdateInvestigator(w http.ResponseWriter, id string, inv *models.Investigator) error
-	DeleteInvestigator(w http.ResponseWriter, id string) error
-	ListInvestigators(r *http.Request) (map[string]*models.Investigator, error)
-	ExportInvestigatorsList(r *http.Request) (string, error)
-	ImportInvestigatorsList(w http.ResponseWriter, uuid string) error
-}
\ No newline at end of file
+	SaveInvestigator(ctx context.Context, ownerID string, inv *models.Investigator) (string, error)
+	GetInvestigator(ctx context.Context, ownerID, id string) (*models.Investigator, error)
+	UpdateInvestigator(ctx context.Context, ownerID, id string, inv *models.Investigator) error
+	DeleteInvestigator(ctx context.Context, ownerID, id string) error
+	ListInvestigators(ctx context.Context, ownerID string) (map[string]*models.Investigator, error)
+	ExportInvestigatorsList(ctx context.Context, ownerID string) (string, error)
+	ImportInvestigatorsList(ctx context.Context, ownerID, uuid string) error
+}
+
+// UserStore handles user accounts and their login sessions
+type UserStore interface {
+	CreateUser(ctx context.Context, username, password string) (*models.User, error)
+	AuthenticateUser(ctx context.Context, username, password string) (*models.User, error)
+	CreateSession(ctx context.Context, userID string) (string, error)
+	GetSessionUser(ctx context.Context, token string) (*models.User, error)
+	DeleteSession(ctx context.Context, token string) error
+}
+
+// RevisionStore keeps the change history of investigators scoped to an owner
+type RevisionStore interface {
+	SaveRevision(ctx context.Context, ownerID string, rev *models.Revision) error
+	GetRevision(ctx context.Context, ownerID, investigatorID string, revisionID int64) (*models.Revision, error)
+	ListRevisions(ctx context.Context, ownerID, investigatorID string) ([]*models.Revision, error)
+	DeleteRevisions(ctx context.Context, ownerID, investigatorID string) error
+}
+
+// CampaignStore handles campaigns run by a keeper and the investigators that joined them.
+// Members keep a copy of their sheet so the keeper can see it without access to the
+// player's investigator store.
+type CampaignStore interface {
+	CreateCampaign(ctx context.Context, keeperID string, campaign *models.Campaign) (string, error)
+	GetCampaign(ctx context.Context, keeperID, id string) (*models.Campaign, error)
+	ListCampaigns(ctx context.Context, keeperID string) ([]*models.Campaign, error)
+	DeleteCampaign(ctx context.Context, keeperID, id string) error
+	JoinCampaign(ctx context.Context, joinCode, ownerID string, inv *models.Investigator) (*models.Campaign, error)
+	RemoveCampaignMember(ctx context.Context, keeperID, campaignID, investigatorID string) error
+	GetCampaignMember(ctx context.Context, keeperID, campaignID, investigatorID string) (*models.CampaignMember, error)
+	SyncCampaignMember(ctx context.Context, ownerID, investigatorID string, inv *models.Investigator) error
+	LeaveCampaigns(ctx context.Context, ownerID, investigatorID string) error
+}
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// migration is a single versioned schema change
+type migration struct {
+	version int
+	name    string
+	query   string
+}
+
+// schemaMigrations holds the schema history of the application tables.
+// New migrations must be appended with an increasing version; never edit an applied one.
+var schemaMigrations = []migration{
+	{
+		version: 1,
+		name:    "create investigators",
+		query: `
+			CREATE TABLE IF NOT EXISTS investigators (
+				id TEXT PRIMARY KEY,
+				token TEXT NOT NULL,
+				data TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL,
+				updated_at TIMESTAMP NOT NULL
+			);
+		`,
+	},
+	{
+		version: 2,
+		name:    "scope investigators by owner",
+		query: `
+			ALTER TABLE investigators ADD COLUMN owner_id TEXT NOT NULL DEFAULT '';
+			-- Kept for their browser to claim through the access cookie, see legacyOwnerPrefix
+			UPDATE investigators SET owner_id = 'legacy:' || token;
+			ALTER TABLE investigators DROP COLUMN token;
+			CREATE INDEX IF NOT EXISTS idx_investigators_owner_id ON investigators(owner_id);
+		`,
+	},
+	{
+		version: 3,
+		name:    "create users and sessions",
+		query: `
+			CREATE TABLE IF NOT EXISTS users (
+				id TEXT PRIMARY KEY,
+				username TEXT NOT NULL UNIQUE COLLATE NOCASE,
+				password_hash TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL
+			);
+			CREATE TABLE IF NOT EXISTS sessions (
+				token_hash TEXT PRIMARY KEY,
+				user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				created_at TIMESTAMP NOT NULL,
+				expires_at TIMESTAMP NOT NULL
+			);
+			CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+			CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions(expires_at);
+		`,
+	},
+	{
+		version: 4,
+		name:    "create investigator revisions",
+		query: `
+			CREATE TABLE IF NOT EXISTS investigator_revisions (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				owner_id TEXT NOT NULL,
+				investigator_id TEXT NOT NULL,
+				author_id TEXT NOT NULL,
+				author_name TEXT NOT NULL DEFAULT '',
+				section TEXT NOT NULL,
+				field TEXT NOT NULL,
+				old_value TEXT NOT NULL,
+				new_value TEXT NOT NULL,
+				snapshot TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL
+			);
+			CREATE INDEX IF NOT EXISTS idx_investigator_revisions_owner_investigator
+				ON investigator_revisions(owner_id, investigator_id);
+		`,
+	},
+	{
+		version: 5,
+		name:    "create campaigns",
+		query: `
+			CREATE TABLE IF NOT EXISTS campaigns (
+				id TEXT PRIMARY KEY,
+				keeper_id TEXT NOT NULL,
+				keeper_name TEXT NOT NULL DEFAULT '',
+				name TEXT NOT NULL,
+				era INTEGER NOT NULL,
+				game_mode INTEGER NOT NULL,
+				join_code TEXT NOT NULL UNIQUE,
+				created_at TIMESTAMP NOT NULL
+			);
+			CREATE INDEX IF NOT EXISTS idx_campaigns_keeper_id ON campaigns(keeper_id);
+			CREATE TABLE IF NOT EXISTS campaign_members (
+				campaign_id TEXT NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+				owner_id TEXT NOT NULL,
+				investigator_id TEXT NOT NULL,
+				data TEXT NOT NULL,
+				joined_at TIMESTAMP NOT NULL,
+				PRIMARY KEY (campaign_id, owner_id, investigator_id)
+			);
+			CREATE INDEX IF NOT EXISTS idx_campaign_members_owner_investigator
+				ON campaign_members(owner_id, investigator_id);
+		`,
+	},
+}
+
+// migrate applies every migration newer than the recorded schema version
+func migrate(db *sql.DB, migrations []migration) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL
+		);
+	`
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var current int
+	if err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := applyMigration(db, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyMigration runs a migration and records it in a single transaction
+func applyMigration(db *sql.DB, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %d: %w", m.version, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(m.query); err != nil {
+		return fmt.Errorf("failed to apply migration %d (%s): %w", m.version, m.name, err)
+	}
+
+	query := `INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`
+	if _, err := tx.Exec(query, m.version, m.name, time.Now()); err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", m.version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %d: %w", m.version, err)
+	}
+
+	return nil
+}
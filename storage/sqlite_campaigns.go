@@ -0,0 +1,350 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/models"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+)
+
+const (
+	// joinCodeAlphabet leaves out characters that are easily confused when read aloud
+	joinCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	// joinCodeLength gives about 40 bits of entropy
+	joinCodeLength = 8
+	// joinCodeAttempts bounds retries on the unlikely event of a join code collision
+	joinCodeAttempts = 5
+)
+
+// SQLiteCampaignStore implements the CampaignStore interface using SQLite.
+// A campaign is only visible to its keeper; players reach it through its join code.
+type SQLiteCampaignStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteCampaignStore creates a new SQLiteCampaignStore and migrates its schema
+func NewSQLiteCampaignStore(db *sql.DB) (*SQLiteCampaignStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database is required")
+	}
+
+	if err := migrate(db, schemaMigrations); err != nil {
+		return nil, fmt.Errorf("failed to migrate campaigns schema: %w", err)
+	}
+
+	return &SQLiteCampaignStore{db: db}, nil
+}
+
+// CreateCampaign creates a campaign run by the keeper with a fresh join code
+func (s *SQLiteCampaignStore) CreateCampaign(ctx context.Context, keeperID string, campaign *models.Campaign) (string, error) {
+	if keeperID == "" || campaign == nil || strings.TrimSpace(campaign.Name) == "" {
+		return "", errors.ErrInvalidData
+	}
+
+	campaign.ID = uuid.New().String()
+	campaign.KeeperID = keeperID
+	campaign.CreatedAt = time.Now()
+	campaign.Members = []models.CampaignMember{}
+
+	query := `
+		INSERT INTO campaigns (id, keeper_id, keeper_name, name, era, game_mode, join_code, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	for attempt := 0; ; attempt++ {
+		code, err := generateJoinCode()
+		if err != nil {
+			return "", err
+		}
+		campaign.JoinCode = code
+
+		_, err = s.db.ExecContext(ctx, query, campaign.ID, keeperID, campaign.KeeperName, campaign.Name,
+			int(campaign.Era), int(campaign.GameMode), campaign.JoinCode, campaign.CreatedAt)
+		if err == nil {
+			return campaign.ID, nil
+		}
+
+		var sqliteErr sqlite3.Error
+		if !stderrors.As(err, &sqliteErr) || sqliteErr.ExtendedCode != sqlite3.ErrConstraintUnique || attempt+1 >= joinCodeAttempts {
+			return "", fmt.Errorf("failed to create campaign: %w", err)
+		}
+	}
+}
+
+// GetCampaign retrieves one of the keeper's campaigns with its members
+func (s *SQLiteCampaignStore) GetCampaign(ctx context.Context, keeperID, id string) (*models.Campaign, error) {
+	if keeperID == "" || id == "" {
+		return nil, errors.ErrInvalidData
+	}
+
+	query := `
+		SELECT id, keeper_id, keeper_name, name, era, game_mode, join_code, created_at
+		FROM campaigns WHERE id = ? AND keeper_id = ?
+	`
+	campaign, err := scanCampaign(s.db.QueryRowContext(ctx, query, id, keeperID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	if campaign.Members, err = s.listMembers(ctx, campaign.ID); err != nil {
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+// ListCampaigns returns the keeper's campaigns with their members, newest first
+func (s *SQLiteCampaignStore) ListCampaigns(ctx context.Context, keeperID string) ([]*models.Campaign, error) {
+	campaigns := make([]*models.Campaign, 0)
+	if keeperID == "" {
+		return campaigns, nil
+	}
+
+	query := `
+		SELECT id, keeper_id, keeper_name, name, era, game_mode, join_code, created_at
+		FROM campaigns WHERE keeper_id = ? ORDER BY created_at DESC
+	`
+	rows, err := s.db.QueryContext(ctx, query, keeperID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		campaign, err := scanCampaign(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan campaign: %w", err)
+		}
+		campaigns = append(campaigns, campaign)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+
+	for _, campaign := range campaigns {
+		if campaign.Members, err = s.listMembers(ctx, campaign.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return campaigns, nil
+}
+
+// DeleteCampaign removes one of the keeper's campaigns and its memberships
+func (s *SQLiteCampaignStore) DeleteCampaign(ctx context.Context, keeperID, id string) error {
+	if keeperID == "" || id == "" {
+		return errors.ErrInvalidData
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM campaigns WHERE id = ? AND keeper_id = ?`, id, keeperID)
+	if err != nil {
+		return fmt.Errorf("failed to delete campaign: %w", err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM campaign_members WHERE campaign_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete campaign members: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// JoinCampaign adds the owner's investigator to the campaign with the join code.
+// Joining again refreshes the stored copy of the sheet.
+func (s *SQLiteCampaignStore) JoinCampaign(ctx context.Context, joinCode, ownerID string, inv *models.Investigator) (*models.Campaign, error) {
+	joinCode = strings.ToUpper(strings.TrimSpace(joinCode))
+	if joinCode == "" || ownerID == "" || inv == nil || inv.ID == "" {
+		return nil, errors.ErrInvalidData
+	}
+
+	query := `
+		SELECT id, keeper_id, keeper_name, name, era, game_mode, join_code, created_at
+		FROM campaigns WHERE join_code = ?
+	`
+	campaign, err := scanCampaign(s.db.QueryRowContext(ctx, query, joinCode))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find campaign: %w", err)
+	}
+
+	data, err := inv.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode investigator: %w", err)
+	}
+
+	insert := `
+		INSERT INTO campaign_members (campaign_id, owner_id, investigator_id, data, joined_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (campaign_id, owner_id, investigator_id) DO UPDATE SET data = excluded.data
+	`
+	if _, err := s.db.ExecContext(ctx, insert, campaign.ID, ownerID, inv.ID, string(data), time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to join campaign: %w", err)
+	}
+
+	return campaign, nil
+}
+
+// RemoveCampaignMember removes an investigator from one of the keeper's campaigns
+func (s *SQLiteCampaignStore) RemoveCampaignMember(ctx context.Context, keeperID, campaignID, investigatorID string) error {
+	if keeperID == "" || campaignID == "" || investigatorID == "" {
+		return errors.ErrInvalidData
+	}
+
+	query := `
+		DELETE FROM campaign_members
+		WHERE investigator_id = ? AND campaign_id IN (SELECT id FROM campaigns WHERE id = ? AND keeper_id = ?)
+	`
+	result, err := s.db.ExecContext(ctx, query, investigatorID, campaignID, keeperID)
+	if err != nil {
+		return fmt.Errorf("failed to remove campaign member: %w", err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetCampaignMember retrieves an investigator that joined one of the keeper's campaigns
+func (s *SQLiteCampaignStore) GetCampaignMember(ctx context.Context, keeperID, campaignID, investigatorID string) (*models.CampaignMember, error) {
+	if keeperID == "" || campaignID == "" || investigatorID == "" {
+		return nil, errors.ErrInvalidData
+	}
+
+	query := `
+		SELECT investigator_id, owner_id, data, joined_at FROM campaign_members
+		WHERE investigator_id = ? AND campaign_id IN (SELECT id FROM campaigns WHERE id = ? AND keeper_id = ?)
+	`
+	member := &models.CampaignMember{}
+	var data string
+	err := s.db.QueryRowContext(ctx, query, investigatorID, campaignID, keeperID).
+		Scan(&member.InvestigatorID, &member.OwnerID, &data, &member.JoinedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get campaign member: %w", err)
+	}
+
+	if member.Investigator, err = unmarshalInvestigator([]byte(data)); err != nil {
+		return nil, fmt.Errorf("failed to decode campaign member: %w", err)
+	}
+
+	return member, nil
+}
+
+// SyncCampaignMember refreshes the copy of the sheet in every campaign the investigator joined
+func (s *SQLiteCampaignStore) SyncCampaignMember(ctx context.Context, ownerID, investigatorID string, inv *models.Investigator) error {
+	if ownerID == "" || investigatorID == "" || inv == nil {
+		return errors.ErrInvalidData
+	}
+
+	data, err := inv.ToJSON()
+	if err != nil {
+		return fmt.Errorf("failed to encode investigator: %w", err)
+	}
+
+	query := `UPDATE campaign_members SET data = ? WHERE owner_id = ? AND investigator_id = ?`
+	if _, err := s.db.ExecContext(ctx, query, string(data), ownerID, investigatorID); err != nil {
+		return fmt.Errorf("failed to sync campaign member: %w", err)
+	}
+
+	return nil
+}
+
+// LeaveCampaigns removes the investigator from every campaign it joined
+func (s *SQLiteCampaignStore) LeaveCampaigns(ctx context.Context, ownerID, investigatorID string) error {
+	if ownerID == "" || investigatorID == "" {
+		return errors.ErrInvalidData
+	}
+
+	query := `DELETE FROM campaign_members WHERE owner_id = ? AND investigator_id = ?`
+	if _, err := s.db.ExecContext(ctx, query, ownerID, investigatorID); err != nil {
+		return fmt.Errorf("failed to leave campaigns: %w", err)
+	}
+
+	return nil
+}
+
+// listMembers returns the members of a campaign in the order they joined
+func (s *SQLiteCampaignStore) listMembers(ctx context.Context, campaignID string) ([]models.CampaignMember, error) {
+	query := `SELECT investigator_id, owner_id, data, joined_at FROM campaign_members WHERE campaign_id = ? ORDER BY joined_at`
+	rows, err := s.db.QueryContext(ctx, query, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaign members: %w", err)
+	}
+	defer rows.Close()
+
+	members := make([]models.CampaignMember, 0)
+	for rows.Next() {
+		var member models.CampaignMember
+		var data string
+		if err := rows.Scan(&member.InvestigatorID, &member.OwnerID, &data, &member.JoinedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan campaign member: %w", err)
+		}
+
+		investigator, err := unmarshalInvestigator([]byte(data))
+		if err != nil {
+			// Skip invalid rows instead of failing completely
+			continue
+		}
+		member.Investigator = investigator
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list campaign members: %w", err)
+	}
+
+	return members, nil
+}
+
+// scanCampaign reads a campaign row without its members
+func scanCampaign(row rowScanner) (*models.Campaign, error) {
+	var campaign models.Campaign
+	var era, gameMode int
+	err := row.Scan(&campaign.ID, &campaign.KeeperID, &campaign.KeeperName, &campaign.Name,
+		&era, &gameMode, &campaign.JoinCode, &campaign.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	campaign.Era = models.Era(era)
+	campaign.GameMode = models.GameMode(gameMode)
+	campaign.Members = []models.CampaignMember{}
+	return &campaign, nil
+}
+
+// generateJoinCode returns a random code players type in to join a campaign
+func generateJoinCode() (string, error) {
+	raw := make([]byte, joinCodeLength)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate join code: %w", err)
+	}
+
+	code := make([]byte, joinCodeLength)
+	for i, b := range raw {
+		code[i] = joinCodeAlphabet[int(b)%len(joinCodeAlphabet)]
+	}
+	return string(code), nil
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/models"
+)
+
+// newTestCampaignStore returns a SQLiteCampaignStore backed by a temporary database
+func newTestCampaignStore(t *testing.T) *SQLiteCampaignStore {
+	t.Helper()
+
+	sqliteStore, err := NewSQLiteStore(testConfig(t))
+	if err != nil {
+		t.Fatalf("failed to create SQLite store: %v", err)
+	}
+	t.Cleanup(func() { sqliteStore.Close() })
+
+	store, err := NewSQLiteCampaignStore(sqliteStore.db)
+	if err != nil {
+		t.Fatalf("failed to create campaign store: %v", err)
+	}
+	return store
+}
+
+func TestSQLiteCampaignStore(t *testing.T) {
+	store := newTestCampaignStore(t)
+	ctx := context.Background()
+
+	campaign := &models.Campaign{Name: "Masks of Nyarlathotep", Era: models.Twenties, GameMode: models.Pulp}
+	id, err := store.CreateCampaign(ctx, "keeper-1", campaign)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if campaign.JoinCode == "" {
+		t.Fatal("expected a join code")
+	}
+
+	inv := models.RandomInvestigator(models.Pulp, models.Modern)
+	inv.ID = "inv-1"
+	inv.Name = "Jackson Elias"
+
+	t.Run("joins with the code", func(t *testing.T) {
+		joined, err := store.JoinCampaign(ctx, campaign.JoinCode, "player-1", inv)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if joined.ID != id {
+			t.Errorf("expected campaign %s, got %s", id, joined.ID)
+		}
+
+		retrieved, err := store.GetCampaign(ctx, "keeper-1", id)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(retrieved.Members) != 1 || retrieved.Members[0].Investigator.Name != "Jackson Elias" {
+			t.Fatalf("expected Jackson Elias as the only member, got %+v", retrieved.Members)
+		}
+		if retrieved.Era != models.Twenties || retrieved.GameMode != models.Pulp {
+			t.Errorf("expected era and game mode to round trip, got %v %v", retrieved.Era, retrieved.GameMode)
+		}
+	})
+
+	t.Run("rejects unknown codes", func(t *testing.T) {
+		if _, err := store.JoinCampaign(ctx, "NOPE", "player-1", inv); err != errors.ErrNotFound {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("syncs the member's sheet", func(t *testing.T) {
+		inv.Name = "Jackson Elias (wounded)"
+		if err := store.SyncCampaignMember(ctx, "player-1", "inv-1", inv); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		retrieved, _ := store.GetCampaign(ctx, "keeper-1", id)
+		if party := retrieved.Party(); len(party) != 1 || party[0].Name != "Jackson Elias (wounded)" {
+			t.Errorf("expected synced name in party, got %+v", party)
+		}
+	})
+
+	t.Run("gets a member with its owner", func(t *testing.T) {
+		member, err := store.GetCampaignMember(ctx, "keeper-1", id, "inv-1")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if member.OwnerID != "player-1" || member.Investigator.Name != inv.Name {
+			t.Errorf("expected player-1's investigator, got %+v", member)
+		}
+	})
+
+	t.Run("hides campaigns from other keepers", func(t *testing.T) {
+		if _, err := store.GetCampaign(ctx, "keeper-2", id); err != errors.ErrNotFound {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+		if _, err := store.GetCampaignMember(ctx, "keeper-2", id, "inv-1"); err != errors.ErrNotFound {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+		if err := store.RemoveCampaignMember(ctx, "keeper-2", id, "inv-1"); err != errors.ErrNotFound {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+		campaigns, _ := store.ListCampaigns(ctx, "keeper-2")
+		if len(campaigns) != 0 {
+			t.Errorf("expected no campaigns, got %d", len(campaigns))
+		}
+	})
+
+	t.Run("removes members", func(t *testing.T) {
+		if err := store.RemoveCampaignMember(ctx, "keeper-1", id, "inv-1"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		campaigns, err := store.ListCampaigns(ctx, "keeper-1")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(campaigns) != 1 || len(campaigns[0].Members) != 0 {
+			t.Errorf("expected one empty campaign, got %+v", campaigns)
+		}
+	})
+
+	t.Run("leaves campaigns when the investigator is deleted", func(t *testing.T) {
+		store.JoinCampaign(ctx, campaign.JoinCode, "player-1", inv)
+		if err := store.LeaveCampaigns(ctx, "player-1", "inv-1"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		retrieved, _ := store.GetCampaign(ctx, "keeper-1", id)
+		if len(retrieved.Members) != 0 {
+			t.Errorf("expected no members, got %d", len(retrieved.Members))
+		}
+	})
+
+	t.Run("deletes campaign", func(t *testing.T) {
+		if err := store.DeleteCampaign(ctx, "keeper-1", id); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, err := store.GetCampaign(ctx, "keeper-1", id); err != errors.ErrNotFound {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
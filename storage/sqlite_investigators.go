@@ -0,0 +1,284 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base32"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/models"
+
+	"github.com/google/uuid"
+)
+
+// SQLiteInvestigatorStore implements the InvestigatorStore interface using SQLite.
+// Every investigator row belongs to an owner and is only visible to that owner.
+type SQLiteInvestigatorStore struct {
+	db          *sql.DB
+	exportStore ExportStore
+}
+
+// NewSQLiteInvestigatorStore creates a new SQLiteInvestigatorStore and migrates its schema
+func NewSQLiteInvestigatorStore(db *sql.DB, exportStore ExportStore) (*SQLiteInvestigatorStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database is required")
+	}
+
+	if err := migrate(db, schemaMigrations); err != nil {
+		return nil, fmt.Errorf("failed to migrate investigators schema: %w", err)
+	}
+
+	return &SQLiteInvestigatorStore{
+		db:          db,
+		exportStore: exportStore,
+	}, nil
+}
+
+// SaveInvestigator inserts a new investigator for the owner
+func (s *SQLiteInvestigatorStore) SaveInvestigator(ctx context.Context, ownerID string, inv *models.Investigator) (string, error) {
+	if ownerID == "" || inv == nil {
+		return "", errors.ErrInvalidData
+	}
+
+	inv.ID = uuid.New().String()
+
+	data, err := inv.ToJSON()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode investigator: %w", err)
+	}
+
+	now := time.Now()
+	query := `INSERT INTO investigators (id, owner_id, data, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`
+	if _, err := s.db.ExecContext(ctx, query, inv.ID, ownerID, string(data), now, now); err != nil {
+		return "", fmt.Errorf("failed to save investigator: %w", err)
+	}
+
+	return inv.ID, nil
+}
+
+// GetInvestigator retrieves one of the owner's investigators
+func (s *SQLiteInvestigatorStore) GetInvestigator(ctx context.Context, ownerID, id string) (*models.Investigator, error) {
+	if ownerID == "" || id == "" {
+		return nil, errors.ErrInvalidData
+	}
+
+	var data string
+	query := `SELECT data FROM investigators WHERE id = ? AND owner_id = ?`
+	err := s.db.QueryRowContext(ctx, query, id, ownerID).Scan(&data)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get investigator: %w", err)
+	}
+
+	return unmarshalInvestigator([]byte(data))
+}
+
+// UpdateInvestigator replaces the stored data of one of the owner's investigators
+func (s *SQLiteInvestigatorStore) UpdateInvestigator(ctx context.Context, ownerID, id string, inv *models.Investigator) error {
+	if ownerID == "" || id == "" || inv == nil {
+		return errors.ErrInvalidData
+	}
+
+	data, err := inv.ToJSON()
+	if err != nil {
+		return fmt.Errorf("failed to encode investigator: %w", err)
+	}
+
+	query := `UPDATE investigators SET data = ?, updated_at = ? WHERE id = ? AND owner_id = ?`
+	result, err := s.db.ExecContext(ctx, query, string(data), time.Now(), id, ownerID)
+	if err != nil {
+		return fmt.Errorf("failed to update investigator: %w", err)
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteInvestigator removes one of the owner's investigators
+func (s *SQLiteInvestigatorStore) DeleteInvestigator(ctx context.Context, ownerID, id string) error {
+	if ownerID == "" || id == "" {
+		return errors.ErrInvalidData
+	}
+
+	query := `DELETE FROM investigators WHERE id = ? AND owner_id = ?`
+	result, err := s.db.ExecContext(ctx, query, id, ownerID)
+	if err != nil {
+		return fmt.Errorf("failed to delete investigator: %w", err)
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+// ClaimInvestigator moves an investigator from an anonymous owner to a user account,
+// keeping its ID unless another owner already uses it. Its revisions and campaign memberships move in the same transaction so the
+// history and campaigns follow it into the account.
+func (s *SQLiteInvestigatorStore) ClaimInvestigator(ctx context.Context, fromOwnerID, toUserID string, inv *models.Investigator) error {
+	if fromOwnerID == "" || toUserID == "" || inv == nil || inv.ID == "" {
+		return errors.ErrInvalidData
+	}
+
+	data, err := inv.ToJSON()
+	if err != nil {
+		return fmt.Errorf("failed to encode investigator: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Investigators already kept in SQLite only change owner, cookie ones get their row
+	now := time.Now()
+	query := `
+		INSERT INTO investigators (id, owner_id, data, created_at, updated_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET owner_id = excluded.owner_id, data = excluded.data, updated_at = excluded.updated_at
+		WHERE investigators.owner_id = ?
+	`
+	result, err := tx.ExecContext(ctx, query, inv.ID, toUserID, string(data), now, now, fromOwnerID)
+	if err != nil {
+		return fmt.Errorf("failed to claim investigator: %w", err)
+	}
+
+	// Cookie IDs are only unique per browser, one taken by somebody else gets a new ID
+	// and its revisions and memberships are moved to it
+	oldID := inv.ID
+	if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+		inv.ID = uuid.New().String()
+		if data, err = inv.ToJSON(); err != nil {
+			return fmt.Errorf("failed to encode investigator: %w", err)
+		}
+		query = `INSERT INTO investigators (id, owner_id, data, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`
+		if _, err := tx.ExecContext(ctx, query, inv.ID, toUserID, string(data), now, now); err != nil {
+			return fmt.Errorf("failed to claim investigator: %w", err)
+		}
+	}
+
+	query = `UPDATE investigator_revisions SET owner_id = ?, investigator_id = ? WHERE owner_id = ? AND investigator_id = ?`
+	if _, err := tx.ExecContext(ctx, query, toUserID, inv.ID, fromOwnerID, oldID); err != nil {
+		return fmt.Errorf("failed to claim investigator revisions: %w", err)
+	}
+
+	query = `UPDATE campaign_members SET owner_id = ?, investigator_id = ? WHERE owner_id = ? AND investigator_id = ?`
+	if _, err := tx.ExecContext(ctx, query, toUserID, inv.ID, fromOwnerID, oldID); err != nil {
+		return fmt.Errorf("failed to claim investigator campaign memberships: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// ListInvestigators returns all investigators belonging to the owner
+func (s *SQLiteInvestigatorStore) ListInvestigators(ctx context.Context, ownerID string) (map[string]*models.Investigator, error) {
+	investigators := make(map[string]*models.Investigator)
+	if ownerID == "" {
+		return investigators, nil
+	}
+
+	query := `SELECT id, data FROM investigators WHERE owner_id = ?`
+	rows, err := s.db.QueryContext(ctx, query, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list investigators: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, data string
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan investigator: %w", err)
+		}
+
+		investigator, err := unmarshalInvestigator([]byte(data))
+		if err != nil {
+			// Skip invalid rows instead of failing completely
+			continue
+		}
+		investigators[id] = investigator
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list investigators: %w", err)
+	}
+
+	return investigators, nil
+}
+
+// ExportInvestigatorsList exports all of the owner's investigators for sharing.
+// The export uses the same format as CookieStore so codes work across backends.
+func (s *SQLiteInvestigatorStore) ExportInvestigatorsList(ctx context.Context, ownerID string) (string, error) {
+	investigators, err := s.ListInvestigators(ctx, ownerID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(investigators) == 0 {
+		return "", errors.ErrNotFound
+	}
+
+	entries := make(map[string]string, len(investigators))
+	for id, inv := range investigators {
+		encoded, err := encodeInvestigator(inv)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode investigator: %w", err)
+		}
+		entries[id] = encoded
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal investigators: %w", err)
+	}
+
+	exportID, err := s.exportStore.SaveExport(base32.StdEncoding.EncodeToString(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to save export: %w", err)
+	}
+
+	return exportID, nil
+}
+
+// ImportInvestigatorsList imports investigators from a shared export as new copies for the owner
+func (s *SQLiteInvestigatorStore) ImportInvestigatorsList(ctx context.Context, ownerID, uuid string) error {
+	if ownerID == "" || uuid == "" {
+		return errors.ErrInvalidData
+	}
+
+	encodedData, err := s.exportStore.GetExport(uuid)
+	if err != nil {
+		return fmt.Errorf("failed to get export: %w", err)
+	}
+
+	data, err := base32.StdEncoding.DecodeString(encodedData)
+	if err != nil {
+		return fmt.Errorf("failed to decode data: %w", err)
+	}
+
+	var entries map[string]string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to unmarshal investigators: %w", err)
+	}
+
+	for _, encoded := range entries {
+		inv, err := decodeInvestigator(encoded)
+		if err != nil {
+			// Skip invalid entries instead of failing completely
+			continue
+		}
+		if _, err := s.SaveInvestigator(ctx, ownerID, inv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
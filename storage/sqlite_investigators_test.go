@@ -0,0 +1,349 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"book-of-shadows/internal/config"
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/models"
+
+	"github.com/google/uuid"
+)
+
+// newTestInvestigatorStore returns a SQLiteInvestigatorStore backed by a temporary database
+func newTestInvestigatorStore(t *testing.T) *SQLiteInvestigatorStore {
+	t.Helper()
+
+	sqliteStore, err := NewSQLiteStore(testConfig(t))
+	if err != nil {
+		t.Fatalf("failed to create SQLite store: %v", err)
+	}
+	t.Cleanup(func() { sqliteStore.Close() })
+
+	store, err := NewSQLiteInvestigatorStore(sqliteStore.db, sqliteStore)
+	if err != nil {
+		t.Fatalf("failed to create investigator store: %v", err)
+	}
+	return store
+}
+
+func TestNewSQLiteInvestigatorStore(t *testing.T) {
+	t.Run("returns error with nil database", func(t *testing.T) {
+		_, err := NewSQLiteInvestigatorStore(nil, nil)
+		if err == nil {
+			t.Error("expected error with nil database")
+		}
+	})
+
+	t.Run("migrations are idempotent", func(t *testing.T) {
+		store := newTestInvestigatorStore(t)
+		if err := migrate(store.db, schemaMigrations); err != nil {
+			t.Fatalf("expected no error re-running migrations, got %v", err)
+		}
+
+		var version int
+		if err := store.db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
+			t.Fatalf("failed to read schema version: %v", err)
+		}
+		if version != schemaMigrations[len(schemaMigrations)-1].version {
+			t.Errorf("expected schema version %d, got %d", schemaMigrations[len(schemaMigrations)-1].version, version)
+		}
+	})
+}
+
+func TestSQLiteInvestigatorStoreSaveAndGet(t *testing.T) {
+	store := newTestInvestigatorStore(t)
+	ctx := context.Background()
+
+	t.Run("saves and retrieves investigator", func(t *testing.T) {
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		inv.Name = "Harvey Walters"
+
+		id, err := store.SaveInvestigator(ctx, "owner-1", inv)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		retrieved, err := store.GetInvestigator(ctx, "owner-1", id)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if retrieved.Name != "Harvey Walters" {
+			t.Errorf("expected name Harvey Walters, got %s", retrieved.Name)
+		}
+	})
+
+	t.Run("stores investigators larger than a cookie", func(t *testing.T) {
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		for i := 0; i < 200; i++ {
+			name := "Custom Skill " + string(rune('A'+i%26)) + string(rune('a'+i/26))
+			inv.Skills[name] = models.Skill{Name: name, Value: i}
+		}
+
+		id, err := store.SaveInvestigator(ctx, "owner-1", inv)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		retrieved, err := store.GetInvestigator(ctx, "owner-1", id)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(retrieved.Skills) != len(inv.Skills) {
+			t.Errorf("expected %d skills, got %d", len(inv.Skills), len(retrieved.Skills))
+		}
+	})
+
+	t.Run("keeps the game mode", func(t *testing.T) {
+		id, _ := store.SaveInvestigator(ctx, "owner-1", models.RandomInvestigator(models.Classic, models.Modern))
+
+		retrieved, err := store.GetInvestigator(ctx, "owner-1", id)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if retrieved.GameMode != models.Classic || retrieved.Archetype != nil {
+			t.Errorf("expected classic investigator without archetype, got %s", retrieved.GameMode)
+		}
+	})
+
+	t.Run("keeps the era", func(t *testing.T) {
+		id, _ := store.SaveInvestigator(ctx, "owner-1", models.RandomInvestigator(models.Pulp, models.Gaslight))
+
+		retrieved, err := store.GetInvestigator(ctx, "owner-1", id)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if retrieved.Era != models.Gaslight {
+			t.Errorf("expected gaslight, got %s", retrieved.Era)
+		}
+	})
+
+	t.Run("reads investigators saved before the game mode as modern pulp", func(t *testing.T) {
+		inv, err := unmarshalInvestigator([]byte(`{"Investigators_Name":"Old","Archetype":{"Name":"Adventurer"}}`))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if inv.GameMode != models.Pulp || inv.Era != models.Modern {
+			t.Errorf("expected modern pulp, got %s %s", inv.Era, inv.GameMode)
+		}
+	})
+
+	t.Run("hides investigators from other owners", func(t *testing.T) {
+		id, _ := store.SaveInvestigator(ctx, "owner-1", models.RandomInvestigator(models.Pulp, models.Modern))
+
+		_, err := store.GetInvestigator(ctx, "owner-2", id)
+		if err != errors.ErrNotFound {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("returns error without owner", func(t *testing.T) {
+		_, err := store.SaveInvestigator(ctx, "", models.RandomInvestigator(models.Pulp, models.Modern))
+		if err != errors.ErrInvalidData {
+			t.Errorf("expected ErrInvalidData, got %v", err)
+		}
+	})
+
+	t.Run("returns error for nil investigator", func(t *testing.T) {
+		_, err := store.SaveInvestigator(ctx, "owner-1", nil)
+		if err != errors.ErrInvalidData {
+			t.Errorf("expected ErrInvalidData, got %v", err)
+		}
+	})
+}
+
+func TestSQLiteInvestigatorStoreUpdate(t *testing.T) {
+	store := newTestInvestigatorStore(t)
+	ctx := context.Background()
+
+	t.Run("updates existing investigator", func(t *testing.T) {
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		id, _ := store.SaveInvestigator(ctx, "owner-1", inv)
+
+		inv.Name = "Updated Name"
+		if err := store.UpdateInvestigator(ctx, "owner-1", id, inv); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		retrieved, err := store.GetInvestigator(ctx, "owner-1", id)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if retrieved.Name != "Updated Name" {
+			t.Errorf("expected name Updated Name, got %s", retrieved.Name)
+		}
+	})
+
+	t.Run("returns error for another owner's investigator", func(t *testing.T) {
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		id, _ := store.SaveInvestigator(ctx, "owner-1", inv)
+
+		err := store.UpdateInvestigator(ctx, "owner-2", id, inv)
+		if err != errors.ErrNotFound {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("returns error for non-existent investigator", func(t *testing.T) {
+		err := store.UpdateInvestigator(ctx, "owner-1", "nonexistent", models.RandomInvestigator(models.Pulp, models.Modern))
+		if err != errors.ErrNotFound {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func TestSQLiteInvestigatorStoreDeleteAndList(t *testing.T) {
+	store := newTestInvestigatorStore(t)
+	ctx := context.Background()
+
+	id1, _ := store.SaveInvestigator(ctx, "owner-1", models.RandomInvestigator(models.Pulp, models.Modern))
+	id2, _ := store.SaveInvestigator(ctx, "owner-1", models.RandomInvestigator(models.Pulp, models.Modern))
+	store.SaveInvestigator(ctx, "owner-2", models.RandomInvestigator(models.Pulp, models.Modern))
+
+	t.Run("lists only the owner's investigators", func(t *testing.T) {
+		investigators, err := store.ListInvestigators(ctx, "owner-1")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(investigators) != 2 {
+			t.Errorf("expected 2 investigators, got %d", len(investigators))
+		}
+	})
+
+	t.Run("does not delete another owner's investigator", func(t *testing.T) {
+		if err := store.DeleteInvestigator(ctx, "owner-2", id1); err != errors.ErrNotFound {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("deletes investigator", func(t *testing.T) {
+		if err := store.DeleteInvestigator(ctx, "owner-1", id1); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		investigators, err := store.ListInvestigators(ctx, "owner-1")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, ok := investigators[id2]; !ok || len(investigators) != 1 {
+			t.Errorf("expected only %s to remain, got %d investigators", id2, len(investigators))
+		}
+	})
+}
+
+func TestSQLiteInvestigatorStoreExportImport(t *testing.T) {
+	store := newTestInvestigatorStore(t)
+	ctx := context.Background()
+
+	inv := models.RandomInvestigator(models.Pulp, models.Modern)
+	inv.Name = "Exported"
+	store.SaveInvestigator(ctx, "owner-1", inv)
+
+	code, err := store.ExportInvestigatorsList(ctx, "owner-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	t.Run("imports export as new investigators", func(t *testing.T) {
+		if err := store.ImportInvestigatorsList(ctx, "owner-2", code); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		investigators, err := store.ListInvestigators(ctx, "owner-2")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(investigators) != 1 {
+			t.Fatalf("expected 1 investigator, got %d", len(investigators))
+		}
+		for id, imported := range investigators {
+			if id == inv.ID {
+				t.Error("expected imported investigator to get a new ID")
+			}
+			if imported.Name != "Exported" {
+				t.Errorf("expected name Exported, got %s", imported.Name)
+			}
+		}
+	})
+
+	t.Run("returns error when nothing to export", func(t *testing.T) {
+		_, err := store.ExportInvestigatorsList(ctx, "owner-3")
+		if err != errors.ErrNotFound {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func TestLegacyInvestigatorMigration(t *testing.T) {
+	dbConfig := testConfig(t)
+
+	// A database of the first schema, with an investigator behind a per-investigator access token
+	db, err := sql.Open("sqlite3", dbConfig.Path)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if err := migrate(db, schemaMigrations[:1]); err != nil {
+		t.Fatalf("failed to apply the first migration: %v", err)
+	}
+	inv := models.RandomInvestigator(models.Pulp, models.Modern)
+	inv.Name = "Harvey Walters"
+	data, _ := inv.ToJSON()
+	id, token := "investigator0123456789abcdef0123456789abcdef", uuid.New().String()
+	query := `INSERT INTO investigators (id, token, data, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`
+	if _, err := db.Exec(query, id, token, string(data), time.Now(), time.Now()); err != nil {
+		t.Fatalf("failed to insert investigator: %v", err)
+	}
+	db.Close()
+
+	cfg := &config.Config{
+		Database: *dbConfig,
+		Cookie:   *testCookieConfig(),
+		Storage:  config.StorageConfig{Backend: config.StorageBackendSQLite},
+		Auth:     config.AuthConfig{SessionTTL: time.Hour},
+	}
+	store, err := NewAppStore(cfg, testLogger)
+	if err != nil {
+		t.Fatalf("failed to create app store: %v", err)
+	}
+	defer store.Close()
+
+	t.Run("stays out of reach without the access cookie", func(t *testing.T) {
+		ctx := sessionContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+		if investigators, _ := store.ListInvestigators(ctx, "owner-2"); len(investigators) != 0 {
+			t.Errorf("expected no investigators, got %d", len(investigators))
+		}
+	})
+
+	t.Run("is claimed by the browser holding the access cookie", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.AddCookie(&http.Cookie{Name: id, Value: token})
+		w := httptest.NewRecorder()
+		ctx := sessionContext(w, req)
+
+		investigators, err := store.ListInvestigators(ctx, "owner-1")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if investigators[id] == nil || investigators[id].Name != "Harvey Walters" {
+			t.Fatalf("expected the legacy investigator to be listed, got %v", investigators)
+		}
+		if got, err := store.GetInvestigator(ctx, "owner-1", id); err != nil || got.Name != "Harvey Walters" {
+			t.Errorf("expected the legacy investigator to be read back, got %v", err)
+		}
+
+		expired := false
+		for _, cookie := range w.Result().Cookies() {
+			if cookie.Name == id && cookie.MaxAge < 0 {
+				expired = true
+			}
+		}
+		if !expired {
+			t.Error("expected the access cookie to be removed")
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/models"
+)
+
+// maxRevisionsPerInvestigator bounds the history kept for a single investigator
+const maxRevisionsPerInvestigator = 200
+
+// SQLiteRevisionStore implements the RevisionStore interface using SQLite.
+// History is kept server side for every investigator backend, keyed by the owner.
+type SQLiteRevisionStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteRevisionStore creates a new SQLiteRevisionStore and migrates its schema
+func NewSQLiteRevisionStore(db *sql.DB) (*SQLiteRevisionStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database is required")
+	}
+
+	if err := migrate(db, schemaMigrations); err != nil {
+		return nil, fmt.Errorf("failed to migrate revisions schema: %w", err)
+	}
+
+	return &SQLiteRevisionStore{db: db}, nil
+}
+
+// SaveRevision records a revision and drops the oldest ones beyond the history limit
+func (s *SQLiteRevisionStore) SaveRevision(ctx context.Context, ownerID string, rev *models.Revision) error {
+	if ownerID == "" || rev == nil || rev.InvestigatorID == "" || len(rev.Snapshot) == 0 {
+		return errors.ErrInvalidData
+	}
+
+	oldValue, err := json.Marshal(rev.OldValue)
+	if err != nil {
+		return fmt.Errorf("failed to encode old value: %w", err)
+	}
+	newValue, err := json.Marshal(rev.NewValue)
+	if err != nil {
+		return fmt.Errorf("failed to encode new value: %w", err)
+	}
+
+	if rev.CreatedAt.IsZero() {
+		rev.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO investigator_revisions
+			(owner_id, investigator_id, author_id, author_name, section, field, old_value, new_value, snapshot, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := s.db.ExecContext(ctx, query, ownerID, rev.InvestigatorID, rev.AuthorID, rev.AuthorName,
+		rev.Section, rev.Field, string(oldValue), string(newValue), string(rev.Snapshot), rev.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save revision: %w", err)
+	}
+
+	if rev.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to read revision ID: %w", err)
+	}
+
+	prune := `
+		DELETE FROM investigator_revisions
+		WHERE owner_id = ? AND investigator_id = ? AND id NOT IN (
+			SELECT id FROM investigator_revisions
+			WHERE owner_id = ? AND investigator_id = ?
+			ORDER BY id DESC LIMIT ?
+		)
+	`
+	if _, err := s.db.ExecContext(ctx, prune, ownerID, rev.InvestigatorID, ownerID, rev.InvestigatorID, maxRevisionsPerInvestigator); err != nil {
+		return fmt.Errorf("failed to prune revisions: %w", err)
+	}
+
+	return nil
+}
+
+// GetRevision retrieves one revision of the owner's investigator, including its snapshot
+func (s *SQLiteRevisionStore) GetRevision(ctx context.Context, ownerID, investigatorID string, revisionID int64) (*models.Revision, error) {
+	if ownerID == "" || investigatorID == "" {
+		return nil, errors.ErrInvalidData
+	}
+
+	query := `
+		SELECT id, investigator_id, author_id, author_name, section, field, old_value, new_value, snapshot, created_at
+		FROM investigator_revisions
+		WHERE id = ? AND owner_id = ? AND investigator_id = ?
+	`
+	rev, err := scanRevision(s.db.QueryRowContext(ctx, query, revisionID, ownerID, investigatorID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+
+	return rev, nil
+}
+
+// ListRevisions returns the history of the owner's investigator, newest first
+func (s *SQLiteRevisionStore) ListRevisions(ctx context.Context, ownerID, investigatorID string) ([]*models.Revision, error) {
+	if ownerID == "" || investigatorID == "" {
+		return nil, errors.ErrInvalidData
+	}
+
+	query := `
+		SELECT id, investigator_id, author_id, author_name, section, field, old_value, new_value, snapshot, created_at
+		FROM investigator_revisions
+		WHERE owner_id = ? AND investigator_id = ?
+		ORDER BY id DESC
+	`
+	rows, err := s.db.QueryContext(ctx, query, ownerID, investigatorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := make([]*models.Revision, 0)
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		revisions = append(revisions, rev)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// DeleteRevisions removes the whole history of the owner's investigator
+func (s *SQLiteRevisionStore) DeleteRevisions(ctx context.Context, ownerID, investigatorID string) error {
+	if ownerID == "" || investigatorID == "" {
+		return errors.ErrInvalidData
+	}
+
+	query := `DELETE FROM investigator_revisions WHERE owner_id = ? AND investigator_id = ?`
+	if _, err := s.db.ExecContext(ctx, query, ownerID, investigatorID); err != nil {
+		return fmt.Errorf("failed to delete revisions: %w", err)
+	}
+
+	return nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanRevision reads a revision row, decoding the JSON encoded values
+func scanRevision(row rowScanner) (*models.Revision, error) {
+	var rev models.Revision
+	var oldValue, newValue, snapshot string
+	err := row.Scan(&rev.ID, &rev.InvestigatorID, &rev.AuthorID, &rev.AuthorName,
+		&rev.Section, &rev.Field, &oldValue, &newValue, &snapshot, &rev.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(oldValue), &rev.OldValue); err != nil {
+		return nil, fmt.Errorf("failed to decode old value: %w", err)
+	}
+	if err := json.Unmarshal([]byte(newValue), &rev.NewValue); err != nil {
+		return nil, fmt.Errorf("failed to decode new value: %w", err)
+	}
+	rev.Snapshot = []byte(snapshot)
+
+	return &rev, nil
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/models"
+)
+
+// newTestRevisionStore returns a SQLiteRevisionStore backed by a temporary database
+func newTestRevisionStore(t *testing.T) *SQLiteRevisionStore {
+	t.Helper()
+
+	sqliteStore, err := NewSQLiteStore(testConfig(t))
+	if err != nil {
+		t.Fatalf("failed to create SQLite store: %v", err)
+	}
+	t.Cleanup(func() { sqliteStore.Close() })
+
+	store, err := NewSQLiteRevisionStore(sqliteStore.db)
+	if err != nil {
+		t.Fatalf("failed to create revision store: %v", err)
+	}
+	return store
+}
+
+func TestSQLiteRevisionStore(t *testing.T) {
+	store := newTestRevisionStore(t)
+	ctx := context.Background()
+
+	first := &models.Revision{
+		InvestigatorID: "inv-1",
+		AuthorID:       "owner-1",
+		Section:        "skills",
+		Field:          "Spot Hidden",
+		OldValue:       25,
+		NewValue:       60,
+		Snapshot:       []byte(`{"Investigators_Name":"Before"}`),
+	}
+	if err := store.SaveRevision(ctx, "owner-1", first); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	second := &models.Revision{InvestigatorID: "inv-1", AuthorID: "owner-1", Section: "personalInfo", Field: "Name", Snapshot: []byte(`{}`)}
+	store.SaveRevision(ctx, "owner-1", second)
+
+	t.Run("lists revisions newest first", func(t *testing.T) {
+		revisions, err := store.ListRevisions(ctx, "owner-1", "inv-1")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(revisions) != 2 || revisions[0].ID != second.ID {
+			t.Fatalf("expected 2 revisions starting with %d", second.ID)
+		}
+		if revisions[1].OldValue != float64(25) || revisions[1].NewValue != float64(60) {
+			t.Errorf("expected 25 -> 60, got %v -> %v", revisions[1].OldValue, revisions[1].NewValue)
+		}
+	})
+
+	t.Run("returns revision with snapshot", func(t *testing.T) {
+		rev, err := store.GetRevision(ctx, "owner-1", "inv-1", first.ID)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if string(rev.Snapshot) != `{"Investigators_Name":"Before"}` {
+			t.Errorf("unexpected snapshot %s", rev.Snapshot)
+		}
+	})
+
+	t.Run("hides revisions from other owners", func(t *testing.T) {
+		if _, err := store.GetRevision(ctx, "owner-2", "inv-1", first.ID); err != errors.ErrNotFound {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+		revisions, _ := store.ListRevisions(ctx, "owner-2", "inv-1")
+		if len(revisions) != 0 {
+			t.Errorf("expected no revisions, got %d", len(revisions))
+		}
+	})
+
+	t.Run("requires a snapshot", func(t *testing.T) {
+		err := store.SaveRevision(ctx, "owner-1", &models.Revision{InvestigatorID: "inv-1"})
+		if err != errors.ErrInvalidData {
+			t.Errorf("expected ErrInvalidData, got %v", err)
+		}
+	})
+
+	t.Run("keeps a bounded history", func(t *testing.T) {
+		for i := 0; i < maxRevisionsPerInvestigator+5; i++ {
+			store.SaveRevision(ctx, "owner-1", &models.Revision{InvestigatorID: "inv-2", Snapshot: []byte(`{}`)})
+		}
+		revisions, _ := store.ListRevisions(ctx, "owner-1", "inv-2")
+		if len(revisions) != maxRevisionsPerInvestigator {
+			t.Errorf("expected %d revisions, got %d", maxRevisionsPerInvestigator, len(revisions))
+		}
+	})
+
+	t.Run("deletes history", func(t *testing.T) {
+		if err := store.DeleteRevisions(ctx, "owner-1", "inv-1"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		revisions, _ := store.ListRevisions(ctx, "owner-1", "inv-1")
+		if len(revisions) != 0 {
+			t.Errorf("expected no revisions, got %d", len(revisions))
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/models"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// SQLiteUserStore implements the UserStore interface using SQLite.
+// Passwords are stored as bcrypt hashes and session tokens as SHA-256 hashes,
+// so a leaked database cannot be used to log in.
+type SQLiteUserStore struct {
+	db         *sql.DB
+	sessionTTL time.Duration
+}
+
+// NewSQLiteUserStore creates a new SQLiteUserStore and migrates its schema
+func NewSQLiteUserStore(db *sql.DB, sessionTTL time.Duration) (*SQLiteUserStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database is required")
+	}
+	if sessionTTL <= 0 {
+		return nil, fmt.Errorf("session TTL must be positive")
+	}
+
+	if err := migrate(db, schemaMigrations); err != nil {
+		return nil, fmt.Errorf("failed to migrate users schema: %w", err)
+	}
+
+	return &SQLiteUserStore{
+		db:         db,
+		sessionTTL: sessionTTL,
+	}, nil
+}
+
+// CreateUser registers a new user with a bcrypt hash of the password
+func (s *SQLiteUserStore) CreateUser(ctx context.Context, username, password string) (*models.User, error) {
+	username = strings.TrimSpace(username)
+	if username == "" || password == "" {
+		return nil, errors.ErrInvalidData
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := &models.User{
+		ID:        uuid.New().String(),
+		Username:  username,
+		CreatedAt: time.Now(),
+	}
+
+	query := `INSERT INTO users (id, username, password_hash, created_at) VALUES (?, ?, ?, ?)`
+	if _, err := s.db.ExecContext(ctx, query, user.ID, user.Username, string(hash), user.CreatedAt); err != nil {
+		var sqliteErr sqlite3.Error
+		if stderrors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return nil, errors.ErrAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return user, nil
+}
+
+// AuthenticateUser returns the user matching the username and password
+func (s *SQLiteUserStore) AuthenticateUser(ctx context.Context, username, password string) (*models.User, error) {
+	var user models.User
+	var hash string
+	query := `SELECT id, username, password_hash, created_at FROM users WHERE username = ?`
+	err := s.db.QueryRowContext(ctx, query, strings.TrimSpace(username)).Scan(&user.ID, &user.Username, &hash, &user.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return nil, errors.ErrInvalidCredentials
+	}
+
+	return &user, nil
+}
+
+// CreateSession starts a session for the user and returns its token
+func (s *SQLiteUserStore) CreateSession(ctx context.Context, userID string) (string, error) {
+	if userID == "" {
+		return "", errors.ErrInvalidData
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate session token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	// Drop expired sessions while we are writing anyway
+	now := time.Now()
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE expires_at < ?`, now); err != nil {
+		return "", fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+
+	query := `INSERT INTO sessions (token_hash, user_id, created_at, expires_at) VALUES (?, ?, ?, ?)`
+	if _, err := s.db.ExecContext(ctx, query, hashSessionToken(token), userID, now, now.Add(s.sessionTTL)); err != nil {
+		return "", fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return token, nil
+}
+
+// GetSessionUser returns the user of an unexpired session
+func (s *SQLiteUserStore) GetSessionUser(ctx context.Context, token string) (*models.User, error) {
+	if token == "" {
+		return nil, errors.ErrInvalidData
+	}
+
+	var user models.User
+	query := `
+		SELECT users.id, users.username, users.created_at
+		FROM sessions JOIN users ON users.id = sessions.user_id
+		WHERE sessions.token_hash = ? AND sessions.expires_at > ?
+	`
+	err := s.db.QueryRowContext(ctx, query, hashSessionToken(token), time.Now()).Scan(&user.ID, &user.Username, &user.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return &user, nil
+}
+
+// DeleteSession ends a session; deleting an unknown session is not an error
+func (s *SQLiteUserStore) DeleteSession(ctx context.Context, token string) error {
+	if token == "" {
+		return errors.ErrInvalidData
+	}
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE token_hash = ?`, hashSessionToken(token)); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	return nil
+}
+
+// hashSessionToken returns the stored form of a session token
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"book-of-shadows/internal/config"
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/models"
+)
+
+// newTestUserStore returns a SQLiteUserStore backed by a temporary database
+func newTestUserStore(t *testing.T, sessionTTL time.Duration) *SQLiteUserStore {
+	t.Helper()
+
+	sqliteStore, err := NewSQLiteStore(testConfig(t))
+	if err != nil {
+		t.Fatalf("failed to create SQLite store: %v", err)
+	}
+	t.Cleanup(func() { sqliteStore.Close() })
+
+	store, err := NewSQLiteUserStore(sqliteStore.db, sessionTTL)
+	if err != nil {
+		t.Fatalf("failed to create user store: %v", err)
+	}
+	return store
+}
+
+func TestSQLiteUserStoreAccounts(t *testing.T) {
+	store := newTestUserStore(t, time.Hour)
+	ctx := context.Background()
+
+	user, err := store.CreateUser(ctx, "Harvey", "correct horse")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	t.Run("does not store the plain password", func(t *testing.T) {
+		var hash string
+		store.db.QueryRow(`SELECT password_hash FROM users WHERE id = ?`, user.ID).Scan(&hash)
+		if hash == "" || hash == "correct horse" {
+			t.Error("expected a password hash")
+		}
+	})
+
+	t.Run("rejects duplicate usernames regardless of case", func(t *testing.T) {
+		_, err := store.CreateUser(ctx, "harvey", "another password")
+		if err != errors.ErrAlreadyExists {
+			t.Errorf("expected ErrAlreadyExists, got %v", err)
+		}
+	})
+
+	t.Run("authenticates with the right password", func(t *testing.T) {
+		authenticated, err := store.AuthenticateUser(ctx, "Harvey", "correct horse")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if authenticated.ID != user.ID {
+			t.Errorf("expected user %s, got %s", user.ID, authenticated.ID)
+		}
+	})
+
+	t.Run("rejects wrong password and unknown user", func(t *testing.T) {
+		if _, err := store.AuthenticateUser(ctx, "Harvey", "wrong"); err != errors.ErrInvalidCredentials {
+			t.Errorf("expected ErrInvalidCredentials, got %v", err)
+		}
+		if _, err := store.AuthenticateUser(ctx, "nobody", "correct horse"); err != errors.ErrInvalidCredentials {
+			t.Errorf("expected ErrInvalidCredentials, got %v", err)
+		}
+	})
+}
+
+func TestSQLiteUserStoreSessions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("resolves and deletes sessions", func(t *testing.T) {
+		store := newTestUserStore(t, time.Hour)
+		user, _ := store.CreateUser(ctx, "harvey", "correct horse")
+
+		token, err := store.CreateSession(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		sessionUser, err := store.GetSessionUser(ctx, token)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if sessionUser.Username != "harvey" {
+			t.Errorf("expected harvey, got %s", sessionUser.Username)
+		}
+
+		if err := store.DeleteSession(ctx, token); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, err := store.GetSessionUser(ctx, token); err != errors.ErrNotFound {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("ignores expired sessions", func(t *testing.T) {
+		store := newTestUserStore(t, time.Nanosecond)
+		user, _ := store.CreateUser(ctx, "harvey", "correct horse")
+
+		token, _ := store.CreateSession(ctx, user.ID)
+		time.Sleep(time.Millisecond)
+
+		if _, err := store.GetSessionUser(ctx, token); err != errors.ErrNotFound {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func TestAppStoreAccounts(t *testing.T) {
+	cfg := &config.Config{
+		Database: *testConfig(t),
+		Cookie:   *testCookieConfig(),
+		Storage:  config.StorageConfig{Backend: config.StorageBackendCookie},
+		Auth:     config.AuthConfig{SessionTTL: time.Hour},
+	}
+	store, err := NewAppStore(cfg, testLogger)
+	if err != nil {
+		t.Fatalf("failed to create app store: %v", err)
+	}
+	defer store.Close()
+
+	user := &models.User{ID: "user-1", Username: "harvey"}
+
+	t.Run("logged in users keep investigators in SQLite", func(t *testing.T) {
+		ctx := WithUser(context.Background(), user)
+		id, err := store.SaveInvestigator(ctx, user.ID, models.RandomInvestigator(models.Pulp, models.Modern))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, err := store.accounts.GetInvestigator(ctx, user.ID, id); err != nil {
+			t.Errorf("expected investigator in account store, got %v", err)
+		}
+	})
+
+	t.Run("claims anonymous cookie investigators", func(t *testing.T) {
+		saved := httptest.NewRecorder()
+		ctx := sessionContext(saved, httptest.NewRequest("GET", "/", nil))
+		cookieID, _ := store.SaveInvestigator(ctx, "anonymous", models.RandomInvestigator(models.Pulp, models.Modern))
+
+		claimed := httptest.NewRecorder()
+		ctx = sessionContext(claimed, requestWithCookies(saved))
+		if err := store.ClaimInvestigators(ctx, "anonymous", user.ID); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		investigators, _ := store.accounts.ListInvestigators(ctx, user.ID)
+		if len(investigators) != 2 {
+			t.Errorf("expected 2 account investigators, got %d", len(investigators))
+		}
+
+		expired := false
+		for _, cookie := range claimed.Result().Cookies() {
+			if cookie.Name == cookieID && cookie.MaxAge < 0 {
+				expired = true
+			}
+		}
+		if !expired {
+			t.Error("expected claimed investigator cookie to be removed")
+		}
+	})
+
+	t.Run("claimed investigators keep their ID, history and campaigns", func(t *testing.T) {
+		saved := httptest.NewRecorder()
+		ctx := sessionContext(saved, httptest.NewRequest("GET", "/", nil))
+		inv := models.RandomInvestigator(models.Pulp, models.Modern)
+		inv.Name = "Harvey Walters"
+		id, _ := store.SaveInvestigator(ctx, "anonymous-2", inv)
+
+		rev := &models.Revision{InvestigatorID: id, Section: "stats", Field: "HP", Snapshot: []byte("{}")}
+		if err := store.SaveRevision(ctx, "anonymous-2", rev); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		campaign := &models.Campaign{Name: "Horror on the Orient Express", Era: models.Twenties, GameMode: models.Pulp}
+		if _, err := store.CreateCampaign(ctx, "keeper-1", campaign); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, err := store.JoinCampaign(ctx, campaign.JoinCode, "anonymous-2", inv); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		claimed := httptest.NewRecorder()
+		ctx = sessionContext(claimed, requestWithCookies(saved))
+		if err := store.ClaimInvestigators(ctx, "anonymous-2", user.ID); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if _, err := store.accounts.GetInvestigator(ctx, user.ID, id); err != nil {
+			t.Errorf("expected the investigator to keep ID %s, got %v", id, err)
+		}
+		if revisions, _ := store.ListRevisions(ctx, user.ID, id); len(revisions) != 1 {
+			t.Errorf("expected the history to follow the investigator, got %d revisions", len(revisions))
+		}
+		var ownerID string
+		query := `SELECT owner_id FROM campaign_members WHERE investigator_id = ?`
+		if err := store.SQLiteStore.db.QueryRowContext(ctx, query, id).Scan(&ownerID); err != nil || ownerID != user.ID {
+			t.Errorf("expected the campaign membership to move to %s, got %q (%v)", user.ID, ownerID, err)
+		}
+	})
+}
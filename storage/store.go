@@ -2,17 +2,34 @@ package storage
 
 import (
 	"book-of-shadows/internal/config"
+	"book-of-shadows/internal/errors"
+	"book-of-shadows/models"
+	"context"
 	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
 )
 
-// AppStore combines all storage functionality
+// legacyOwnerPrefix marks the owner of investigators stored before owners existed, followed by
+// the access token their browser keeps in a cookie named after the investigator (migration 2)
+const legacyOwnerPrefix = "legacy:"
+
+// AppStore combines all storage functionality.
+// Anonymous callers use the configured investigator backend while logged in users
+// always keep their investigators in SQLite so they can reach them from any device.
 type AppStore struct {
 	*SQLiteStore
-	*CookieStore
+	*SQLiteUserStore
+	*SQLiteRevisionStore
+	*SQLiteCampaignStore
+	investigators InvestigatorStore
+	accounts      *SQLiteInvestigatorStore
 }
 
 // NewAppStore creates a new combined store instance
-func NewAppStore(cfg *config.Config) (*AppStore, error) {
+func NewAppStore(cfg *config.Config, logger *log.Logger) (*AppStore, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("config is required")
 	}
@@ -23,15 +40,214 @@ func NewAppStore(cfg *config.Config) (*AppStore, error) {
 		return nil, fmt.Errorf("failed to create SQLite store: %w", err)
 	}
 
-	// Create cookie store with SQLite as export store
-	cookieStore := NewCookieStore(&cfg.Cookie, sqliteStore)
+	// Create the configured investigator store with SQLite as export store
+	investigatorStore, err := newInvestigatorStore(cfg, sqliteStore, logger)
+	if err != nil {
+		sqliteStore.Close()
+		return nil, err
+	}
+
+	// Create the account stores
+	userStore, err := NewSQLiteUserStore(sqliteStore.db, cfg.Auth.SessionTTL)
+	if err != nil {
+		sqliteStore.Close()
+		return nil, fmt.Errorf("failed to create user store: %w", err)
+	}
+
+	revisionStore, err := NewSQLiteRevisionStore(sqliteStore.db)
+	if err != nil {
+		sqliteStore.Close()
+		return nil, fmt.Errorf("failed to create revision store: %w", err)
+	}
+
+	campaignStore, err := NewSQLiteCampaignStore(sqliteStore.db)
+	if err != nil {
+		sqliteStore.Close()
+		return nil, fmt.Errorf("failed to create campaign store: %w", err)
+	}
+
+	accountStore, ok := investigatorStore.(*SQLiteInvestigatorStore)
+	if !ok {
+		accountStore, err = NewSQLiteInvestigatorStore(sqliteStore.db, sqliteStore)
+		if err != nil {
+			sqliteStore.Close()
+			return nil, fmt.Errorf("failed to create account investigator store: %w", err)
+		}
+	}
 
 	return &AppStore{
-		SQLiteStore: sqliteStore,
-		CookieStore: cookieStore,
+		SQLiteStore:         sqliteStore,
+		SQLiteUserStore:     userStore,
+		SQLiteRevisionStore: revisionStore,
+		SQLiteCampaignStore: campaignStore,
+		investigators:       investigatorStore,
+		accounts:            accountStore,
 	}, nil
 }
 
+// newInvestigatorStore selects the investigator backend from the storage config
+func newInvestigatorStore(cfg *config.Config, sqliteStore *SQLiteStore, logger *log.Logger) (InvestigatorStore, error) {
+	switch cfg.Storage.Backend {
+	case config.StorageBackendCookie, "":
+		return NewCookieStore(&cfg.Cookie, sqliteStore, logger), nil
+	case config.StorageBackendSQLite:
+		store, err := NewSQLiteInvestigatorStore(sqliteStore.db, sqliteStore)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SQLite investigator store: %w", err)
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Storage.Backend)
+	}
+}
+
+// investigatorStore returns the store holding the investigators of the caller in ctx
+func (s *AppStore) investigatorStore(ctx context.Context) InvestigatorStore {
+	if UserFromContext(ctx) != nil {
+		return s.accounts
+	}
+	return s.investigators
+}
+
+// SaveInvestigator saves an investigator for the owner
+func (s *AppStore) SaveInvestigator(ctx context.Context, ownerID string, inv *models.Investigator) (string, error) {
+	return s.investigatorStore(ctx).SaveInvestigator(ctx, ownerID, inv)
+}
+
+// GetInvestigator retrieves one of the owner's investigators
+func (s *AppStore) GetInvestigator(ctx context.Context, ownerID, id string) (*models.Investigator, error) {
+	return s.investigatorStore(ctx).GetInvestigator(ctx, ownerID, id)
+}
+
+// UpdateInvestigator updates one of the owner's investigators and the copies
+// held by the campaigns it joined
+func (s *AppStore) UpdateInvestigator(ctx context.Context, ownerID, id string, inv *models.Investigator) error {
+	if err := s.investigatorStore(ctx).UpdateInvestigator(ctx, ownerID, id, inv); err != nil {
+		return err
+	}
+	return s.SyncCampaignMember(ctx, ownerID, id, inv)
+}
+
+// DeleteInvestigator removes one of the owner's investigators along with its history
+// and campaign memberships
+func (s *AppStore) DeleteInvestigator(ctx context.Context, ownerID, id string) error {
+	if err := s.investigatorStore(ctx).DeleteInvestigator(ctx, ownerID, id); err != nil {
+		return err
+	}
+	if err := s.LeaveCampaigns(ctx, ownerID, id); err != nil {
+		return err
+	}
+	return s.DeleteRevisions(ctx, ownerID, id)
+}
+
+// ListInvestigators returns all investigators belonging to the owner, first claiming the ones
+// stored before owners existed that the browser holds an access cookie for
+func (s *AppStore) ListInvestigators(ctx context.Context, ownerID string) (map[string]*models.Investigator, error) {
+	if err := s.claimLegacyInvestigators(ctx, ownerID); err != nil {
+		return nil, err
+	}
+	return s.investigatorStore(ctx).ListInvestigators(ctx, ownerID)
+}
+
+// claimLegacyInvestigators moves the SQLite investigators the request holds an access cookie
+// for to the owner, with their history and campaigns, and removes the cookie. Anonymous owners
+// keeping investigators in cookies claim them once logged in.
+func (s *AppStore) claimLegacyInvestigators(ctx context.Context, ownerID string) error {
+	if ownerID == "" || s.investigatorStore(ctx) != InvestigatorStore(s.accounts) {
+		return nil
+	}
+	session, err := cookieSessionFromContext(ctx)
+	if err != nil {
+		return nil
+	}
+
+	for _, cookie := range session.r.Cookies() {
+		// Access tokens are UUIDs, which no sealed investigator cookie is
+		if uuid.Validate(cookie.Value) != nil {
+			continue
+		}
+		legacyOwnerID := legacyOwnerPrefix + cookie.Value
+		inv, err := s.accounts.GetInvestigator(ctx, legacyOwnerID, cookie.Name)
+		if err == errors.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read legacy investigator: %w", err)
+		}
+
+		inv.ID = cookie.Name
+		if err := s.accounts.ClaimInvestigator(ctx, legacyOwnerID, ownerID, inv); err != nil {
+			return fmt.Errorf("failed to claim legacy investigator: %w", err)
+		}
+		http.SetCookie(session.w, &http.Cookie{Name: cookie.Name, Path: "/", MaxAge: -1})
+	}
+
+	return nil
+}
+
+// ExportInvestigatorsList exports all of the owner's investigators for sharing
+func (s *AppStore) ExportInvestigatorsList(ctx context.Context, ownerID string) (string, error) {
+	return s.investigatorStore(ctx).ExportInvestigatorsList(ctx, ownerID)
+}
+
+// ImportInvestigatorsList imports investigators from a shared export for the owner
+func (s *AppStore) ImportInvestigatorsList(ctx context.Context, ownerID, uuid string) error {
+	return s.investigatorStore(ctx).ImportInvestigatorsList(ctx, ownerID, uuid)
+}
+
+// ClaimInvestigators moves the investigators an anonymous owner created in this
+// browser into the user's account, removing them from the anonymous backend.
+// They keep their IDs, revisions and campaign memberships.
+func (s *AppStore) ClaimInvestigators(ctx context.Context, fromOwnerID, toUserID string) error {
+	if fromOwnerID == "" || toUserID == "" {
+		return errors.ErrInvalidData
+	}
+
+	investigators, err := s.investigators.ListInvestigators(ctx, fromOwnerID)
+	if err != nil {
+		return fmt.Errorf("failed to list anonymous investigators: %w", err)
+	}
+
+	// With the SQLite backend the rows were moved by the claim itself
+	separate := s.investigators != InvestigatorStore(s.accounts)
+	for id, inv := range investigators {
+		inv.ID = id
+		if err := s.accounts.ClaimInvestigator(ctx, fromOwnerID, toUserID, inv); err != nil {
+			return fmt.Errorf("failed to claim investigator: %w", err)
+		}
+		if !separate {
+			continue
+		}
+		if err := s.investigators.DeleteInvestigator(ctx, fromOwnerID, id); err != nil {
+			return fmt.Errorf("failed to remove claimed investigator: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetMemberInvestigator loads the sheet of a campaign member from its owner's store.
+// Only sheets kept in SQLite can be reached, players keeping their investigators in browser
+// cookies give ErrNotFound.
+func (s *AppStore) GetMemberInvestigator(ctx context.Context, member *models.CampaignMember) (*models.Investigator, error) {
+	if member == nil || member.OwnerID == "" {
+		return nil, errors.ErrInvalidData
+	}
+	return s.accounts.GetInvestigator(ctx, member.OwnerID, member.InvestigatorID)
+}
+
+// UpdateMemberInvestigator saves a keeper's change to the sheet of a campaign member for its
+// owner and refreshes the copies held by the campaigns it joined
+func (s *AppStore) UpdateMemberInvestigator(ctx context.Context, member *models.CampaignMember, inv *models.Investigator) error {
+	if member == nil || member.OwnerID == "" {
+		return errors.ErrInvalidData
+	}
+	if err := s.accounts.UpdateInvestigator(ctx, member.OwnerID, member.InvestigatorID, inv); err != nil {
+		return err
+	}
+	return s.SyncCampaignMember(ctx, member.OwnerID, member.InvestigatorID, inv)
+}
+
 // Close gracefully shuts down the store
 func (s *AppStore) Close() error {
 	return s.SQLiteStore.Close()
@@ -40,17 +256,55 @@ func (s *AppStore) Close() error {
 // Ensure AppStore implements the Store interface
 var _ Store = (*AppStore)(nil)
 
-// The AppStore now implements all methods from both SQLiteStore and CookieStore:
+// Ensure the investigator backends and the user store implement their interfaces
+var (
+	_ InvestigatorStore = (*CookieStore)(nil)
+	_ InvestigatorStore = (*SQLiteInvestigatorStore)(nil)
+	_ UserStore         = (*SQLiteUserStore)(nil)
+	_ RevisionStore     = (*SQLiteRevisionStore)(nil)
+	_ CampaignStore     = (*SQLiteCampaignStore)(nil)
+)
+
+// The AppStore now implements all methods from SQLiteStore, SQLiteUserStore, SQLiteRevisionStore,
+// SQLiteCampaignStore and the investigator stores:
 // From SQLiteStore (ExportStore):
 // - SaveExport(data string) (string, error)
 // - GetExport(id string) (string, error)
 // - DeleteExpiredExports() error
 //
-// From CookieStore (InvestigatorStore):
-// - SaveInvestigator(w http.ResponseWriter, inv *models.Investigator) (string, error)
-// - GetInvestigator(r *http.Request, id string) (*models.Investigator, error)
-// - UpdateInvestigator(w http.ResponseWriter, id string, inv *models.Investigator) error
-// - DeleteInvestigator(w http.ResponseWriter, id string) error
-// - ListInvestigators(r *http.Request) (map[string]*models.Investigator, error)
-// - ExportInvestigatorsList(r *http.Request) (string, error)
-// - ImportInvestigatorsList(w http.ResponseWriter, uuid string) error
\ No newline at end of file
+// From SQLiteUserStore (UserStore):
+// - CreateUser(ctx context.Context, username, password string) (*models.User, error)
+// - AuthenticateUser(ctx context.Context, username, password string) (*models.User, error)
+// - CreateSession(ctx context.Context, userID string) (string, error)
+// - GetSessionUser(ctx context.Context, token string) (*models.User, error)
+// - DeleteSession(ctx context.Context, token string) error
+//
+// From SQLiteRevisionStore (RevisionStore):
+// - SaveRevision(ctx context.Context, ownerID string, rev *models.Revision) error
+// - GetRevision(ctx context.Context, ownerID, investigatorID string, revisionID int64) (*models.Revision, error)
+// - ListRevisions(ctx context.Context, ownerID, investigatorID string) ([]*models.Revision, error)
+// - DeleteRevisions(ctx context.Context, ownerID, investigatorID string) error
+//
+// From SQLiteCampaignStore (CampaignStore):
+// - CreateCampaign(ctx context.Context, keeperID string, campaign *models.Campaign) (string, error)
+// - GetCampaign(ctx context.Context, keeperID, id string) (*models.Campaign, error)
+// - ListCampaigns(ctx context.Context, keeperID string) ([]*models.Campaign, error)
+// - DeleteCampaign(ctx context.Context, keeperID, id string) error
+// - JoinCampaign(ctx context.Context, joinCode, ownerID string, inv *models.Investigator) (*models.Campaign, error)
+// - RemoveCampaignMember(ctx context.Context, keeperID, campaignID, investigatorID string) error
+// - GetCampaignMember(ctx context.Context, keeperID, campaignID, investigatorID string) (*models.CampaignMember, error)
+// - SyncCampaignMember(ctx context.Context, ownerID, investigatorID string, inv *models.Investigator) error
+// - LeaveCampaigns(ctx context.Context, ownerID, investigatorID string) error
+//
+// From CookieStore or SQLiteInvestigatorStore (InvestigatorStore), chosen per request
+// by whether a user is logged in:
+// - SaveInvestigator(ctx context.Context, ownerID string, inv *models.Investigator) (string, error)
+// - GetInvestigator(ctx context.Context, ownerID, id string) (*models.Investigator, error)
+// - UpdateInvestigator(ctx context.Context, ownerID, id string, inv *models.Investigator) error
+// - DeleteInvestigator(ctx context.Context, ownerID, id string) error
+// - ListInvestigators(ctx context.Context, ownerID string) (map[string]*models.Investigator, error)
+// - ExportInvestigatorsList(ctx context.Context, ownerID string) (string, error)
+// - ImportInvestigatorsList(ctx context.Context, ownerID, uuid string) error
+//
+// CookieStore needs the HTTP exchange attached with WithCookieSession, which the
+// middleware.Session middleware does for every request.
@@ -80,7 +80,7 @@ func AssignAttrForm(investigator *models.Investigator) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = components.AttributeFormActions(investigator.ID).Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = components.AttributeFormActions(investigator).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -8,9 +8,12 @@ package views
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
-import "book-of-shadows/components"
+import (
+	"book-of-shadows/components"
+	"book-of-shadows/models"
+)
 
-func KeeperDashboard() templ.Component {
+func KeeperDashboard(investigators []*models.Investigator, campaigns []*models.Campaign) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -55,7 +58,15 @@ func KeeperDashboard() templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, " <div class=\"container-fluid p-4 coc-sheet\"><div class=\"keeper-dashboard\"><!-- Header --><div class=\"text-center mb-5\"><h1 class=\"display-5 fw-bold mb-3\"><i class=\"bi bi-shield-shaded me-2\"></i>Keeper's Toolkit</h1><p class=\"lead text-muted\">Tools to run your Call of Cthulhu sessions</p></div><!-- Tool Cards --><div class=\"row g-4 justify-content-center\"><!-- Chase Tracker Card --><div class=\"col-md-5\"><a href=\"/keeper/chase\" class=\"text-decoration-none\"><div class=\"card keeper-tool-card shadow-sm h-100\"><div class=\"card-body text-center p-4\"><div class=\"keeper-tool-icon mb-3\"><i class=\"bi bi-signpost-split\"></i></div><h3 class=\"card-title\">Chase Tracker</h3><p class=\"card-text text-muted\">Run dynamic chase sequences with participants, hazards, and obstacles. Track positions on a visual chase track.</p><div class=\"mt-3\"><span class=\"badge bg-secondary me-1\">Foot Chases</span> <span class=\"badge bg-secondary me-1\">Vehicle Chases</span> <span class=\"badge bg-secondary\">Hazards</span></div></div><div class=\"card-footer bg-transparent border-0 text-center pb-4\"><span class=\"btn btn-outline-primary\"><i class=\"bi bi-play-fill me-1\"></i>Start Chase</span></div></div></a></div><!-- Combat Tracker Card --><div class=\"col-md-5\"><a href=\"/keeper/combat\" class=\"text-decoration-none\"><div class=\"card keeper-tool-card shadow-sm h-100\"><div class=\"card-body text-center p-4\"><div class=\"keeper-tool-icon mb-3 text-danger\"><i class=\"bi bi-bullseye\"></i></div><h3 class=\"card-title\">Combat Tracker</h3><p class=\"card-text text-muted\">Manage turn-based combat encounters. Track initiative, HP, actions, and conditions for all combatants.</p><div class=\"mt-3\"><span class=\"badge bg-secondary me-1\">Initiative</span> <span class=\"badge bg-secondary me-1\">HP Tracking</span> <span class=\"badge bg-secondary\">Actions</span></div></div><div class=\"card-footer bg-transparent border-0 text-center pb-4\"><span class=\"btn btn-outline-danger\"><i class=\"bi bi-lightning-fill me-1\"></i>Start Combat</span></div></div></a></div></div><!-- Quick Tips --><div class=\"row mt-5\"><div class=\"col-12\"><div class=\"card shadow-sm\"><div class=\"card-header\"><i class=\"bi bi-lightbulb me-2\"></i>Quick Tips</div><div class=\"card-body\"><div class=\"row\"><div class=\"col-md-6\"><h6><i class=\"bi bi-signpost-split text-primary me-2\"></i>Chase Sequences</h6><ul class=\"small text-muted\"><li>Set up participants with their Movement rates</li><li>Add hazards and barriers at specific track positions</li><li>Participants must make skill checks to pass hazards</li><li>The chase ends when someone escapes or is caught</li></ul></div><div class=\"col-md-6\"><h6><i class=\"bi bi-crosshair text-danger me-2\"></i>Combat Encounters</h6><ul class=\"small text-muted\"><li>Roll DEX for initiative order</li><li>Track actions: Attack, Defend, Dodge, Flee</li><li>Major Wound at half HP or more damage in one hit</li><li>0 HP = Dying, needs First Aid or Medicine</li></ul></div></div></div></div></div></div></div></div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, " <div class=\"container-fluid p-4 coc-sheet\"><div class=\"keeper-dashboard\"><!-- Header --><div class=\"text-center mb-5\"><h1 class=\"display-5 fw-bold mb-3\"><i class=\"bi bi-shield-shaded me-2\"></i>Keeper's Toolkit</h1><p class=\"lead text-muted\">Tools to run your Call of Cthulhu sessions</p></div><!-- Tool Cards --><div class=\"row g-4 justify-content-center\"><!-- Chase Tracker Card --><div class=\"col-md-5\"><a href=\"/keeper/chase\" class=\"text-decoration-none\"><div class=\"card keeper-tool-card shadow-sm h-100\"><div class=\"card-body text-center p-4\"><div class=\"keeper-tool-icon mb-3\"><i class=\"bi bi-signpost-split\"></i></div><h3 class=\"card-title\">Chase Tracker</h3><p class=\"card-text text-muted\">Run dynamic chase sequences with participants, hazards, and obstacles. Track positions on a visual chase track.</p><div class=\"mt-3\"><span class=\"badge bg-secondary me-1\">Foot Chases</span> <span class=\"badge bg-secondary me-1\">Vehicle Chases</span> <span class=\"badge bg-secondary\">Hazards</span></div></div><div class=\"card-footer bg-transparent border-0 text-center pb-4\"><span class=\"btn btn-outline-primary\"><i class=\"bi bi-play-fill me-1\"></i>Start Chase</span></div></div></a></div><!-- Combat Tracker Card --><div class=\"col-md-5\"><a href=\"/keeper/combat\" class=\"text-decoration-none\"><div class=\"card keeper-tool-card shadow-sm h-100\"><div class=\"card-body text-center p-4\"><div class=\"keeper-tool-icon mb-3 text-danger\"><i class=\"bi bi-bullseye\"></i></div><h3 class=\"card-title\">Combat Tracker</h3><p class=\"card-text text-muted\">Manage turn-based combat encounters. Track initiative, HP, actions, and conditions for all combatants.</p><div class=\"mt-3\"><span class=\"badge bg-secondary me-1\">Initiative</span> <span class=\"badge bg-secondary me-1\">HP Tracking</span> <span class=\"badge bg-secondary\">Actions</span></div></div><div class=\"card-footer bg-transparent border-0 text-center pb-4\"><span class=\"btn btn-outline-danger\"><i class=\"bi bi-lightning-fill me-1\"></i>Start Combat</span></div></div></a></div><!-- Campaigns Card --><div class=\"col-md-5\"><a href=\"/keeper/campaigns\" class=\"text-decoration-none\"><div class=\"card keeper-tool-card shadow-sm h-100\"><div class=\"card-body text-center p-4\"><div class=\"keeper-tool-icon mb-3 text-success\"><i class=\"bi bi-people-fill\"></i></div><h3 class=\"card-title\">Campaigns</h3><p class=\"card-text text-muted\">Gather your players' investigators with a join code and keep an eye on the whole party at a glance.</p><div class=\"mt-3\"><span class=\"badge bg-secondary me-1\">Join Codes</span> <span class=\"badge bg-secondary me-1\">Party Overview</span> <span class=\"badge bg-secondary\">Status Flags</span></div></div><div class=\"card-footer bg-transparent border-0 text-center pb-4\"><span class=\"btn btn-outline-success\"><i class=\"bi bi-collection-fill me-1\"></i>Manage Campaigns</span></div></div></a></div></div><!-- Bout of Madness --><div class=\"row mt-5 justify-content-center\"><div class=\"col-md-10\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = boutOfMadness(investigators, campaigns).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "</div></div><!-- Quick Tips --><div class=\"row mt-5\"><div class=\"col-12\"><div class=\"card shadow-sm\"><div class=\"card-header\"><i class=\"bi bi-lightbulb me-2\"></i>Quick Tips</div><div class=\"card-body\"><div class=\"row\"><div class=\"col-md-6\"><h6><i class=\"bi bi-signpost-split text-primary me-2\"></i>Chase Sequences</h6><ul class=\"small text-muted\"><li>Set up participants with their Movement rates</li><li>Add hazards and barriers at specific track positions</li><li>Participants must make skill checks to pass hazards</li><li>The chase ends when someone escapes or is caught</li></ul></div><div class=\"col-md-6\"><h6><i class=\"bi bi-crosshair text-danger me-2\"></i>Combat Encounters</h6><ul class=\"small text-muted\"><li>Roll DEX for initiative order</li><li>Track actions: Attack, Defend, Dodge, Flee</li><li>Major Wound at half HP or more damage in one hit</li><li>0 HP = Dying, needs First Aid or Medicine</li></ul></div></div></div></div></div></div></div></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -63,6 +74,10 @@ func KeeperDashboard() templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, " <script src=\"/static/js/madness.js\"></script>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
 			return nil
 		})
 		templ_7745c5c3_Err = components.Layout("Keeper Tools - Book of Shadows").Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
@@ -73,7 +88,17 @@ func KeeperDashboard() templ.Component {
 	})
 }
 
-func ChaseTracker() templ.Component {
+// hasCampaignMembers reports whether any of the keeper's campaigns has members to roll for
+func hasCampaignMembers(campaigns []*models.Campaign) bool {
+	for _, campaign := range campaigns {
+		if len(campaign.Members) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func boutOfMadness(investigators []*models.Investigator, campaigns []*models.Campaign) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -94,7 +119,167 @@ func ChaseTracker() templ.Component {
 			templ_7745c5c3_Var3 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Var4 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "<div class=\"card shadow-sm\" id=\"bout-of-madness\"><div class=\"card-header\"><i class=\"bi bi-tornado me-2\"></i>Bout of Madness</div><div class=\"card-body\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if len(investigators) == 0 && !hasCampaignMembers(campaigns) {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "<p class=\"text-muted mb-0\">Create an investigator or run a campaign to roll bouts of madness for them.</p>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		} else {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "<form class=\"row g-2 align-items-end\" onsubmit=\"return Madness.roll(this);\"><div class=\"col-md-4\"><label class=\"form-label small\" for=\"bout-investigator\">Investigator</label> <select class=\"form-select\" id=\"bout-investigator\" name=\"investigator\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if len(investigators) > 0 {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "<optgroup label=\"My Investigators\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				for _, inv := range investigators {
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "<option value=\"")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					var templ_7745c5c3_Var4 string
+					templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(inv.ID)
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/keeper.templ`, Line: 181, Col: 32}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "\">")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					var templ_7745c5c3_Var5 string
+					templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(inv.Name)
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/keeper.templ`, Line: 181, Col: 45}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "</option>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "</optgroup> ")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			for _, campaign := range campaigns {
+				if len(campaign.Members) > 0 {
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "<optgroup label=\"")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					var templ_7745c5c3_Var6 string
+					templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(campaign.Name)
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/keeper.templ`, Line: 187, Col: 40}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "\">")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					for _, member := range campaign.Members {
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "<option value=\"")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						var templ_7745c5c3_Var7 string
+						templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(member.InvestigatorID)
+						if templ_7745c5c3_Err != nil {
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/keeper.templ`, Line: 189, Col: 48}
+						}
+						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "\" data-campaign=\"")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						var templ_7745c5c3_Var8 string
+						templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(campaign.ID)
+						if templ_7745c5c3_Err != nil {
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/keeper.templ`, Line: 189, Col: 78}
+						}
+						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "\">")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						var templ_7745c5c3_Var9 string
+						templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(member.PartyStatus().Name)
+						if templ_7745c5c3_Err != nil {
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/keeper.templ`, Line: 189, Col: 108}
+						}
+						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "</option>")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "</optgroup>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "</select></div><div class=\"col-md-3\"><label class=\"form-label small\" for=\"bout-kind\">Bout</label> <select class=\"form-select\" id=\"bout-kind\" name=\"kind\"><option value=\"realtime\">Real-time (1D10 rounds)</option> <option value=\"summary\">Summary (1D10 hours)</option></select></div><div class=\"col-md-3\"><div class=\"form-check\"><input class=\"form-check-input\" type=\"checkbox\" id=\"bout-add\" name=\"addCondition\" checked> <label class=\"form-check-label small\" for=\"bout-add\">Add phobia or mania to the sheet</label></div></div><div class=\"col-md-2 d-grid\"><button type=\"submit\" class=\"btn btn-outline-primary\"><i class=\"bi bi-dice-5 me-1\"></i>Roll</button></div></form><div id=\"bout-result\" class=\"mt-3 d-none\"></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "</div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+func ChaseTracker() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var10 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var10 == nil {
+			templ_7745c5c3_Var10 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Var11 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 			if !templ_7745c5c3_IsBuffer {
@@ -110,7 +295,7 @@ func ChaseTracker() templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, " ")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, " ")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -118,13 +303,13 @@ func ChaseTracker() templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, " <div class=\"container-fluid p-4 coc-sheet\"><div class=\"chase-tracker\"><!-- Header --><div class=\"d-flex justify-content-between align-items-center mb-4\"><div><a href=\"/keeper\" class=\"btn btn-sm btn-outline-secondary me-2\"><i class=\"bi bi-arrow-left\"></i></a> <span class=\"h4 mb-0\"><i class=\"bi bi-signpost-split me-2\"></i>Chase Tracker</span></div><div><span class=\"badge bg-secondary me-2\" id=\"chase-status\">Setup</span> <span class=\"badge bg-primary\" id=\"chase-round\">Round 0</span></div></div><div class=\"row g-4\"><!-- Left Column: Setup & Participants --><div class=\"col-lg-4\"><!-- Add Participant --><div class=\"card shadow-sm mb-4\"><div class=\"card-header\"><i class=\"bi bi-person-plus me-2\"></i>Add Participant</div><div class=\"card-body\"><div class=\"mb-3\"><label class=\"form-label\">Name</label> <input type=\"text\" class=\"form-control\" id=\"participant-name\" placeholder=\"Enter name...\"></div><div class=\"row mb-3\"><div class=\"col-6\"><label class=\"form-label\">Type</label> <select class=\"form-select\" id=\"participant-type\"><option value=\"investigator\">Investigator</option> <option value=\"enemy\">Enemy</option> <option value=\"npc\">NPC</option></select></div><div class=\"col-6\"><label class=\"form-label\">Speed (MOV)</label> <input type=\"number\" class=\"form-control\" id=\"participant-speed\" value=\"8\" min=\"1\" max=\"15\"></div></div><button class=\"btn btn-primary w-100\" onclick=\"ChaseTracker.addParticipant()\"><i class=\"bi bi-plus-lg me-1\"></i>Add to Chase</button></div></div><!-- Participants List --><div class=\"card shadow-sm mb-4\"><div class=\"card-header d-flex justify-content-between align-items-center\"><span><i class=\"bi bi-people me-2\"></i>Participants</span> <span class=\"badge bg-secondary\" id=\"participant-count\">0</span></div><div class=\"card-body p-0\"><div id=\"participants-list\" class=\"participants-list\"><div class=\"text-center text-muted p-4\"><i class=\"bi bi-person-dash display-6\"></i><p class=\"mt-2 mb-0\">No participants yet</p></div></div></div></div><!-- Add Hazard --><div class=\"card shadow-sm\"><div class=\"card-header\"><i class=\"bi bi-exclamation-triangle me-2\"></i>Add Hazard</div><div class=\"card-body\"><div class=\"mb-3\"><label class=\"form-label\">Position</label> <input type=\"number\" class=\"form-control\" id=\"hazard-position\" value=\"3\" min=\"1\" max=\"20\"></div><div class=\"mb-3\"><label class=\"form-label\">Name</label> <input type=\"text\" class=\"form-control\" id=\"hazard-name\" placeholder=\"e.g., Fence, Crowd...\"></div><div class=\"mb-3\"><label class=\"form-label\">Skill Check</label> <input type=\"text\" class=\"form-control\" id=\"hazard-skill\" placeholder=\"e.g., Jump, DEX\"></div><button class=\"btn btn-warning w-100\" onclick=\"ChaseTracker.addHazard()\"><i class=\"bi bi-plus-lg me-1\"></i>Add Hazard</button></div></div></div><!-- Right Column: Chase Track & Controls --><div class=\"col-lg-8\"><!-- Chase Track --><div class=\"card shadow-sm mb-4\"><div class=\"card-header d-flex justify-content-between align-items-center\"><span><i class=\"bi bi-signpost me-2\"></i>Chase Track</span><div><label class=\"me-2 small\">Track Length:</label> <input type=\"number\" class=\"form-control form-control-sm d-inline-block\" style=\"width: 70px;\" id=\"track-length\" value=\"10\" min=\"5\" max=\"30\" onchange=\"ChaseTracker.updateTrackLength()\"></div></div><div class=\"card-body\"><div id=\"chase-track\" class=\"chase-track\"><!-- Track positions will be rendered here --></div></div></div><!-- Round Controls --><div class=\"card shadow-sm mb-4\"><div class=\"card-header\"><i class=\"bi bi-controller me-2\"></i>Controls</div><div class=\"card-body\"><div class=\"d-flex gap-2 flex-wrap mb-3\"><button class=\"btn btn-success\" onclick=\"ChaseTracker.startChase()\" id=\"btn-start\"><i class=\"bi bi-play-fill me-1\"></i>Start Chase</button> <button class=\"btn btn-primary\" onclick=\"ChaseTracker.nextRound()\" id=\"btn-next-round\" disabled><i class=\"bi bi-skip-forward-fill me-1\"></i>Next Round</button> <button class=\"btn btn-outline-secondary\" onclick=\"ChaseTracker.rollMovement()\" id=\"btn-roll-movement\" disabled><i class=\"bi bi-dice-5 me-1\"></i>Roll All Movement</button> <button class=\"btn btn-outline-danger\" onclick=\"ChaseTracker.endChase()\" id=\"btn-end\"><i class=\"bi bi-stop-fill me-1\"></i>End Chase</button> <button class=\"btn btn-outline-warning\" onclick=\"ChaseTracker.reset()\"><i class=\"bi bi-arrow-counterclockwise me-1\"></i>Reset</button></div><!-- Manual Movement Input --><div class=\"manual-move-section border-top pt-3\"><label class=\"form-label fw-bold\"><i class=\"bi bi-person-walking me-1\"></i>Move Participant</label><div class=\"row g-2 align-items-end\"><div class=\"col-5\"><label class=\"form-label small\">Participant</label> <select class=\"form-select form-select-sm\" id=\"manual-move-participant\"><option value=\"\">Select...</option></select></div><div class=\"col-3\"><label class=\"form-label small\">Spaces</label> <input type=\"number\" class=\"form-control form-control-sm\" id=\"manual-move-spaces\" value=\"1\" min=\"-10\" max=\"10\"></div><div class=\"col-4\"><button class=\"btn btn-primary btn-sm w-100\" onclick=\"ChaseTracker.manualMove()\"><i class=\"bi bi-arrow-right me-1\"></i>Move</button></div></div></div></div></div><!-- Action Log --><div class=\"card shadow-sm\"><div class=\"card-header d-flex justify-content-between align-items-center\"><span><i class=\"bi bi-journal-text me-2\"></i>Chase Log</span> <button class=\"btn btn-sm btn-outline-secondary\" onclick=\"ChaseTracker.clearLog()\">Clear</button></div><div class=\"card-body p-0\"><div id=\"chase-log\" class=\"action-log\"><div class=\"log-entry text-muted\"><i class=\"bi bi-info-circle me-1\"></i> Add participants and hazards, then start the chase.</div></div></div></div></div></div></div></div><!-- Confirmation Modal --> <div class=\"modal fade\" id=\"confirm-modal\" tabindex=\"-1\" aria-hidden=\"true\"><div class=\"modal-dialog modal-dialog-centered\"><div class=\"modal-content\"><div class=\"modal-header border-bottom-0\"><h5 class=\"modal-title\" id=\"confirm-modal-title\"><i class=\"bi bi-exclamation-triangle text-warning me-2\"></i>Confirm Reset</h5><button type=\"button\" class=\"btn-close\" data-bs-dismiss=\"modal\" aria-label=\"Close\"></button></div><div class=\"modal-body\" id=\"confirm-modal-body\"><p>Are you sure you want to reset? This will clear all data.</p></div><div class=\"modal-footer border-top-0\"><button type=\"button\" class=\"btn btn-secondary\" data-bs-dismiss=\"modal\">Cancel</button> <button type=\"button\" class=\"btn btn-danger\" id=\"confirm-modal-btn\" data-bs-dismiss=\"modal\"><i class=\"bi bi-arrow-counterclockwise me-1\"></i>Reset</button></div></div></div></div><script src=\"/static/js/chase-tracker.js\"></script> <script>\n\t\t\tdocument.addEventListener('DOMContentLoaded', () => {\n\t\t\t\tChaseTracker.init();\n\t\t\t});\n\t\t</script>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, " <div class=\"container-fluid p-4 coc-sheet\"><div class=\"chase-tracker\"><!-- Header --><div class=\"d-flex justify-content-between align-items-center mb-4\"><div><a href=\"/keeper\" class=\"btn btn-sm btn-outline-secondary me-2\"><i class=\"bi bi-arrow-left\"></i></a> <span class=\"h4 mb-0\"><i class=\"bi bi-signpost-split me-2\"></i>Chase Tracker</span></div><div><span class=\"badge bg-secondary me-2\" id=\"chase-status\">Setup</span> <span class=\"badge bg-primary\" id=\"chase-round\">Round 0</span></div></div><div class=\"row g-4\"><!-- Left Column: Setup & Participants --><div class=\"col-lg-4\"><!-- Add Participant --><div class=\"card shadow-sm mb-4\"><div class=\"card-header\"><i class=\"bi bi-person-plus me-2\"></i>Add Participant</div><div class=\"card-body\"><div class=\"mb-3\"><label class=\"form-label\">Name</label> <input type=\"text\" class=\"form-control\" id=\"participant-name\" placeholder=\"Enter name...\"></div><div class=\"row mb-3\"><div class=\"col-6\"><label class=\"form-label\">Type</label> <select class=\"form-select\" id=\"participant-type\"><option value=\"investigator\">Investigator</option> <option value=\"enemy\">Enemy</option> <option value=\"npc\">NPC</option></select></div><div class=\"col-6\"><label class=\"form-label\">Speed (MOV)</label> <input type=\"number\" class=\"form-control\" id=\"participant-speed\" value=\"8\" min=\"1\" max=\"15\"></div></div><button class=\"btn btn-primary w-100\" onclick=\"ChaseTracker.addParticipant()\"><i class=\"bi bi-plus-lg me-1\"></i>Add to Chase</button></div></div><!-- Participants List --><div class=\"card shadow-sm mb-4\"><div class=\"card-header d-flex justify-content-between align-items-center\"><span><i class=\"bi bi-people me-2\"></i>Participants</span> <span class=\"badge bg-secondary\" id=\"participant-count\">0</span></div><div class=\"card-body p-0\"><div id=\"participants-list\" class=\"participants-list\"><div class=\"text-center text-muted p-4\"><i class=\"bi bi-person-dash display-6\"></i><p class=\"mt-2 mb-0\">No participants yet</p></div></div></div></div><!-- Add Hazard --><div class=\"card shadow-sm\"><div class=\"card-header\"><i class=\"bi bi-exclamation-triangle me-2\"></i>Add Hazard</div><div class=\"card-body\"><div class=\"mb-3\"><label class=\"form-label\">Position</label> <input type=\"number\" class=\"form-control\" id=\"hazard-position\" value=\"3\" min=\"1\" max=\"20\"></div><div class=\"mb-3\"><label class=\"form-label\">Name</label> <input type=\"text\" class=\"form-control\" id=\"hazard-name\" placeholder=\"e.g., Fence, Crowd...\"></div><div class=\"mb-3\"><label class=\"form-label\">Skill Check</label> <input type=\"text\" class=\"form-control\" id=\"hazard-skill\" placeholder=\"e.g., Jump, DEX\"></div><button class=\"btn btn-warning w-100\" onclick=\"ChaseTracker.addHazard()\"><i class=\"bi bi-plus-lg me-1\"></i>Add Hazard</button></div></div></div><!-- Right Column: Chase Track & Controls --><div class=\"col-lg-8\"><!-- Chase Track --><div class=\"card shadow-sm mb-4\"><div class=\"card-header d-flex justify-content-between align-items-center\"><span><i class=\"bi bi-signpost me-2\"></i>Chase Track</span><div><label class=\"me-2 small\">Track Length:</label> <input type=\"number\" class=\"form-control form-control-sm d-inline-block\" style=\"width: 70px;\" id=\"track-length\" value=\"10\" min=\"5\" max=\"30\" onchange=\"ChaseTracker.updateTrackLength()\"></div></div><div class=\"card-body\"><div id=\"chase-track\" class=\"chase-track\"><!-- Track positions will be rendered here --></div></div></div><!-- Round Controls --><div class=\"card shadow-sm mb-4\"><div class=\"card-header\"><i class=\"bi bi-controller me-2\"></i>Controls</div><div class=\"card-body\"><div class=\"d-flex gap-2 flex-wrap mb-3\"><button class=\"btn btn-success\" onclick=\"ChaseTracker.startChase()\" id=\"btn-start\"><i class=\"bi bi-play-fill me-1\"></i>Start Chase</button> <button class=\"btn btn-primary\" onclick=\"ChaseTracker.nextRound()\" id=\"btn-next-round\" disabled><i class=\"bi bi-skip-forward-fill me-1\"></i>Next Round</button> <button class=\"btn btn-outline-secondary\" onclick=\"ChaseTracker.rollMovement()\" id=\"btn-roll-movement\" disabled><i class=\"bi bi-dice-5 me-1\"></i>Roll All Movement</button> <button class=\"btn btn-outline-danger\" onclick=\"ChaseTracker.endChase()\" id=\"btn-end\"><i class=\"bi bi-stop-fill me-1\"></i>End Chase</button> <button class=\"btn btn-outline-warning\" onclick=\"ChaseTracker.reset()\"><i class=\"bi bi-arrow-counterclockwise me-1\"></i>Reset</button></div><!-- Manual Movement Input --><div class=\"manual-move-section border-top pt-3\"><label class=\"form-label fw-bold\"><i class=\"bi bi-person-walking me-1\"></i>Move Participant</label><div class=\"row g-2 align-items-end\"><div class=\"col-5\"><label class=\"form-label small\">Participant</label> <select class=\"form-select form-select-sm\" id=\"manual-move-participant\"><option value=\"\">Select...</option></select></div><div class=\"col-3\"><label class=\"form-label small\">Spaces</label> <input type=\"number\" class=\"form-control form-control-sm\" id=\"manual-move-spaces\" value=\"1\" min=\"-10\" max=\"10\"></div><div class=\"col-4\"><button class=\"btn btn-primary btn-sm w-100\" onclick=\"ChaseTracker.manualMove()\"><i class=\"bi bi-arrow-right me-1\"></i>Move</button></div></div></div></div></div><!-- Action Log --><div class=\"card shadow-sm\"><div class=\"card-header d-flex justify-content-between align-items-center\"><span><i class=\"bi bi-journal-text me-2\"></i>Chase Log</span> <button class=\"btn btn-sm btn-outline-secondary\" onclick=\"ChaseTracker.clearLog()\">Clear</button></div><div class=\"card-body p-0\"><div id=\"chase-log\" class=\"action-log\"><div class=\"log-entry text-muted\"><i class=\"bi bi-info-circle me-1\"></i> Add participants and hazards, then start the chase.</div></div></div></div></div></div></div></div><!-- Confirmation Modal --> <div class=\"modal fade\" id=\"confirm-modal\" tabindex=\"-1\" aria-hidden=\"true\"><div class=\"modal-dialog modal-dialog-centered\"><div class=\"modal-content\"><div class=\"modal-header border-bottom-0\"><h5 class=\"modal-title\" id=\"confirm-modal-title\"><i class=\"bi bi-exclamation-triangle text-warning me-2\"></i>Confirm Reset</h5><button type=\"button\" class=\"btn-close\" data-bs-dismiss=\"modal\" aria-label=\"Close\"></button></div><div class=\"modal-body\" id=\"confirm-modal-body\"><p>Are you sure you want to reset? This will clear all data.</p></div><div class=\"modal-footer border-top-0\"><button type=\"button\" class=\"btn btn-secondary\" data-bs-dismiss=\"modal\">Cancel</button> <button type=\"button\" class=\"btn btn-danger\" id=\"confirm-modal-btn\" data-bs-dismiss=\"modal\"><i class=\"bi bi-arrow-counterclockwise me-1\"></i>Reset</button></div></div></div></div><script src=\"/static/js/chase-tracker.js\"></script> <script>\n\t\t\tdocument.addEventListener('DOMContentLoaded', () => {\n\t\t\t\tChaseTracker.init();\n\t\t\t});\n\t\t</script>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			return nil
 		})
-		templ_7745c5c3_Err = components.Layout("Chase Tracker - Keeper Tools").Render(templ.WithChildren(ctx, templ_7745c5c3_Var4), templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = components.Layout("Chase Tracker - Keeper Tools").Render(templ.WithChildren(ctx, templ_7745c5c3_Var11), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -148,12 +333,12 @@ func CombatTracker() templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var5 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var5 == nil {
-			templ_7745c5c3_Var5 = templ.NopComponent
+		templ_7745c5c3_Var12 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var12 == nil {
+			templ_7745c5c3_Var12 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Var6 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_Var13 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 			templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 			templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 			if !templ_7745c5c3_IsBuffer {
@@ -169,7 +354,7 @@ func CombatTracker() templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, " ")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, " ")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -177,13 +362,13 @@ func CombatTracker() templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, " <div class=\"container-fluid p-4 coc-sheet\"><div class=\"combat-tracker\"><!-- Header --><div class=\"d-flex justify-content-between align-items-center mb-4\"><div><a href=\"/keeper\" class=\"btn btn-sm btn-outline-secondary me-2\"><i class=\"bi bi-arrow-left\"></i></a> <span class=\"h4 mb-0\"><i class=\"bi bi-bullseye me-2\"></i>Combat Tracker</span></div><div><span class=\"badge bg-secondary me-2\" id=\"combat-status\">Setup</span> <span class=\"badge bg-danger\" id=\"combat-round\">Round 0</span></div></div><div class=\"row g-4\"><!-- Left Column: Setup & Initiative --><div class=\"col-lg-4\"><!-- Add Combatant --><div class=\"card shadow-sm mb-4\"><div class=\"card-header\"><i class=\"bi bi-person-plus me-2\"></i>Add Combatant</div><div class=\"card-body\"><div class=\"mb-3\"><label class=\"form-label\">Name</label> <input type=\"text\" class=\"form-control\" id=\"combatant-name\" placeholder=\"Enter name...\"></div><div class=\"row mb-3\"><div class=\"col-6\"><label class=\"form-label\">Type</label> <select class=\"form-select\" id=\"combatant-type\"><option value=\"investigator\">Investigator</option> <option value=\"enemy\">Enemy</option> <option value=\"npc\">NPC</option></select></div><div class=\"col-6\"><label class=\"form-label\">Max HP</label> <input type=\"number\" class=\"form-control\" id=\"combatant-hp\" value=\"12\" min=\"1\" max=\"100\"></div></div><div class=\"mb-3\"><label class=\"form-label\">DEX (for Initiative)</label> <input type=\"number\" class=\"form-control\" id=\"combatant-dex\" value=\"50\" min=\"1\" max=\"99\"></div><button class=\"btn btn-primary w-100\" onclick=\"CombatTracker.addCombatant()\"><i class=\"bi bi-plus-lg me-1\"></i>Add to Combat</button></div></div><!-- Initiative Order --><div class=\"card shadow-sm\"><div class=\"card-header d-flex justify-content-between align-items-center\"><span><i class=\"bi bi-sort-numeric-down me-2\"></i>Initiative Order</span> <button class=\"btn btn-sm btn-outline-primary\" onclick=\"CombatTracker.rollAllInitiative()\"><i class=\"bi bi-dice-5 me-1\"></i>Roll All</button></div><div class=\"card-body p-0\"><div id=\"initiative-list\" class=\"initiative-list\"><div class=\"text-center text-muted p-4\"><i class=\"bi bi-hourglass display-6\"></i><p class=\"mt-2 mb-0\">No combatants yet</p></div></div></div></div></div><!-- Right Column: Combat Area & Log --><div class=\"col-lg-8\"><!-- Active Combatant --><div class=\"card shadow-sm mb-4\" id=\"active-combatant-card\" style=\"display: none;\"><div class=\"card-header bg-danger text-white\"><i class=\"bi bi-lightning-fill me-2\"></i>Active Turn</div><div class=\"card-body\"><div class=\"row align-items-center\"><div class=\"col-md-3\"><h4 id=\"active-combatant-name\" class=\"mb-1\">-</h4><span class=\"badge\" id=\"active-combatant-type\">-</span> <span class=\"badge bg-warning ms-1\" id=\"active-combatant-major-wound\" style=\"display: none;\">Major Wound</span></div><div class=\"col-md-3\"><div class=\"hp-display\"><div class=\"hp-bar-container\"><div class=\"hp-bar\" id=\"active-combatant-hp-bar\" style=\"width: 100%\"></div></div><span class=\"hp-text\"><span id=\"active-combatant-hp\">0</span>/<span id=\"active-combatant-maxhp\">0</span> HP</span></div></div><div class=\"col-md-6\"><div class=\"d-flex gap-2 align-items-center justify-content-end\"><div class=\"input-group input-group-sm\" style=\"width: 150px;\"><input type=\"number\" class=\"form-control\" id=\"active-hp-amount\" value=\"1\" min=\"1\" max=\"99\"> <button class=\"btn btn-success\" onclick=\"CombatTracker.healActive()\"><i class=\"bi bi-plus\"></i></button> <button class=\"btn btn-danger\" onclick=\"CombatTracker.damageActive()\"><i class=\"bi bi-dash\"></i></button></div><div class=\"form-check form-switch ms-2\"><input class=\"form-check-input\" type=\"checkbox\" id=\"active-major-wound\" onchange=\"CombatTracker.toggleActiveMajorWound()\"> <label class=\"form-check-label small\" for=\"active-major-wound\">Major Wound</label></div></div></div></div></div></div><!-- Action Panel --><div class=\"card shadow-sm mb-4\"><div class=\"card-header\"><i class=\"bi bi-joystick me-2\"></i>Actions</div><div class=\"card-body\"><!-- Target Selection --><div class=\"row g-3 mb-3\"><div class=\"col-12\"><label class=\"form-label\"><i class=\"bi bi-crosshair me-1\"></i>Target</label> <select class=\"form-select form-select-lg\" id=\"action-target\"><option value=\"\">Select target...</option></select></div></div><!-- Damage Section --><div class=\"row g-3 mb-3\"><div class=\"col-md-4\"><label class=\"form-label\"><i class=\"bi bi-droplet-fill text-danger me-1\"></i>Damage to Target</label> <input type=\"number\" class=\"form-control\" id=\"action-damage\" value=\"0\" min=\"0\" max=\"99\" placeholder=\"Damage dealt\"></div><div class=\"col-md-4\"><label class=\"form-label\"><i class=\"bi bi-arrow-left-right text-warning me-1\"></i>Fight Back Damage</label> <input type=\"number\" class=\"form-control\" id=\"action-fightback\" value=\"0\" min=\"0\" max=\"99\" placeholder=\"Damage received\"></div><div class=\"col-md-4 d-flex align-items-end\"><button class=\"btn btn-primary w-100\" onclick=\"CombatTracker.nextTurn()\" id=\"btn-next-turn\" disabled><i class=\"bi bi-skip-forward-fill me-1\"></i>Next Turn</button></div></div><!-- Action Buttons --><div class=\"d-flex gap-2 flex-wrap\"><button class=\"btn btn-outline-danger action-btn\" onclick=\"CombatTracker.recordAction('attack')\"><i class=\"bi bi-bullseye\"></i> Attack</button> <button class=\"btn btn-outline-primary action-btn\" onclick=\"CombatTracker.recordAction('defend')\"><i class=\"bi bi-shield\"></i> Defend</button> <button class=\"btn btn-outline-warning action-btn\" onclick=\"CombatTracker.recordAction('dodge')\"><i class=\"bi bi-arrows-move\"></i> Dodge</button> <button class=\"btn btn-outline-secondary action-btn\" onclick=\"CombatTracker.recordAction('flee')\"><i class=\"bi bi-box-arrow-right\"></i> Flee</button> <button class=\"btn btn-outline-info action-btn\" onclick=\"CombatTracker.recordAction('spell')\"><i class=\"bi bi-stars\"></i> Spell</button> <button class=\"btn btn-outline-success action-btn\" onclick=\"CombatTracker.recordAction('item')\"><i class=\"bi bi-bag\"></i> Item</button></div></div></div><!-- Round Controls --><div class=\"card shadow-sm mb-4\"><div class=\"card-header\"><i class=\"bi bi-controller me-2\"></i>Combat Controls</div><div class=\"card-body\"><div class=\"d-flex gap-2 flex-wrap\"><button class=\"btn btn-success\" onclick=\"CombatTracker.startCombat()\" id=\"btn-start-combat\"><i class=\"bi bi-play-fill me-1\"></i>Start Combat</button> <button class=\"btn btn-primary\" onclick=\"CombatTracker.nextRound()\" id=\"btn-next-combat-round\" disabled><i class=\"bi bi-arrow-repeat me-1\"></i>Next Round</button> <button class=\"btn btn-outline-danger\" onclick=\"CombatTracker.endCombat()\" id=\"btn-end-combat\"><i class=\"bi bi-stop-fill me-1\"></i>End Combat</button> <button class=\"btn btn-outline-warning\" onclick=\"CombatTracker.reset()\"><i class=\"bi bi-arrow-counterclockwise me-1\"></i>Reset</button></div></div></div><!-- Combat Log --><div class=\"card shadow-sm\"><div class=\"card-header d-flex justify-content-between align-items-center\"><span><i class=\"bi bi-journal-text me-2\"></i>Combat Log</span> <button class=\"btn btn-sm btn-outline-secondary\" onclick=\"CombatTracker.clearLog()\">Clear</button></div><div class=\"card-body p-0\"><div id=\"combat-log\" class=\"action-log\"><div class=\"log-entry text-muted\"><i class=\"bi bi-info-circle me-1\"></i> Add combatants, roll initiative, then start combat.</div></div></div></div></div></div></div></div><!-- Confirmation Modal --> <div class=\"modal fade\" id=\"confirm-modal\" tabindex=\"-1\" aria-hidden=\"true\"><div class=\"modal-dialog modal-dialog-centered\"><div class=\"modal-content\"><div class=\"modal-header border-bottom-0\"><h5 class=\"modal-title\" id=\"confirm-modal-title\"><i class=\"bi bi-exclamation-triangle text-warning me-2\"></i>Confirm Reset</h5><button type=\"button\" class=\"btn-close\" data-bs-dismiss=\"modal\" aria-label=\"Close\"></button></div><div class=\"modal-body\" id=\"confirm-modal-body\"><p>Are you sure you want to reset? This will clear all data.</p></div><div class=\"modal-footer border-top-0\"><button type=\"button\" class=\"btn btn-secondary\" data-bs-dismiss=\"modal\">Cancel</button> <button type=\"button\" class=\"btn btn-danger\" id=\"confirm-modal-btn\" data-bs-dismiss=\"modal\"><i class=\"bi bi-arrow-counterclockwise me-1\"></i>Reset</button></div></div></div></div><script src=\"/static/js/combat-tracker.js\"></script> <script>\n\t\t\tdocument.addEventListener('DOMContentLoaded', () => {\n\t\t\t\tCombatTracker.init();\n\t\t\t});\n\t\t</script>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, " <div class=\"container-fluid p-4 coc-sheet\"><div class=\"combat-tracker\"><!-- Header --><div class=\"d-flex justify-content-between align-items-center mb-4\"><div><a href=\"/keeper\" class=\"btn btn-sm btn-outline-secondary me-2\"><i class=\"bi bi-arrow-left\"></i></a> <span class=\"h4 mb-0\"><i class=\"bi bi-bullseye me-2\"></i>Combat Tracker</span></div><div><span class=\"badge bg-secondary me-2\" id=\"combat-status\">Setup</span> <span class=\"badge bg-danger\" id=\"combat-round\">Round 0</span></div></div><div class=\"row g-4\"><!-- Left Column: Setup & Initiative --><div class=\"col-lg-4\"><!-- Add Combatant --><div class=\"card shadow-sm mb-4\"><div class=\"card-header\"><i class=\"bi bi-person-plus me-2\"></i>Add Combatant</div><div class=\"card-body\"><div class=\"mb-3\"><label class=\"form-label\">Name</label> <input type=\"text\" class=\"form-control\" id=\"combatant-name\" placeholder=\"Enter name...\"></div><div class=\"row mb-3\"><div class=\"col-6\"><label class=\"form-label\">Type</label> <select class=\"form-select\" id=\"combatant-type\"><option value=\"investigator\">Investigator</option> <option value=\"enemy\">Enemy</option> <option value=\"npc\">NPC</option></select></div><div class=\"col-6\"><label class=\"form-label\">Max HP</label> <input type=\"number\" class=\"form-control\" id=\"combatant-hp\" value=\"12\" min=\"1\" max=\"100\"></div></div><div class=\"mb-3\"><label class=\"form-label\">DEX (for Initiative)</label> <input type=\"number\" class=\"form-control\" id=\"combatant-dex\" value=\"50\" min=\"1\" max=\"99\"></div><button class=\"btn btn-primary w-100\" onclick=\"CombatTracker.addCombatant()\"><i class=\"bi bi-plus-lg me-1\"></i>Add to Combat</button></div></div><!-- Initiative Order --><div class=\"card shadow-sm\"><div class=\"card-header d-flex justify-content-between align-items-center\"><span><i class=\"bi bi-sort-numeric-down me-2\"></i>Initiative Order</span> <button class=\"btn btn-sm btn-outline-primary\" onclick=\"CombatTracker.rollAllInitiative()\"><i class=\"bi bi-dice-5 me-1\"></i>Roll All</button></div><div class=\"card-body p-0\"><div id=\"initiative-list\" class=\"initiative-list\"><div class=\"text-center text-muted p-4\"><i class=\"bi bi-hourglass display-6\"></i><p class=\"mt-2 mb-0\">No combatants yet</p></div></div></div></div></div><!-- Right Column: Combat Area & Log --><div class=\"col-lg-8\"><!-- Active Combatant --><div class=\"card shadow-sm mb-4\" id=\"active-combatant-card\" style=\"display: none;\"><div class=\"card-header bg-danger text-white\"><i class=\"bi bi-lightning-fill me-2\"></i>Active Turn</div><div class=\"card-body\"><div class=\"row align-items-center\"><div class=\"col-md-3\"><h4 id=\"active-combatant-name\" class=\"mb-1\">-</h4><span class=\"badge\" id=\"active-combatant-type\">-</span> <span class=\"badge bg-warning ms-1\" id=\"active-combatant-major-wound\" style=\"display: none;\">Major Wound</span></div><div class=\"col-md-3\"><div class=\"hp-display\"><div class=\"hp-bar-container\"><div class=\"hp-bar\" id=\"active-combatant-hp-bar\" style=\"width: 100%\"></div></div><span class=\"hp-text\"><span id=\"active-combatant-hp\">0</span>/<span id=\"active-combatant-maxhp\">0</span> HP</span></div></div><div class=\"col-md-6\"><div class=\"d-flex gap-2 align-items-center justify-content-end\"><div class=\"input-group input-group-sm\" style=\"width: 150px;\"><input type=\"number\" class=\"form-control\" id=\"active-hp-amount\" value=\"1\" min=\"1\" max=\"99\"> <button class=\"btn btn-success\" onclick=\"CombatTracker.healActive()\"><i class=\"bi bi-plus\"></i></button> <button class=\"btn btn-danger\" onclick=\"CombatTracker.damageActive()\"><i class=\"bi bi-dash\"></i></button></div><div class=\"form-check form-switch ms-2\"><input class=\"form-check-input\" type=\"checkbox\" id=\"active-major-wound\" onchange=\"CombatTracker.toggleActiveMajorWound()\"> <label class=\"form-check-label small\" for=\"active-major-wound\">Major Wound</label></div></div></div></div></div></div><!-- Action Panel --><div class=\"card shadow-sm mb-4\"><div class=\"card-header\"><i class=\"bi bi-joystick me-2\"></i>Actions</div><div class=\"card-body\"><!-- Target Selection --><div class=\"row g-3 mb-3\"><div class=\"col-12\"><label class=\"form-label\"><i class=\"bi bi-crosshair me-1\"></i>Target</label> <select class=\"form-select form-select-lg\" id=\"action-target\"><option value=\"\">Select target...</option></select></div></div><!-- Damage Section --><div class=\"row g-3 mb-3\"><div class=\"col-md-4\"><label class=\"form-label\"><i class=\"bi bi-droplet-fill text-danger me-1\"></i>Damage to Target</label> <input type=\"number\" class=\"form-control\" id=\"action-damage\" value=\"0\" min=\"0\" max=\"99\" placeholder=\"Damage dealt\"></div><div class=\"col-md-4\"><label class=\"form-label\"><i class=\"bi bi-arrow-left-right text-warning me-1\"></i>Fight Back Damage</label> <input type=\"number\" class=\"form-control\" id=\"action-fightback\" value=\"0\" min=\"0\" max=\"99\" placeholder=\"Damage received\"></div><div class=\"col-md-4 d-flex align-items-end\"><button class=\"btn btn-primary w-100\" onclick=\"CombatTracker.nextTurn()\" id=\"btn-next-turn\" disabled><i class=\"bi bi-skip-forward-fill me-1\"></i>Next Turn</button></div></div><!-- Action Buttons --><div class=\"d-flex gap-2 flex-wrap\"><button class=\"btn btn-outline-danger action-btn\" onclick=\"CombatTracker.recordAction('attack')\"><i class=\"bi bi-bullseye\"></i> Attack</button> <button class=\"btn btn-outline-primary action-btn\" onclick=\"CombatTracker.recordAction('defend')\"><i class=\"bi bi-shield\"></i> Defend</button> <button class=\"btn btn-outline-warning action-btn\" onclick=\"CombatTracker.recordAction('dodge')\"><i class=\"bi bi-arrows-move\"></i> Dodge</button> <button class=\"btn btn-outline-secondary action-btn\" onclick=\"CombatTracker.recordAction('flee')\"><i class=\"bi bi-box-arrow-right\"></i> Flee</button> <button class=\"btn btn-outline-info action-btn\" onclick=\"CombatTracker.recordAction('spell')\"><i class=\"bi bi-stars\"></i> Spell</button> <button class=\"btn btn-outline-success action-btn\" onclick=\"CombatTracker.recordAction('item')\"><i class=\"bi bi-bag\"></i> Item</button></div></div></div><!-- Round Controls --><div class=\"card shadow-sm mb-4\"><div class=\"card-header\"><i class=\"bi bi-controller me-2\"></i>Combat Controls</div><div class=\"card-body\"><div class=\"d-flex gap-2 flex-wrap\"><button class=\"btn btn-success\" onclick=\"CombatTracker.startCombat()\" id=\"btn-start-combat\"><i class=\"bi bi-play-fill me-1\"></i>Start Combat</button> <button class=\"btn btn-primary\" onclick=\"CombatTracker.nextRound()\" id=\"btn-next-combat-round\" disabled><i class=\"bi bi-arrow-repeat me-1\"></i>Next Round</button> <button class=\"btn btn-outline-danger\" onclick=\"CombatTracker.endCombat()\" id=\"btn-end-combat\"><i class=\"bi bi-stop-fill me-1\"></i>End Combat</button> <button class=\"btn btn-outline-warning\" onclick=\"CombatTracker.reset()\"><i class=\"bi bi-arrow-counterclockwise me-1\"></i>Reset</button></div></div></div><!-- Combat Log --><div class=\"card shadow-sm\"><div class=\"card-header d-flex justify-content-between align-items-center\"><span><i class=\"bi bi-journal-text me-2\"></i>Combat Log</span> <button class=\"btn btn-sm btn-outline-secondary\" onclick=\"CombatTracker.clearLog()\">Clear</button></div><div class=\"card-body p-0\"><div id=\"combat-log\" class=\"action-log\"><div class=\"log-entry text-muted\"><i class=\"bi bi-info-circle me-1\"></i> Add combatants, roll initiative, then start combat.</div></div></div></div></div></div></div></div><!-- Confirmation Modal --> <div class=\"modal fade\" id=\"confirm-modal\" tabindex=\"-1\" aria-hidden=\"true\"><div class=\"modal-dialog modal-dialog-centered\"><div class=\"modal-content\"><div class=\"modal-header border-bottom-0\"><h5 class=\"modal-title\" id=\"confirm-modal-title\"><i class=\"bi bi-exclamation-triangle text-warning me-2\"></i>Confirm Reset</h5><button type=\"button\" class=\"btn-close\" data-bs-dismiss=\"modal\" aria-label=\"Close\"></button></div><div class=\"modal-body\" id=\"confirm-modal-body\"><p>Are you sure you want to reset? This will clear all data.</p></div><div class=\"modal-footer border-top-0\"><button type=\"button\" class=\"btn btn-secondary\" data-bs-dismiss=\"modal\">Cancel</button> <button type=\"button\" class=\"btn btn-danger\" id=\"confirm-modal-btn\" data-bs-dismiss=\"modal\"><i class=\"bi bi-arrow-counterclockwise me-1\"></i>Reset</button></div></div></div></div><script src=\"/static/js/combat-tracker.js\"></script> <script>\n\t\t\tdocument.addEventListener('DOMContentLoaded', () => {\n\t\t\t\tCombatTracker.init();\n\t\t\t});\n\t\t</script>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			return nil
 		})
-		templ_7745c5c3_Err = components.Layout("Combat Tracker - Keeper Tools").Render(templ.WithChildren(ctx, templ_7745c5c3_Var6), templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = components.Layout("Combat Tracker - Keeper Tools").Render(templ.WithChildren(ctx, templ_7745c5c3_Var13), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
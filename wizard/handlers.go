@@ -29,7 +29,8 @@ func (h *Handler) BaseStep(w http.ResponseWriter, r *http.Request) {
 
 	component := views.BaseStep(nil)
 	if key != "" && key != "new" {
-		investigator, err := h.store.GetInvestigator(r, key)
+		ctx := r.Context()
+		investigator, err := h.store.GetInvestigator(ctx, storage.OwnerFromContext(ctx), key)
 		if err != nil {
 			h.logger.Printf("Failed to get investigator: %v", err)
 			// Continue with nil investigator for new character
@@ -49,7 +50,8 @@ func (h *Handler) AttrStep(w http.ResponseWriter, r *http.Request) {
 	params := r.Context().Value("params").([]string)
 	key := params[0]
 
-	investigator, err := h.store.GetInvestigator(r, key)
+	ctx := r.Context()
+	investigator, err := h.store.GetInvestigator(ctx, storage.OwnerFromContext(ctx), key)
 	if err != nil {
 		h.logger.Printf("Failed to get investigator: %v", err)
 		http.Error(w, "Investigator not found", http.StatusNotFound)
@@ -68,7 +70,8 @@ func (h *Handler) SkillStep(w http.ResponseWriter, r *http.Request) {
 	params := r.Context().Value("params").([]string)
 	key := params[0]
 
-	investigator, err := h.store.GetInvestigator(r, key)
+	ctx := r.Context()
+	investigator, err := h.store.GetInvestigator(ctx, storage.OwnerFromContext(ctx), key)
 	if err != nil {
 		h.logger.Printf("Failed to get investigator: %v", err)
 		http.Error(w, "Investigator not found", http.StatusNotFound)
@@ -87,14 +90,19 @@ func (h *Handler) TalentStep(w http.ResponseWriter, r *http.Request) {
 	params := r.Context().Value("params").([]string)
 	key := params[0]
 
-	investigator, err := h.store.GetInvestigator(r, key)
+	ctx := r.Context()
+	investigator, err := h.store.GetInvestigator(ctx, storage.OwnerFromContext(ctx), key)
 	if err != nil {
 		h.logger.Printf("Failed to get investigator: %v", err)
 		http.Error(w, "Investigator not found", http.StatusNotFound)
 		return
 	}
 
+	// Classic investigators have no talents, so the wizard moves on to the attributes
 	component := views.TalentStep(investigator)
+	if investigator.Archetype == nil {
+		component = views.AttrStep(investigator)
+	}
 	if err := component.Render(r.Context(), w); err != nil {
 		h.logger.Printf("Failed to render talent step: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)